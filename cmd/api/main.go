@@ -3,20 +3,40 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"project-simple/internal/cache"
 	"project-simple/internal/config"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/graphql"
+	"project-simple/internal/grpcserver"
 	"project-simple/internal/handler"
+	"project-simple/internal/health"
 	"project-simple/internal/infrastructure/database"
+	"project-simple/internal/jobs"
+	"project-simple/internal/logging"
+	"project-simple/internal/metrics"
+	"project-simple/internal/outbox"
+	"project-simple/internal/ratelimit"
 	"project-simple/internal/repository"
+	"project-simple/internal/requestctx"
 	"project-simple/internal/router"
 	"project-simple/internal/service"
+	"project-simple/internal/stream"
+	"project-simple/internal/tracing"
+	"project-simple/internal/validation"
+	"project-simple/internal/webhook"
+	"project-simple/pkg/response"
+	"sync"
 	"syscall"
 	"time"
 
 	_ "project-simple/docs" // Import swagger docs
+
+	"github.com/redis/go-redis/v9"
 )
 
 // @title Car Management API
@@ -37,35 +57,165 @@ import (
 func main() {
 	// Load configuration
 	cfg := config.Load()
-	log.Println("Configuration loaded successfully")
 
-	// Initialize database
-	db, err := database.NewDatabase(cfg)
+	// Initialize structured logging
+	if err := logging.Setup(os.Stdout, cfg.Log.Format, cfg.Log.Level); err != nil {
+		fatal("Failed to initialize logging", err)
+	}
+	if err := cfg.Server.Validate(); err != nil {
+		fatal("Invalid server configuration", err)
+	}
+	if err := cfg.Admin.Validate(); err != nil {
+		fatal("Invalid admin configuration", err)
+	}
+	if err := cfg.RateLimit.Validate(); err != nil {
+		fatal("Invalid rate limit configuration", err)
+	}
+	switch cfg.Server.ErrorFormat {
+	case "legacy", "problem":
+		response.SetProblemDetailsDefault(cfg.Server.ErrorFormat == "problem")
+	default:
+		fatal("Invalid error format", fmt.Errorf("ERROR_FORMAT must be legacy or problem, got %q", cfg.Server.ErrorFormat))
+	}
+	if err := validation.Setup(); err != nil {
+		fatal("Failed to initialize validation messages", err)
+	}
+	slog.Info("Configuration loaded successfully")
+
+	// Initialize tracing before anything that creates spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		fatal("Failed to initialize tracing", err)
 	}
-	log.Println("Database initialized successfully")
+	slog.Info("Tracing initialized", slog.String("exporter", cfg.Tracing.Exporter))
 
-	// Run migrations
-	if err := db.AutoMigrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// Initialize database
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		fatal("Failed to initialize database", err)
 	}
+	slog.Info("Database initialized successfully")
 
 	// Initialize repositories
 	carRepo := repository.NewCarRepository(db.DB)
+	carVersionRepo := repository.NewCarVersionRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	jobRepo := repository.NewJobRepository(db.DB)
+	transactor := repository.NewTransactor(db.DB)
+
+	// Register database and business metrics
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		fatal("Failed to get database instance", err)
+	}
+	metrics.RegisterDB(sqlDB)
+	metrics.RegisterCars(carRepo)
+
+	// Register health checks. Optional checks only warn, so a slow relay or a
+	// filling disk never takes every instance out of rotation at once.
+	healthRegistry := health.NewRegistry(cfg.Health.CheckTimeout, cfg.Health.CacheTTL)
+	healthRegistry.Register(health.Registration{
+		Name:    "database",
+		Checker: health.Database(db.DB),
+		Probes:  []health.Probe{health.Readiness, health.Startup},
+	})
+	healthRegistry.Register(health.Registration{
+		Name:    "migrations",
+		Checker: health.Migrations(db.SchemaVersion, database.SchemaHead),
+		Probes:  []health.Probe{health.Readiness, health.Startup},
+	})
+	healthRegistry.Register(health.Registration{
+		Name:     "outbox_lag",
+		Checker:  health.OutboxLag(outboxRepo, cfg.Health.MaxOutboxLag),
+		Probes:   []health.Probe{health.Readiness},
+		Optional: true,
+	})
+	healthRegistry.Register(health.Registration{
+		Name:     "outbox_dead_letters",
+		Checker:  health.OutboxDeadLetters(outboxRepo),
+		Probes:   []health.Probe{health.Readiness},
+		Optional: true,
+	})
+	healthRegistry.Register(health.Registration{
+		Name:     "disk",
+		Checker:  health.DiskSpace(cfg.Health.DiskPath, uint64(cfg.Health.DiskMinFreeMB)<<20),
+		Probes:   []health.Probe{health.Readiness},
+		Optional: true,
+	})
+
+	// Redis is optional: when configured it shares cached reads and rate
+	// limits between instances. While it is down, reads go to the database.
+	var redisClient *redis.Client
+	var sharedCache *cache.RedisStore
+	if cfg.Redis.Addr != "" {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:         cfg.Redis.Addr,
+			Password:     cfg.Redis.Password,
+			DB:           cfg.Redis.DB,
+			DialTimeout:  cfg.Redis.Timeout,
+			ReadTimeout:  cfg.Redis.Timeout,
+			WriteTimeout: cfg.Redis.Timeout,
+			MaxRetries:   -1,
+		})
+		defer redisClient.Close()
+
+		sharedCache = cache.NewRedisStore(redisClient, cfg.Cache.TTL, cfg.Redis.RetryInterval)
+		healthRegistry.Register(health.Registration{
+			Name: "redis",
+			Checker: health.CheckerFunc(func(ctx context.Context) error {
+				return redisClient.Ping(ctx).Err()
+			}),
+			Probes:   []health.Probe{health.Readiness},
+			Optional: true,
+		})
+	}
+
+	rateLimitStore, runRateLimitCleanup, err := newRateLimitStore(cfg.RateLimit.Store, db, redisClient)
+	if err != nil {
+		fatal("Failed to initialize rate limiting", err)
+	}
 
 	// Initialize services
-	carService := service.NewCarService(carRepo)
+	carService := service.NewCarService(carRepo, carVersionRepo, auditRepo, outboxRepo, transactor)
+	if sharedCache != nil {
+		carService = service.NewCachedCarService(carService, cfg.Cache.Size, cfg.Cache.TTL, sharedCache)
+	} else if cfg.Cache.Size > 0 {
+		carService = service.NewCachedCarService(carService, cfg.Cache.Size, cfg.Cache.TTL, nil)
+	}
+	carService = service.NewTracedCarService(carService)
+	auditService := service.NewAuditService(auditRepo)
+	webhookService := service.NewWebhookService(webhookRepo)
+	jobService := service.NewJobService(jobRepo, cfg.Jobs.MaxAttempts)
 
 	// Initialize handlers
-	carHandler := handler.NewCarHandler(carService)
-	healthHandler := handler.NewHealthHandler(db.DB)
+	carHandler := handler.NewCarHandler(carService, jobService, cfg.Export.WriteTimeout)
+	auditHandler := handler.NewAuditHandler(auditService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	jobHandler := handler.NewJobHandler(jobService)
+	graphqlServer, err := graphql.NewServer(carService, graphql.Config{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	})
+	if err != nil {
+		fatal("Failed to initialize GraphQL", err)
+	}
+	graphqlHandler := handler.NewGraphQLHandler(graphqlServer, cfg.GraphQL.GraphiQL)
+	broker := stream.NewBroker(cfg.Stream.BufferSize)
+	eventStreamHandler := handler.NewEventStreamHandler(broker, cfg.Stream.HeartbeatInterval)
+	healthHandler := handler.NewHealthHandler(db.DB, healthRegistry)
+	adminHandler := handler.NewAdminHandler(cfg, db.QueryStats)
 
 	// Setup router
-	r := router.SetupRouter(cfg, carHandler, healthHandler)
+	r := router.SetupRouter(cfg, rateLimitStore, carHandler, auditHandler, webhookHandler, jobHandler, graphqlHandler, eventStreamHandler, healthHandler)
 
 	// Configure HTTP server with timeouts
 	serverAddr := fmt.Sprintf(":%s", cfg.Server.Port)
+
+	// Every request context derives from this one, so requests still running
+	// at the end of the shutdown grace period can be cancelled with a cause
+	requestsCtx, cancelRequests := context.WithCancelCause(context.Background())
 	srv := &http.Server{
 		Addr:           serverAddr,
 		Handler:        r,
@@ -73,41 +223,245 @@ func main() {
 		WriteTimeout:   10 * time.Second,
 		IdleTimeout:    120 * time.Second,
 		MaxHeaderBytes: 1 << 20, // 1 MB
+		BaseContext:    func(net.Listener) context.Context { return requestsCtx },
 	}
+	// Open event streams never finish on their own; end them on shutdown
+	srv.RegisterOnShutdown(broker.Close)
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Server starting on %s", serverAddr)
-		log.Printf("Swagger documentation available at http://localhost%s/swagger/index.html", serverAddr)
+		slog.Info("Server starting", slog.String("addr", serverAddr))
+		slog.Info("Swagger documentation available", slog.String("url", fmt.Sprintf("http://localhost%s/swagger/index.html", serverAddr)))
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			fatal("Failed to start server", err)
+		}
+	}()
+
+	// Serve metrics on their own port so they are never exposed with the API
+	metricsAddr := fmt.Sprintf(":%s", cfg.Metrics.Port)
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	metricsSrv := &http.Server{
+		Addr:              metricsAddr,
+		Handler:           metricsMux,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	go func() {
+		slog.Info("Metrics available", slog.String("url", fmt.Sprintf("http://localhost%s/metrics", metricsAddr)))
+
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Failed to start metrics server", err)
+		}
+	}()
+
+	// Serve profiling and debug endpoints on the admin address
+	adminSrv := &http.Server{
+		Addr:              cfg.Admin.Addr,
+		Handler:           router.SetupAdminRouter(cfg, adminHandler),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      90 * time.Second,
+	}
+
+	go func() {
+		slog.Info("Admin server starting", slog.String("addr", cfg.Admin.Addr), slog.Bool("auth", cfg.Admin.Token != ""))
+
+		if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Failed to start admin server", err)
+		}
+	}()
+
+	// Serve gRPC on its own port. Its rate limit policies share the REST
+	// buckets, so a client gets one allowance whichever protocol it uses.
+	grpcServer := grpcserver.NewServer(carService, rateLimitStore, grpcserver.Config{
+		AuthToken: cfg.GRPC.AuthToken,
+		RateLimits: []grpcserver.RateLimitPolicy{
+			{
+				Name:         "writes",
+				Limit:        ratelimit.Limit{Requests: cfg.RateLimit.WriteRequests, Period: cfg.RateLimit.WritePeriod},
+				Methods:      grpcserver.WriteMethods,
+				PerPrincipal: true,
+			},
+			{
+				Name:  "default",
+				Limit: ratelimit.Limit{Requests: cfg.RateLimit.Requests, Period: cfg.RateLimit.Period},
+			},
+		},
+	})
+	grpcAddr := fmt.Sprintf(":%s", cfg.GRPC.Port)
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		fatal("Failed to listen for gRPC", err)
+	}
+
+	go func() {
+		slog.Info("gRPC server starting", slog.String("addr", grpcAddr), slog.Bool("auth", cfg.GRPC.AuthToken != ""))
+
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			fatal("Failed to start gRPC server", err)
 		}
 	}()
 
+	// Run migrations once the probes are served: /startupz and /readyz fail
+	// until they complete, so slow migrations do not get the instance killed
+	if err := db.AutoMigrate(); err != nil {
+		fatal("Failed to run migrations", err)
+	}
+
+	// Start background workers: the outbox relay, the webhook dispatcher and
+	// the listener feeding the event stream
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	sinks := outbox.MultiSink{outbox.LogSink{}, webhook.NewSink(webhookRepo), stream.NewSink(outboxRepo)}
+	relay := outbox.NewRelay(outboxRepo, transactor, sinks, outbox.RelayConfig{
+		BatchSize:    cfg.Outbox.BatchSize,
+		PollInterval: cfg.Outbox.PollInterval,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		BaseBackoff:  cfg.Outbox.BaseBackoff,
+		MaxBackoff:   cfg.Outbox.MaxBackoff,
+	})
+	workers.Go(func() { relay.Run(workersCtx) })
+	slog.Info("Outbox relay started")
+
+	dispatcher := webhook.NewDispatcher(webhookRepo, transactor, webhook.NewClient(cfg.Webhook.RequestTimeout), webhook.DispatcherConfig{
+		MaxAttempts:  cfg.Webhook.MaxAttempts,
+		BaseBackoff:  cfg.Webhook.BaseBackoff,
+		MaxBackoff:   cfg.Webhook.MaxBackoff,
+		BatchSize:    cfg.Webhook.BatchSize,
+		PollInterval: cfg.Webhook.PollInterval,
+		// Long enough to time out on every delivery of a batch
+		Lease: time.Duration(cfg.Webhook.BatchSize+1) * cfg.Webhook.RequestTimeout,
+	})
+	workers.Go(func() { dispatcher.Run(workersCtx) })
+	slog.Info("Webhook dispatcher started")
+
+	listener := stream.NewListener(cfg.Database.GetDSN(), broker)
+	workers.Go(func() { listener.Run(workersCtx) })
+	slog.Info("Event stream listener started")
+
+	if runRateLimitCleanup != nil {
+		workers.Go(func() { runRateLimitCleanup(workersCtx) })
+		slog.Info("Rate limit cleanup started", slog.String("store", cfg.RateLimit.Store))
+	}
+
+	if sharedCache != nil {
+		workers.Go(func() { sharedCache.Run(workersCtx) })
+		slog.Info("Cache invalidation subscriber started")
+	}
+
+	// Start the job workers. They stop separately from the other background
+	// workers: running jobs drain first, while the outbox relay still
+	// delivers the events they publish.
+	pool := jobs.NewPool(jobRepo, jobs.Config{
+		Concurrency:  cfg.Jobs.Concurrency,
+		PollInterval: cfg.Jobs.PollInterval,
+		Lease:        cfg.Jobs.Lease,
+		BaseBackoff:  cfg.Jobs.BaseBackoff,
+		MaxBackoff:   cfg.Jobs.MaxBackoff,
+		DrainTimeout: cfg.Jobs.DrainTimeout,
+	})
+	pool.Register(dto.JobKindCarImport, service.CarImportJob(carService))
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobWorkers sync.WaitGroup
+	jobWorkers.Go(func() { pool.Run(jobsCtx) })
+	slog.Info("Job workers started", slog.Int("concurrency", cfg.Jobs.Concurrency))
+
 	// Setup graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	// Wait for interrupt signal
 	<-quit
-	log.Println("Shutting down server gracefully...")
+	slog.Info("Shutting down server gracefully")
+
+	// Fail readiness first and give load balancers time to stop routing here
+	healthRegistry.SetShuttingDown()
+	grpcServer.Drain()
+	slog.Info("Readiness set to failing, draining", slog.Duration("delay", cfg.Health.ShutdownDelay))
+	time.Sleep(cfg.Health.ShutdownDelay)
+
+	// Stop claiming jobs; running ones drain alongside in-flight requests
+	stopJobs()
 
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Cancel requests still in flight shortly before the deadline, so their
+	// queries stop and they can answer 503 before connections are dropped
+	forceCancel := time.AfterFunc(25*time.Second, func() {
+		cancelRequests(requestctx.ErrServerShutdown)
+	})
+	defer forceCancel.Stop()
+
+	// Stop gRPC alongside HTTP, so neither waits for the other's calls
+	grpcStopped := make(chan error, 1)
+	go func() { grpcStopped <- grpcServer.Shutdown(ctx) }()
+
 	// Shutdown HTTP server
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
+		slog.Error("Server forced to shutdown", slog.Any("error", err))
+	}
+	if err := <-grpcStopped; err != nil {
+		slog.Error("gRPC server forced to shutdown", slog.Any("error", err))
+	}
+	if err := metricsSrv.Shutdown(ctx); err != nil {
+		slog.Error("Metrics server forced to shutdown", slog.Any("error", err))
+	}
+	if err := adminSrv.Shutdown(ctx); err != nil {
+		slog.Error("Admin server forced to shutdown", slog.Any("error", err))
 	}
 
+	// Wait for running jobs to drain or be queued again
+	jobWorkers.Wait()
+	slog.Info("Job workers stopped")
+
+	// Stop background workers before closing the database they use
+	stopWorkers()
+	workers.Wait()
+	slog.Info("Background workers stopped")
+
 	// Close database connections
 	if err := db.Close(); err != nil {
-		log.Printf("Error closing database: %v", err)
+		slog.Error("Error closing database", slog.Any("error", err))
 	} else {
-		log.Println("Database connections closed successfully")
+		slog.Info("Database connections closed successfully")
+	}
+
+	// Flush spans still buffered by the exporter
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Error flushing traces", slog.Any("error", err))
+	}
+
+	slog.Info("Server exited successfully")
+}
+
+// newRateLimitStore returns the rate limit store named by kind, and the loop
+// that cleans it up if it needs one
+func newRateLimitStore(kind string, db *database.Database, redisClient *redis.Client) (ratelimit.Store, func(context.Context), error) {
+	switch kind {
+	case "memory":
+		store := ratelimit.NewMemoryStore()
+		return store, store.Run, nil
+	case "postgres":
+		store := ratelimit.NewPostgresStore(db.DB)
+		return store, store.Run, nil
+	case "redis":
+		if redisClient == nil {
+			return nil, nil, fmt.Errorf("rate limit store %q requires REDIS_ADDR", kind)
+		}
+		return ratelimit.NewRedisStore(redisClient), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown rate limit store %q", kind)
 	}
+}
 
-	log.Println("Server exited successfully")
+// fatal logs err and exits; it stands in for log.Fatal with structured output
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
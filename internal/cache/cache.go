@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"project-simple/internal/metrics"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache is a read-through cache: misses call the loader once per key no
+// matter how many requests are waiting on it, and the result is kept in an
+// LRU until it expires or is invalidated.
+//
+// Loads race with writers. A load that read the database before a write
+// committed must not store its result after the write invalidated the key,
+// or the stale value would be served until the TTL expires. Every
+// invalidation therefore bumps a generation: a load only stores its result
+// if no invalidation happened since it started, and readers arriving after
+// an invalidation start a new load instead of joining one already running.
+type Cache[V any] struct {
+	name  string
+	lru   *LRU[string, V]
+	group singleflight.Group
+
+	mu         sync.Mutex
+	generation uint64
+}
+
+func New[V any](name string, capacity int, ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		name: name,
+		lru:  NewLRU[string, V](capacity, ttl),
+	}
+}
+
+// Get returns the cached value for key, calling load on a miss. Errors are
+// not cached. The load runs detached from the caller's cancellation, since
+// other requests may be waiting on it; a caller that gives up gets ctx.Err().
+func (c *Cache[V]) Get(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	if value, ok := c.lru.Get(key); ok {
+		metrics.CacheRequests.WithLabelValues(c.name, "hit").Inc()
+		return value, nil
+	}
+	metrics.CacheRequests.WithLabelValues(c.name, "miss").Inc()
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	loadCtx := context.WithoutCancel(ctx)
+	flight := c.group.DoChan(key+"@"+strconv.FormatUint(generation, 10), func() (any, error) {
+		value, err := load(loadCtx)
+		if err != nil {
+			return value, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.lru.Set(key, value)
+		}
+		c.mu.Unlock()
+
+		return value, nil
+	})
+
+	select {
+	case res := <-flight:
+		if res.Err != nil {
+			var zero V
+			return zero, res.Err
+		}
+		return res.Val.(V), nil
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Invalidate removes keys and prevents loads already running from storing
+// what they read
+func (c *Cache[V]) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, key := range keys {
+		c.lru.Delete(key)
+	}
+}
+
+// InvalidateAll removes every entry and prevents loads already running from
+// storing what they read
+func (c *Cache[V]) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.lru.Purge()
+}
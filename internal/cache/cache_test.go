@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"project-simple/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("Should load once and then serve hits", func(t *testing.T) {
+		c := New[int]("test_hits", 10, time.Minute)
+		hits := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("test_hits", "hit"))
+		misses := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("test_hits", "miss"))
+		var loads atomic.Int32
+		load := func(context.Context) (int, error) {
+			loads.Add(1)
+			return 42, nil
+		}
+
+		for range 3 {
+			v, err := c.Get(context.Background(), "k", load)
+			require.NoError(t, err)
+			assert.Equal(t, 42, v)
+		}
+
+		assert.Equal(t, int32(1), loads.Load())
+		assert.Equal(t, hits+2, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("test_hits", "hit")))
+		assert.Equal(t, misses+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("test_hits", "miss")))
+	})
+
+	t.Run("Should collapse concurrent misses into one load", func(t *testing.T) {
+		c := New[int]("test_singleflight", 10, time.Minute)
+		var loads atomic.Int32
+		release := make(chan struct{})
+		load := func(context.Context) (int, error) {
+			loads.Add(1)
+			<-release
+			return 1, nil
+		}
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				v, err := c.Get(context.Background(), "k", load)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, v)
+			})
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+	})
+
+	t.Run("Should not cache errors", func(t *testing.T) {
+		c := New[int]("test_errors", 10, time.Minute)
+		failing := errors.New("boom")
+
+		_, err := c.Get(context.Background(), "k", func(context.Context) (int, error) { return 0, failing })
+		require.ErrorIs(t, err, failing)
+
+		v, err := c.Get(context.Background(), "k", func(context.Context) (int, error) { return 7, nil })
+		require.NoError(t, err)
+		assert.Equal(t, 7, v)
+	})
+
+	t.Run("Should not store a load that raced with an invalidation", func(t *testing.T) {
+		c := New[string]("test_race", 10, time.Minute)
+		loading := make(chan struct{})
+		release := make(chan struct{})
+
+		done := make(chan string)
+		go func() {
+			v, _ := c.Get(context.Background(), "k", func(context.Context) (string, error) {
+				close(loading)
+				<-release
+				return "stale", nil
+			})
+			done <- v
+		}()
+
+		// The write commits and invalidates while the old value is in flight
+		<-loading
+		c.Invalidate("k")
+
+		// A read after the write must not join the old load
+		v, err := c.Get(context.Background(), "k", func(context.Context) (string, error) { return "fresh", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "fresh", v)
+
+		close(release)
+		assert.Equal(t, "stale", <-done)
+
+		v, err = c.Get(context.Background(), "k", func(context.Context) (string, error) { return "reloaded", nil })
+		require.NoError(t, err)
+		assert.Equal(t, "fresh", v)
+	})
+
+	t.Run("Should return when the caller gives up", func(t *testing.T) {
+		c := New[int]("test_cancel", 10, time.Minute)
+		release := make(chan struct{})
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.Get(ctx, "k", func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
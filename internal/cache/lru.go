@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a size-bounded map that evicts the least recently used entry when
+// full. Entries older than the TTL are treated as absent. It is safe for
+// concurrent use.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	items    map[K]*list.Element
+	now      func() time.Time
+}
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+func NewLRU[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		items:    make(map[K]*list.Element, capacity),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value stored under key unless it is missing or expired
+func (l *LRU[K, V]) Get(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zero V
+	elem, ok := l.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := elem.Value.(*lruEntry[K, V])
+	if !l.now().Before(entry.expiresAt) {
+		l.remove(elem)
+		return zero, false
+	}
+
+	l.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores value under key, evicting the least recently used entry if the
+// cache is full
+func (l *LRU[K, V]) Set(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt := l.now().Add(l.ttl)
+	if elem, ok := l.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(elem)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+}
+
+// Delete removes key from the cache
+func (l *LRU[K, V]) Delete(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.items[key]; ok {
+		l.remove(elem)
+	}
+}
+
+// Purge removes every entry
+func (l *LRU[K, V]) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.order.Init()
+	clear(l.items)
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (l *LRU[K, V]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *LRU[K, V]) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.items, elem.Value.(*lruEntry[K, V]).key)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	t.Run("Should evict the least recently used entry when full", func(t *testing.T) {
+		lru := NewLRU[string, int](2, time.Minute)
+		lru.Set("a", 1)
+		lru.Set("b", 2)
+		lru.Get("a")
+		lru.Set("c", 3)
+
+		_, ok := lru.Get("b")
+		assert.False(t, ok)
+		v, ok := lru.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("Should expire entries after the TTL", func(t *testing.T) {
+		now := time.Now()
+		lru := NewLRU[string, int](2, time.Minute)
+		lru.now = func() time.Time { return now }
+		lru.Set("a", 1)
+
+		now = now.Add(59 * time.Second)
+		_, ok := lru.Get("a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Second)
+		_, ok = lru.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, lru.Len())
+	})
+
+	t.Run("Should replace an existing value", func(t *testing.T) {
+		lru := NewLRU[string, int](2, time.Minute)
+		lru.Set("a", 1)
+		lru.Set("a", 2)
+
+		v, _ := lru.Get("a")
+		assert.Equal(t, 2, v)
+		assert.Equal(t, 1, lru.Len())
+	})
+
+	t.Run("Should delete and purge", func(t *testing.T) {
+		lru := NewLRU[string, int](3, time.Minute)
+		lru.Set("a", 1)
+		lru.Set("b", 2)
+		lru.Delete("a")
+
+		_, ok := lru.Get("a")
+		assert.False(t, ok)
+
+		lru.Purge()
+		assert.Equal(t, 0, lru.Len())
+	})
+}
@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// keyVersion prefixes every key. Bump it when the encoding of cached
+	// values changes, so a rolling deploy never reads the other format.
+	keyVersion = "v1"
+
+	// InvalidationChannel carries the tags invalidated by any instance
+	InvalidationChannel = "carapi:cache:invalidate"
+
+	// resubscribeDelay is how long the subscriber waits after losing its
+	// connection before subscribing again
+	resubscribeDelay = 2 * time.Second
+)
+
+// RedisStore is a Store on a Redis server, or anything speaking RESP.
+//
+// Each tag has a version key holding a random stamp; an entry lives under its
+// key suffixed with the stamps of its tags. Invalidating a tag replaces its
+// stamp, so entries stored under the old one, including those written by
+// loads that read the database before the invalidation, are never read again
+// and expire on their own.
+//
+// After a failed command the store reports ErrUnavailable without contacting
+// Redis until retryInterval has passed, so an outage costs callers one
+// timeout rather than one per request.
+type RedisStore struct {
+	client        *redis.Client
+	prefix        string
+	ttl           time.Duration
+	retryInterval time.Duration
+
+	unavailableUntil atomic.Int64
+
+	mu       sync.Mutex
+	handlers []func(tags []string)
+}
+
+func NewRedisStore(client *redis.Client, ttl, retryInterval time.Duration) *RedisStore {
+	return &RedisStore{
+		client:        client,
+		prefix:        "carapi:cache:" + keyVersion + ":",
+		ttl:           ttl,
+		retryInterval: retryInterval,
+	}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string, tags ...string) ([]byte, Version, error) {
+	if !s.available() {
+		return nil, "", ErrUnavailable
+	}
+
+	version := Version("")
+	if len(tags) > 0 {
+		tagKeys := make([]string, len(tags))
+		for i, tag := range tags {
+			tagKeys[i] = s.tagKey(tag)
+		}
+		stamps, err := s.client.MGet(ctx, tagKeys...).Result()
+		if err != nil {
+			return nil, "", s.fail(ctx, err)
+		}
+		version = versionOf(stamps)
+	}
+
+	data, err := s.client.Get(ctx, s.entryKey(key, version)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, version, ErrMiss
+	}
+	if err != nil {
+		return nil, "", s.fail(ctx, err)
+	}
+	return data, version, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, version Version, value []byte) error {
+	if !s.available() {
+		return ErrUnavailable
+	}
+
+	if err := s.client.Set(ctx, s.entryKey(key, version), value, s.ttl).Err(); err != nil {
+		return s.fail(ctx, err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Invalidate(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if !s.available() {
+		return ErrUnavailable
+	}
+
+	payload, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+
+	// Stamps outlive every entry stored under them: once a stamp expires the
+	// tag reads as unversioned again, and no entry from that era may remain
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			pipe.Set(ctx, s.tagKey(tag), uuid.NewString(), 2*s.ttl)
+		}
+		pipe.Publish(ctx, InvalidationChannel, payload)
+		return nil
+	})
+	if err != nil {
+		return s.fail(ctx, err)
+	}
+	return nil
+}
+
+func (s *RedisStore) OnInvalidate(fn func(tags []string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers = append(s.handlers, fn)
+}
+
+// Run delivers invalidations broadcast by every instance to the handlers
+// until ctx is cancelled. Each (re)subscription first tells handlers to drop
+// everything, since invalidations sent while disconnected were lost.
+func (s *RedisStore) Run(ctx context.Context) {
+	pubsub := s.client.Subscribe(ctx, InvalidationChannel)
+	// A blocked Receive does not watch ctx; closing the connection ends it
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("Cache invalidation subscriber error", slog.Any("error", err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(resubscribeDelay):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			if msg.Kind == "subscribe" {
+				s.dispatch(nil)
+			}
+		case *redis.Message:
+			var tags []string
+			if err := json.Unmarshal([]byte(msg.Payload), &tags); err != nil {
+				slog.Warn("Cache invalidation subscriber: discarding malformed message", slog.Any("error", err))
+				continue
+			}
+			s.dispatch(tags)
+		}
+	}
+}
+
+func (s *RedisStore) dispatch(tags []string) {
+	s.mu.Lock()
+	handlers := s.handlers
+	s.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(tags)
+	}
+}
+
+func (s *RedisStore) available() bool {
+	return time.Now().UnixNano() >= s.unavailableUntil.Load()
+}
+
+// fail marks Redis unavailable for retryInterval, unless the command only
+// failed because the caller gave up
+func (s *RedisStore) fail(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+
+	until := time.Now().Add(s.retryInterval).UnixNano()
+	if previous := s.unavailableUntil.Swap(until); time.Now().UnixNano() >= previous {
+		slog.Warn("Cache store unavailable, reading from the database", slog.Duration("retry_in", s.retryInterval), slog.Any("error", err))
+	}
+	return errors.Join(ErrUnavailable, err)
+}
+
+func (s *RedisStore) tagKey(tag string) string {
+	return s.prefix + "tag:" + tag
+}
+
+func (s *RedisStore) entryKey(key string, version Version) string {
+	if version == "" {
+		return s.prefix + key
+	}
+	return s.prefix + key + "@" + string(version)
+}
+
+// versionOf joins tag stamps; a tag never invalidated has stamp "0"
+func versionOf(stamps []any) Version {
+	parts := make([]string, len(stamps))
+	for i, stamp := range stamps {
+		if str, ok := stamp.(string); ok {
+			parts[i] = str
+		} else {
+			parts[i] = "0"
+		}
+	}
+	return Version(strings.Join(parts, "."))
+}
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisStore(t *testing.T, server *miniredis.Miniredis) *RedisStore {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{
+		Addr:        server.Addr(),
+		DialTimeout: 100 * time.Millisecond,
+		ReadTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStore(client, time.Minute, time.Minute)
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should miss, store and hit", func(t *testing.T) {
+		store := newRedisStore(t, miniredis.RunT(t))
+
+		_, version, err := store.Get(ctx, "car:1", "car:1")
+		require.ErrorIs(t, err, ErrMiss)
+
+		require.NoError(t, store.Set(ctx, "car:1", version, []byte(`"civic"`)))
+
+		data, _, err := store.Get(ctx, "car:1", "car:1")
+		require.NoError(t, err)
+		assert.Equal(t, `"civic"`, string(data))
+	})
+
+	t.Run("Should make entries unreachable when a tag is invalidated", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		store := newRedisStore(t, server)
+
+		_, version, _ := store.Get(ctx, "cars:page:1", "cars")
+		require.NoError(t, store.Set(ctx, "cars:page:1", version, []byte(`[]`)))
+		_, other, _ := store.Get(ctx, "car:2", "car:2")
+		require.NoError(t, store.Set(ctx, "car:2", other, []byte(`"golf"`)))
+
+		require.NoError(t, store.Invalidate(ctx, "cars"))
+
+		_, _, err := store.Get(ctx, "cars:page:1", "cars")
+		assert.ErrorIs(t, err, ErrMiss)
+		_, _, err = store.Get(ctx, "car:2", "car:2")
+		assert.NoError(t, err, "other tags are untouched")
+		assert.Greater(t, server.TTL("carapi:cache:v1:tag:cars"), time.Minute)
+	})
+
+	t.Run("Should never serve a value loaded before an invalidation", func(t *testing.T) {
+		store := newRedisStore(t, miniredis.RunT(t))
+
+		// A reader misses and loads the old row from the database...
+		_, version, err := store.Get(ctx, "car:1", "car:1")
+		require.ErrorIs(t, err, ErrMiss)
+
+		// ...a writer commits and invalidates...
+		require.NoError(t, store.Invalidate(ctx, "car:1"))
+
+		// ...and the reader stores what it read
+		require.NoError(t, store.Set(ctx, "car:1", version, []byte(`"stale"`)))
+
+		_, _, err = store.Get(ctx, "car:1", "car:1")
+		assert.ErrorIs(t, err, ErrMiss)
+	})
+
+	t.Run("Should broadcast invalidations to every instance", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		writer := newRedisStore(t, server)
+		reader := newRedisStore(t, server)
+
+		var mu sync.Mutex
+		var received [][]string
+		reader.OnInvalidate(func(tags []string) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, tags)
+		})
+
+		runCtx, stop := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			reader.Run(runCtx)
+			close(done)
+		}()
+		defer func() {
+			stop()
+			<-done
+		}()
+
+		// Subscribing first drops everything, since earlier broadcasts were missed
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(received) == 1 && received[0] == nil
+		}, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, writer.Invalidate(ctx, "car:1", "cars"))
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(received) == 2
+		}, time.Second, 10*time.Millisecond)
+		mu.Lock()
+		assert.Equal(t, []string{"car:1", "cars"}, received[1])
+		mu.Unlock()
+	})
+
+	t.Run("Should report unavailable without retrying until the interval passes", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		store := newRedisStore(t, server)
+		server.Close()
+
+		_, _, err := store.Get(ctx, "car:1", "car:1")
+		require.ErrorIs(t, err, ErrUnavailable)
+
+		start := time.Now()
+		_, _, err = store.Get(ctx, "car:1", "car:1")
+		require.ErrorIs(t, err, ErrUnavailable)
+		assert.Less(t, time.Since(start), 10*time.Millisecond)
+		assert.ErrorIs(t, store.Invalidate(ctx, "car:1"), ErrUnavailable)
+	})
+}
+
+func TestReadThrough(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should load once and then serve from the store", func(t *testing.T) {
+		store := newRedisStore(t, miniredis.RunT(t))
+		loads := 0
+		load := func(context.Context) (string, error) {
+			loads++
+			return "civic", nil
+		}
+
+		for range 3 {
+			v, err := ReadThrough(ctx, store, "test_shared", "car:1", []string{"car:1"}, load)
+			require.NoError(t, err)
+			assert.Equal(t, "civic", v)
+		}
+		assert.Equal(t, 1, loads)
+	})
+
+	t.Run("Should fall back to the loader when the store is down", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		store := newRedisStore(t, server)
+		server.Close()
+
+		v, err := ReadThrough(ctx, store, "test_shared", "car:1", []string{"car:1"}, func(context.Context) (string, error) {
+			return "civic", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "civic", v)
+	})
+
+	t.Run("Should not store loader errors", func(t *testing.T) {
+		store := newRedisStore(t, miniredis.RunT(t))
+		failing := errors.New("boom")
+
+		_, err := ReadThrough(ctx, store, "test_shared", "car:1", []string{"car:1"}, func(context.Context) (string, error) {
+			return "", failing
+		})
+		require.ErrorIs(t, err, failing)
+
+		_, _, err = store.Get(ctx, "car:1", "car:1")
+		assert.ErrorIs(t, err, ErrMiss)
+	})
+
+	t.Run("Should load directly without a store", func(t *testing.T) {
+		v, err := ReadThrough(ctx, nil, "test_shared", "car:1", nil, func(context.Context) (int, error) {
+			return 7, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 7, v)
+	})
+}
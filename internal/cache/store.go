@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"project-simple/internal/logging"
+	"project-simple/internal/metrics"
+)
+
+// Store is a cache shared by every instance. Entries are stored under tags;
+// invalidating a tag makes every entry stored under it unreachable, on every
+// instance, and notifies the handlers subscribed on each instance so they can
+// drop what they keep locally.
+type Store interface {
+	// Get returns the value stored under key for the current version of tags.
+	// On ErrMiss the returned Version is where the loaded value belongs.
+	Get(ctx context.Context, key string, tags ...string) ([]byte, Version, error)
+	// Set stores value under key at version. If one of its tags was
+	// invalidated since the version was read, the value is never served.
+	Set(ctx context.Context, key string, version Version, value []byte) error
+	// Invalidate bumps tags and broadcasts the invalidation
+	Invalidate(ctx context.Context, tags ...string) error
+	// OnInvalidate registers fn to receive broadcast invalidations. A nil tag
+	// list means invalidations may have been missed and everything must go.
+	OnInvalidate(fn func(tags []string))
+}
+
+// Version identifies the state of an entry's tags when it was read
+type Version string
+
+// ReadThrough returns the value stored in store under key, calling load and
+// storing its result on a miss. A nil store or a store that fails is
+// bypassed: the value is loaded from the source of truth and not stored.
+func ReadThrough[V any](ctx context.Context, store Store, name, key string, tags []string, load func(ctx context.Context) (V, error)) (V, error) {
+	if store == nil {
+		return load(ctx)
+	}
+
+	data, version, err := store.Get(ctx, key, tags...)
+	switch {
+	case err == nil:
+		var value V
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.CacheRequests.WithLabelValues(name, "hit").Inc()
+			return value, nil
+		}
+		// An entry written in another format by an older release
+		logging.FromContext(ctx).Warn("Discarding undecodable cache entry", slog.String("key", key))
+	case errors.Is(err, ErrMiss):
+	default:
+		metrics.CacheRequests.WithLabelValues(name, "error").Inc()
+		return load(ctx)
+	}
+	metrics.CacheRequests.WithLabelValues(name, "miss").Inc()
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		if err := store.Set(ctx, key, version, data); err != nil && !errors.Is(err, ErrUnavailable) {
+			logging.FromContext(ctx).Warn("Failed to store cache entry", slog.String("key", key), slog.Any("error", err))
+		}
+	}
+	return value, nil
+}
+
+var (
+	ErrMiss        = errors.New("cache miss")
+	ErrUnavailable = errors.New("cache store unavailable")
+)
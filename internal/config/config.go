@@ -2,15 +2,35 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
+	Database  DatabaseConfig
+	Server    ServerConfig
+	Outbox    OutboxConfig
+	Webhook   WebhookConfig
+	Stream    StreamConfig
+	Metrics   MetricsConfig
+	Tracing   TracingConfig
+	Log       LogConfig
+	Health    HealthConfig
+	Admin     AdminConfig
+	Cache     CacheConfig
+	Redis     RedisConfig
+	RateLimit RateLimitConfig
+	Export    ExportConfig
+	Import    ImportConfig
+	Jobs      JobsConfig
+	GraphQL   GraphQLConfig
+	GRPC      GRPCConfig
+	OpenAPI   OpenAPIConfig
 }
 
 type DatabaseConfig struct {
@@ -20,18 +40,177 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// ReadTimeout and WriteTimeout bound each query and each write statement
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// SlowQueryThreshold is the duration above which a statement is logged
+	SlowQueryThreshold time.Duration
+	// QueryStatsWindow is how many recent durations per query shape feed the
+	// percentiles, and QueryStatsMaxShapes how many shapes are tracked
+	QueryStatsWindow    int
+	QueryStatsMaxShapes int
 }
 
 type ServerConfig struct {
 	Port           string
 	Env            string
 	AllowedOrigins []string
+	// TrustedProxies are the IPs or CIDRs whose X-Forwarded-For and
+	// X-Real-IP headers name the client; others' are ignored
+	TrustedProxies []string
+	// ErrorFormat is the error body for clients that do not ask for
+	// application/problem+json: legacy or problem
+	ErrorFormat string
+}
+
+type OutboxConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// MaxAttempts is how often an event is tried before it is dead-lettered
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+type RateLimitConfig struct {
+	// Store holds the buckets: memory (per instance), postgres or redis
+	Store string
+	// Requests per Period, per client IP, on every rate limited route
+	Requests int
+	Period   time.Duration
+	// WriteRequests per WritePeriod on POST, PUT, PATCH and DELETE under
+	// /api, per client IP; gRPC calls presenting the token count per actor
+	WriteRequests int
+	WritePeriod   time.Duration
+}
+
+type CacheConfig struct {
+	// Size is the maximum number of entries per cache; 0 disables caching
+	Size int
+	TTL  time.Duration
+}
+
+type ExportConfig struct {
+	// WriteTimeout bounds a whole export, in place of the server's write
+	// timeout which is sized for ordinary requests
+	WriteTimeout time.Duration
+}
+
+type ImportConfig struct {
+	// MaxBytes bounds an uploaded import file, in place of the 1MB limit on
+	// other request bodies
+	MaxBytes int
+}
+
+type JobsConfig struct {
+	// Concurrency is how many jobs this instance runs at once
+	Concurrency  int
+	PollInterval time.Duration
+	// Lease is how long a job stays claimed by a worker that stopped sending
+	// heartbeats, before another worker takes it over
+	Lease       time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// DrainTimeout is how long running jobs may finish during shutdown
+	// before they are stopped and queued again
+	DrainTimeout time.Duration
+}
+
+type GraphQLConfig struct {
+	// MaxDepth bounds how deeply selections nest
+	MaxDepth int
+	// MaxComplexity bounds the fields a query may resolve, counting those
+	// under a paginated field once per car requested
+	MaxComplexity int
+	// GraphiQL serves the in-browser IDE on GET /graphql; it defaults to on
+	// in development only
+	GraphiQL bool
+}
+
+type GRPCConfig struct {
+	Port string
+	// AuthToken is required from callers as "authorization: Bearer <token>"
+	// metadata; empty disables the check
+	AuthToken string
+}
+
+type OpenAPIConfig struct {
+	// ValidateRequests rejects requests that do not match /openapi.json
+	// before they reach the handlers
+	ValidateRequests bool
+	// ValidateResponses logs responses that do not match /openapi.json; it
+	// defaults to on in the test environment only
+	ValidateResponses bool
+}
+
+type RedisConfig struct {
+	// Addr of the shared cache; empty keeps the cache local to each instance
+	Addr     string
+	Password string
+	DB       int
+	// Timeout bounds every command, so an unresponsive server delays
+	// requests by at most this much before they fall back to the database
+	Timeout       time.Duration
+	RetryInterval time.Duration
+}
+
+type AdminConfig struct {
+	// Addr is where the admin/debug server listens. Without a Token it must
+	// be a loopback address.
+	Addr  string
+	Token string
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration
+	CacheTTL     time.Duration
+	// MaxOutboxLag is the age of the oldest unpublished event above which
+	// the outbox check warns
+	MaxOutboxLag  time.Duration
+	DiskPath      string
+	DiskMinFreeMB int
+	// ShutdownDelay is how long readiness fails before the server stops
+	// accepting connections, so load balancers can react
+	ShutdownDelay time.Duration
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error
+	Level string
+	// Format is json or text
+	Format string
+}
+
+type TracingConfig struct {
+	// Exporter is one of none, otlp, stdout or memory
+	Exporter     string
+	OTLPEndpoint string
+	ServiceName  string
+}
+
+type MetricsConfig struct {
+	Port string
+}
+
+type StreamConfig struct {
+	BufferSize        int
+	HeartbeatInterval time.Duration
+}
+
+type WebhookConfig struct {
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	RequestTimeout time.Duration
+	BatchSize      int
+	PollInterval   time.Duration
 }
 
 func Load() *Config {
 	// Load .env file if exists
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+		slog.Info("No .env file found, using environment variables")
 	}
 
 	return &Config{
@@ -42,11 +221,109 @@ func Load() *Config {
 			Password: getEnv("DB_PASSWORD", "root"),
 			DBName:   getEnv("DB_NAME", "car_db"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			ReadTimeout:  getEnvDuration("DB_READ_TIMEOUT", 3*time.Second),
+			WriteTimeout: getEnvDuration("DB_WRITE_TIMEOUT", 5*time.Second),
+
+			SlowQueryThreshold:  getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			QueryStatsWindow:    getEnvInt("DB_QUERY_STATS_WINDOW", 1000),
+			QueryStatsMaxShapes: getEnvInt("DB_QUERY_STATS_MAX_SHAPES", 500),
 		},
 		Server: ServerConfig{
 			Port:           getEnv("SERVER_PORT", "8080"),
 			Env:            getEnv("SERVER_ENV", "development"),
 			AllowedOrigins: getEnvSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000", "http://localhost:8080"}),
+			TrustedProxies: getEnvSlice("TRUSTED_PROXIES", nil),
+			ErrorFormat:    getEnv("ERROR_FORMAT", "legacy"),
+		},
+		Outbox: OutboxConfig{
+			BatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+			PollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			MaxAttempts:  getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+			BaseBackoff:  getEnvDuration("OUTBOX_BASE_BACKOFF", time.Second),
+			MaxBackoff:   getEnvDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:    getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseBackoff:    getEnvDuration("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+			MaxBackoff:     getEnvDuration("WEBHOOK_MAX_BACKOFF", time.Hour),
+			RequestTimeout: getEnvDuration("WEBHOOK_REQUEST_TIMEOUT", 10*time.Second),
+			BatchSize:      getEnvInt("WEBHOOK_BATCH_SIZE", 50),
+			PollInterval:   getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		},
+		Stream: StreamConfig{
+			BufferSize:        getEnvInt("STREAM_BUFFER_SIZE", 1000),
+			HeartbeatInterval: getEnvDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+		},
+		Metrics: MetricsConfig{
+			Port: getEnv("METRICS_PORT", "9090"),
+		},
+		RateLimit: RateLimitConfig{
+			Store:         getEnv("RATE_LIMIT_STORE", "memory"),
+			Requests:      getEnvInt("RATE_LIMIT_REQUESTS", 100),
+			Period:        getEnvDuration("RATE_LIMIT_PERIOD", time.Minute),
+			WriteRequests: getEnvInt("RATE_LIMIT_WRITE_REQUESTS", 30),
+			WritePeriod:   getEnvDuration("RATE_LIMIT_WRITE_PERIOD", time.Minute),
+		},
+		Cache: CacheConfig{
+			Size: getEnvInt("CACHE_SIZE", 10000),
+			TTL:  getEnvDuration("CACHE_TTL", 30*time.Second),
+		},
+		Export: ExportConfig{
+			WriteTimeout: getEnvDuration("EXPORT_WRITE_TIMEOUT", 30*time.Minute),
+		},
+		Import: ImportConfig{
+			MaxBytes: getEnvInt("IMPORT_MAX_BYTES", 10<<20),
+		},
+		Jobs: JobsConfig{
+			Concurrency:  getEnvInt("JOBS_CONCURRENCY", 4),
+			PollInterval: getEnvDuration("JOBS_POLL_INTERVAL", time.Second),
+			Lease:        getEnvDuration("JOBS_LEASE", 30*time.Second),
+			MaxAttempts:  getEnvInt("JOBS_MAX_ATTEMPTS", 3),
+			BaseBackoff:  getEnvDuration("JOBS_BASE_BACKOFF", 5*time.Second),
+			MaxBackoff:   getEnvDuration("JOBS_MAX_BACKOFF", 5*time.Minute),
+			DrainTimeout: getEnvDuration("JOBS_DRAIN_TIMEOUT", 20*time.Second),
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 10),
+			MaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 1000),
+			GraphiQL:      getEnvBool("GRAPHIQL_ENABLED", getEnv("SERVER_ENV", "development") == "development"),
+		},
+		GRPC: GRPCConfig{
+			Port:      getEnv("GRPC_PORT", "50051"),
+			AuthToken: getEnv("GRPC_AUTH_TOKEN", ""),
+		},
+		OpenAPI: OpenAPIConfig{
+			ValidateRequests:  getEnvBool("OPENAPI_VALIDATE_REQUESTS", false),
+			ValidateResponses: getEnvBool("OPENAPI_VALIDATE_RESPONSES", getEnv("SERVER_ENV", "development") == "test"),
+		},
+		Redis: RedisConfig{
+			Addr:          getEnv("REDIS_ADDR", ""),
+			Password:      getEnv("REDIS_PASSWORD", ""),
+			DB:            getEnvInt("REDIS_DB", 0),
+			Timeout:       getEnvDuration("REDIS_TIMEOUT", 100*time.Millisecond),
+			RetryInterval: getEnvDuration("REDIS_RETRY_INTERVAL", 5*time.Second),
+		},
+		Admin: AdminConfig{
+			Addr:  getEnv("ADMIN_ADDR", "127.0.0.1:6060"),
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Health: HealthConfig{
+			CheckTimeout:  getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			CacheTTL:      getEnvDuration("HEALTH_CACHE_TTL", 2*time.Second),
+			MaxOutboxLag:  getEnvDuration("HEALTH_MAX_OUTBOX_LAG", time.Minute),
+			DiskPath:      getEnv("HEALTH_DISK_PATH", os.TempDir()),
+			DiskMinFreeMB: getEnvInt("HEALTH_DISK_MIN_FREE_MB", 100),
+			ShutdownDelay: getEnvDuration("HEALTH_SHUTDOWN_DELAY", 5*time.Second),
+		},
+		Log: LogConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "json"),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "http://localhost:4318"),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "car-api"),
 		},
 	}
 }
@@ -65,6 +342,36 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		slog.Warn("Invalid integer, using default", slog.String("key", key), slog.Int("default", defaultValue))
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+		slog.Warn("Invalid boolean, using default", slog.String("key", key), slog.Bool("default", defaultValue))
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+		slog.Warn("Invalid duration, using default", slog.String("key", key), slog.Duration("default", defaultValue))
+	}
+	return defaultValue
+}
+
 func getEnvSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// Split by comma for multiple origins
@@ -95,3 +402,57 @@ func splitByComma(s string) []string {
 	}
 	return result
 }
+
+// Validate rejects an admin server reachable from the network without auth
+func (c *AdminConfig) Validate() error {
+	if c.Token != "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(c.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid ADMIN_ADDR %q: %w", c.Addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("ADMIN_TOKEN is required when ADMIN_ADDR %q is not a loopback address", c.Addr)
+}
+
+// Validate rejects limits that allow no requests or have no period, which
+// the rate limiter cannot divide into a refill interval
+func (c *RateLimitConfig) Validate() error {
+	limits := []struct {
+		requests, period string
+		n                int
+		d                time.Duration
+	}{
+		{"RATE_LIMIT_REQUESTS", "RATE_LIMIT_PERIOD", c.Requests, c.Period},
+		{"RATE_LIMIT_WRITE_REQUESTS", "RATE_LIMIT_WRITE_PERIOD", c.WriteRequests, c.WritePeriod},
+	}
+	for _, l := range limits {
+		if l.n <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", l.requests, l.n)
+		}
+		if l.d <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", l.period, l.d)
+		}
+	}
+	return nil
+}
+
+// Validate rejects trusted proxies that are neither an IP nor a CIDR
+func (c *ServerConfig) Validate() error {
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return fmt.Errorf("invalid TRUSTED_PROXIES entry %q: not an IP or CIDR", proxy)
+		}
+	}
+	return nil
+}
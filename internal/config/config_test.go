@@ -0,0 +1,96 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := &Config{
+		Database: DatabaseConfig{Host: "db", Password: "hunter2", ReadTimeout: 3 * time.Second},
+		Admin:    AdminConfig{Addr: "127.0.0.1:6060"},
+	}
+
+	redacted := cfg.Redacted()
+
+	database := redacted["Database"].(map[string]any)
+	assert.Equal(t, "db", database["Host"])
+	assert.Equal(t, "[REDACTED]", database["Password"])
+	assert.Equal(t, "3s", database["ReadTimeout"])
+
+	admin := redacted["Admin"].(map[string]any)
+	assert.Equal(t, "", admin["Token"], "unset secrets show as empty")
+}
+
+func TestAdminConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AdminConfig
+		wantErr bool
+	}{
+		{name: "Loopback IPv4 without token", cfg: AdminConfig{Addr: "127.0.0.1:6060"}},
+		{name: "Loopback IPv6 without token", cfg: AdminConfig{Addr: "[::1]:6060"}},
+		{name: "Localhost without token", cfg: AdminConfig{Addr: "localhost:6060"}},
+		{name: "All interfaces with token", cfg: AdminConfig{Addr: ":6060", Token: "s3cret"}},
+		{name: "All interfaces without token", cfg: AdminConfig{Addr: ":6060"}, wantErr: true},
+		{name: "Public address without token", cfg: AdminConfig{Addr: "10.0.0.5:6060"}, wantErr: true},
+		{name: "Malformed address", cfg: AdminConfig{Addr: "6060"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	valid := RateLimitConfig{Requests: 100, Period: time.Minute, WriteRequests: 30, WritePeriod: time.Minute}
+
+	tests := []struct {
+		name    string
+		modify  func(c *RateLimitConfig)
+		wantErr string
+	}{
+		{name: "Defaults", modify: func(c *RateLimitConfig) {}},
+		{name: "Zero requests", modify: func(c *RateLimitConfig) { c.Requests = 0 }, wantErr: "RATE_LIMIT_REQUESTS must be positive, got 0"},
+		{name: "Zero write requests", modify: func(c *RateLimitConfig) { c.WriteRequests = 0 }, wantErr: "RATE_LIMIT_WRITE_REQUESTS must be positive, got 0"},
+		{name: "Negative period", modify: func(c *RateLimitConfig) { c.Period = -time.Second }, wantErr: "RATE_LIMIT_PERIOD must be positive, got -1s"},
+		{name: "Zero write period", modify: func(c *RateLimitConfig) { c.WritePeriod = 0 }, wantErr: "RATE_LIMIT_WRITE_PERIOD must be positive, got 0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServerConfig_Validate(t *testing.T) {
+	t.Run("Success - IPs and CIDRs", func(t *testing.T) {
+		cfg := ServerConfig{TrustedProxies: []string{"10.0.0.1", "172.16.0.0/12", "::1"}}
+
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("Error - Host name", func(t *testing.T) {
+		cfg := ServerConfig{TrustedProxies: []string{"lb.internal"}}
+
+		assert.EqualError(t, cfg.Validate(), `invalid TRUSTED_PROXIES entry "lb.internal": not an IP or CIDR`)
+	})
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"project-simple/internal/logging"
+	"reflect"
+	"time"
+)
+
+// Redacted returns the configuration as a map for display, with every field
+// whose name looks like a secret replaced and durations in readable form
+func (c *Config) Redacted() map[string]any {
+	return redactStruct(reflect.ValueOf(*c))
+}
+
+func redactStruct(v reflect.Value) map[string]any {
+	out := make(map[string]any, v.NumField())
+	t := v.Type()
+
+	for i := range v.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+
+		switch {
+		case logging.IsSensitive(field.Name):
+			if value.IsZero() {
+				out[field.Name] = ""
+			} else {
+				out[field.Name] = logging.Redacted
+			}
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			out[field.Name] = time.Duration(value.Int()).String()
+		case value.Kind() == reflect.Struct:
+			out[field.Name] = redactStruct(value)
+		default:
+			out[field.Name] = value.Interface()
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,57 @@
+package dto
+
+// QueryStatsRequest represents the query parameters for the slowest query shapes
+type QueryStatsRequest struct {
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100" example:"10"`
+	Sort  string `form:"sort" binding:"omitempty,oneof=p95 p99 max total count" example:"p95"`
+}
+
+// SetDefaults sets default values for the limit and sort order
+func (r *QueryStatsRequest) SetDefaults() {
+	if r.Limit == 0 {
+		r.Limit = 10
+	}
+	if r.Sort == "" {
+		r.Sort = "p95"
+	}
+}
+
+// RuntimeStatsResponse represents Go runtime and garbage collector statistics
+type RuntimeStatsResponse struct {
+	GoVersion    string  `json:"go_version" example:"go1.25.1"`
+	NumCPU       int     `json:"num_cpu" example:"8"`
+	GOMAXPROCS   int     `json:"gomaxprocs" example:"8"`
+	Goroutines   int     `json:"goroutines" example:"42"`
+	HeapAlloc    uint64  `json:"heap_alloc_bytes" example:"8388608"`
+	HeapSys      uint64  `json:"heap_sys_bytes" example:"16777216"`
+	HeapObjects  uint64  `json:"heap_objects" example:"51234"`
+	TotalAlloc   uint64  `json:"total_alloc_bytes" example:"134217728"`
+	Sys          uint64  `json:"sys_bytes" example:"33554432"`
+	NumGC        uint32  `json:"num_gc" example:"17"`
+	PauseTotalMs float64 `json:"gc_pause_total_ms" example:"3.2"`
+	LastGC       string  `json:"last_gc,omitempty" example:"2024-01-01T10:00:00Z"`
+	NextGC       uint64  `json:"next_gc_bytes" example:"16777216"`
+	GCCPUPercent float64 `json:"gc_cpu_percent" example:"0.05"`
+}
+
+// BuildInfoResponse represents how the running binary was built
+type BuildInfoResponse struct {
+	GoVersion    string            `json:"go_version" example:"go1.25.1"`
+	Path         string            `json:"path" example:"project-simple/cmd/api"`
+	Version      string            `json:"version" example:"(devel)"`
+	Revision     string            `json:"revision,omitempty" example:"4ab55c1"`
+	RevisionTime string            `json:"revision_time,omitempty" example:"2024-01-01T10:00:00Z"`
+	Modified     bool              `json:"modified"`
+	Settings     map[string]string `json:"settings"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// LogLevelRequest represents a change of the log level
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error DEBUG INFO WARN ERROR" example:"debug"`
+}
+
+// LogLevelResponse represents the current log level
+type LogLevelResponse struct {
+	Level string `json:"level" example:"INFO"`
+}
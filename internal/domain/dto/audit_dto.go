@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/json"
+	"project-simple/pkg/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogFilter represents the query parameters for listing audit logs
+type AuditLogFilter struct {
+	EntityID string    `form:"entity_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Actor    string    `form:"actor" binding:"omitempty,max=255" example:"jane.doe"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-12-31T23:59:59Z"`
+	Page     int       `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int       `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
+}
+
+// AuditLogResponse represents a single audit log entry
+type AuditLogResponse struct {
+	ID         uuid.UUID       `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Actor      string          `json:"actor" example:"jane.doe"`
+	Action     string          `json:"action" example:"update"`
+	EntityType string          `json:"entity_type" example:"car"`
+	EntityID   uuid.UUID       `json:"entity_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	ClientIP   string          `json:"client_ip" example:"192.168.0.10"`
+	RequestID  string          `json:"request_id" example:"3f1c2a8e-9b7d-4e1f-8a2b-6c5d4e3f2a1b"`
+	CreatedAt  string          `json:"created_at" example:"2024-01-01T10:00:00Z"`
+}
+
+// AuditLogListResponse represents a paginated list of audit logs
+type AuditLogListResponse struct {
+	Data       []AuditLogResponse `json:"data"`
+	Pagination PaginationMeta     `json:"pagination"`
+}
+
+// Items returns the audit logs of the page
+func (r AuditLogListResponse) Items() interface{} {
+	return r.Data
+}
+
+// PageInfo returns the pagination metadata
+func (r AuditLogListResponse) PageInfo() response.PageInfo {
+	return r.Pagination.pageInfo()
+}
+
+// SetDefaults sets default values for pagination
+func (f *AuditLogFilter) SetDefaults() {
+	if f.Page < 1 {
+		f.Page = DefaultPage
+	}
+	if f.PageSize < 1 {
+		f.PageSize = DefaultPageSize
+	}
+	if f.PageSize > MaxPageSize {
+		f.PageSize = MaxPageSize
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (f *AuditLogFilter) GetOffset() int {
+	return (f.Page - 1) * f.PageSize
+}
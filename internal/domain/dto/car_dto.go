@@ -2,6 +2,9 @@ package dto
 
 import (
 	"fmt"
+	"project-simple/pkg/response"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -33,12 +36,22 @@ type CarResponse struct {
 	UpdatedAt     string    `json:"updated_at" example:"2024-01-01T10:00:00Z"`
 }
 
+// CarFilter represents the filters shared by car listings and exports
+type CarFilter struct {
+	Name          string `json:"name" form:"name" binding:"omitempty,max=100" example:"civic"`
+	EngineVersion string `json:"engine_version" form:"engine_version" binding:"omitempty,oneof=1.0 1.4 1.5 1.6 1.8 2.0 2.4 2.5 3.0 3.5 4.0" example:"2.0"`
+}
+
 // PaginationRequest represents pagination parameters
 type PaginationRequest struct {
 	Page     int    `form:"page" binding:"omitempty,min=1" example:"1"`
 	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
 	SortBy   string `form:"sort_by" binding:"omitempty,oneof=name engine_version created_at" example:"created_at"`
 	SortDir  string `form:"sort_dir" binding:"omitempty,oneof=asc desc" example:"desc"`
+	CarFilter
+	// Offset, when positive, replaces Page so a listing can start at any
+	// car, as cursor pagination does; it is not a query parameter
+	Offset int `form:"-"`
 }
 
 // PaginatedResponse represents a paginated response
@@ -55,6 +68,25 @@ type PaginationMeta struct {
 	TotalRecords int64 `json:"total_records" example:"50"`
 }
 
+// Items returns the cars of the page
+func (r PaginatedResponse) Items() interface{} {
+	return r.Data
+}
+
+// PageInfo returns the pagination metadata
+func (r PaginatedResponse) PageInfo() response.PageInfo {
+	return r.Pagination.pageInfo()
+}
+
+func (m PaginationMeta) pageInfo() response.PageInfo {
+	return response.PageInfo{
+		Page:         m.CurrentPage,
+		PageSize:     m.PageSize,
+		TotalPages:   m.TotalPages,
+		TotalRecords: m.TotalRecords,
+	}
+}
+
 // SetDefaults sets default values for pagination
 func (p *PaginationRequest) SetDefaults() {
 	if p.Page < 1 {
@@ -76,6 +108,9 @@ func (p *PaginationRequest) SetDefaults() {
 
 // GetOffset calculates the offset for pagination
 func (p *PaginationRequest) GetOffset() int {
+	if p.Offset > 0 {
+		return p.Offset
+	}
 	return (p.Page - 1) * p.PageSize
 }
 
@@ -101,3 +136,127 @@ func (p *PaginationRequest) GetOrderBy() string {
 
 	return fmt.Sprintf("%s %s", column, direction)
 }
+
+// CarVersionResponse represents a historical snapshot of a car
+type CarVersionResponse struct {
+	CarID         uuid.UUID `json:"car_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version       int       `json:"version" example:"3"`
+	Operation     string    `json:"operation" example:"update"`
+	RevertedFrom  *int      `json:"reverted_from,omitempty" example:"1"`
+	Name          string    `json:"name" example:"Honda Civic"`
+	EngineVersion string    `json:"engine_version" example:"2.0"`
+	Deleted       bool      `json:"deleted" example:"false"`
+	RecordedAt    string    `json:"recorded_at" example:"2024-01-01T10:00:00Z"`
+}
+
+// Export formats
+const (
+	ExportFormatCSV        = "csv"
+	ExportFormatNDJSON     = "ndjson"
+	ExportFormatNDJSONGzip = "ndjson.gz"
+)
+
+// CarExportRequest represents the format and filters of a car export
+type CarExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson ndjson.gz" example:"csv"`
+	CarFilter
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+}
+
+// Import conflict strategies, for rows whose ID matches an existing car
+const (
+	ImportOnConflictFail   = "fail"
+	ImportOnConflictSkip   = "skip"
+	ImportOnConflictUpdate = "update"
+)
+
+// Import row statuses
+const (
+	ImportStatusCreated = "created"
+	ImportStatusUpdated = "updated"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportCarsQuery represents the options of a car import
+type ImportCarsQuery struct {
+	DryRun     bool   `form:"dry_run" example:"false"`
+	OnConflict string `form:"on_conflict" binding:"omitempty,oneof=fail skip update" example:"update"`
+	// Async queues the import as a job instead of applying it in the request
+	Async bool `form:"async" example:"false"`
+}
+
+// ImportCarRow represents one imported car: created, or matched to an
+// existing car by ID. It is validated with the same rules as CreateCarRequest.
+type ImportCarRow struct {
+	ID string `json:"id" binding:"omitempty,uuid"`
+	CreateCarRequest
+	// Row is the row's line in the uploaded file, for the report
+	Row int `json:"row"`
+	// AssignedID marks an ID given by the server rather than the file, so a
+	// retried import knows a car with it was created by an earlier attempt
+	AssignedID bool `json:"assigned_id,omitempty"`
+}
+
+// ImportCarsRequest represents the valid rows of an import and its options
+type ImportCarsRequest struct {
+	Rows       []ImportCarRow
+	OnConflict string
+	DryRun     bool
+	// Progress, when set, is told how many rows are done after each batch
+	Progress func(done, total int)
+}
+
+// ImportRowResult represents the outcome of one imported row
+type ImportRowResult struct {
+	Row    int                        `json:"row" example:"2"`
+	Status string                     `json:"status" example:"created"`
+	ID     *uuid.UUID                 `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Errors []response.ValidationError `json:"errors,omitempty"`
+}
+
+// ImportCarsResponse represents the report of an import. In a dry run
+// nothing is saved, but the outcomes are those a real run would have.
+type ImportCarsResponse struct {
+	DryRun     bool   `json:"dry_run" example:"false"`
+	OnConflict string `json:"on_conflict" example:"fail"`
+	Total      int    `json:"total" example:"3"`
+	Created    int    `json:"created" example:"1"`
+	Updated    int    `json:"updated" example:"1"`
+	Skipped    int    `json:"skipped" example:"0"`
+	Failed     int    `json:"failed" example:"1"`
+	// IgnoredColumns lists columns of the file that match no car field
+	IgnoredColumns []string          `json:"ignored_columns,omitempty" example:"color"`
+	Rows           []ImportRowResult `json:"rows"`
+}
+
+// Add records a row outcome and counts it
+func (r *ImportCarsResponse) Add(result ImportRowResult) {
+	r.Rows = append(r.Rows, result)
+	r.Total++
+	switch result.Status {
+	case ImportStatusCreated:
+		r.Created++
+	case ImportStatusUpdated:
+		r.Updated++
+	case ImportStatusSkipped:
+		r.Skipped++
+	case ImportStatusFailed:
+		r.Failed++
+	}
+}
+
+// Merge adds the outcomes of rows that never reached the service, such as
+// rows that failed validation, and puts every row back in file order
+func (r *ImportCarsResponse) Merge(results []ImportRowResult) {
+	for _, result := range results {
+		r.Add(result)
+	}
+	sort.SliceStable(r.Rows, func(i, j int) bool { return r.Rows[i].Row < r.Rows[j].Row })
+}
+
+// AsOfRequest represents the point-in-time query parameter for reading a car
+type AsOfRequest struct {
+	AsOf *time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T10:00:00Z"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// HealthCheckResponse represents the status of the API and its database
+type HealthCheckResponse struct {
+	Status   string `json:"status" example:"healthy"`
+	Service  string `json:"service" example:"car-api"`
+	Database string `json:"database" example:"connected"`
+	// Error says why the check failed, when it did
+	Error string `json:"error,omitempty" example:"database ping failed"`
+}
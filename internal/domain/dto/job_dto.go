@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// Job kinds
+const (
+	JobKindCarImport = "car_import"
+)
+
+// JobResponse represents an asynchronous job and where to find its result
+type JobResponse struct {
+	ID              uuid.UUID   `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Kind            string      `json:"kind" example:"car_import"`
+	Status          string      `json:"status" example:"running"`
+	Progress        JobProgress `json:"progress"`
+	Attempts        int         `json:"attempts" example:"1"`
+	MaxAttempts     int         `json:"max_attempts" example:"3"`
+	CancelRequested bool        `json:"cancel_requested" example:"false"`
+	LastError       string      `json:"last_error,omitempty" example:"connection reset by peer"`
+	// RunAt is when a queued job becomes due, later than CreatedAt while it waits to be retried
+	RunAt      string   `json:"run_at" example:"2024-01-01T10:00:00Z"`
+	StartedAt  string   `json:"started_at,omitempty" example:"2024-01-01T10:00:01Z"`
+	FinishedAt string   `json:"finished_at,omitempty" example:"2024-01-01T10:02:30Z"`
+	CreatedAt  string   `json:"created_at" example:"2024-01-01T10:00:00Z"`
+	Links      JobLinks `json:"links"`
+}
+
+// JobProgress represents how much of a job's work is done, as last reported
+// by its worker. Total is 0 until the worker knows it.
+type JobProgress struct {
+	Done    int `json:"done" example:"1500"`
+	Total   int `json:"total" example:"6000"`
+	Percent int `json:"percent" example:"25"`
+}
+
+// JobLinks represents where to poll a job, fetch its result and cancel it.
+// Result is set once the job has succeeded, and Cancel while it can still be
+// cancelled.
+type JobLinks struct {
+	Self   string `json:"self" example:"/api/v1/jobs/7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Result string `json:"result,omitempty" example:"/api/v1/jobs/7c9e6679-7425-40de-944b-e07fc1f90ae7/result"`
+	Cancel string `json:"cancel,omitempty" example:"/api/v1/jobs/7c9e6679-7425-40de-944b-e07fc1f90ae7/cancel"`
+}
+
+// CarImportJob is the payload of an asynchronous import: the rows as parsed
+// and validated by the request, for a worker to apply
+type CarImportJob struct {
+	Rows           []ImportCarRow    `json:"rows"`
+	Invalid        []ImportRowResult `json:"invalid,omitempty"`
+	IgnoredColumns []string          `json:"ignored_columns,omitempty"`
+	OnConflict     string            `json:"on_conflict"`
+	DryRun         bool              `json:"dry_run"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"project-simple/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhookRequest represents the request body for creating a webhook subscription
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,startswith=https://,max=2048" example:"https://partner.example.com/hooks/cars"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=car.created car.updated car.deleted car.restored *" example:"car.created,car.updated"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=255" example:"whsec_2f7c1b9e4d3a8f6c5b2e1d0a9f8e7d6c"`
+}
+
+// UpdateWebhookRequest represents the request body for updating a webhook subscription
+type UpdateWebhookRequest struct {
+	URL        string   `json:"url" binding:"omitempty,url,startswith=https://,max=2048" example:"https://partner.example.com/hooks/cars"`
+	EventTypes []string `json:"event_types" binding:"omitempty,min=1,dive,oneof=car.created car.updated car.deleted car.restored *" example:"car.deleted"`
+	Active     *bool    `json:"active" example:"false"`
+}
+
+// WebhookResponse represents a webhook subscription. The secret is only
+// returned when the subscription is created.
+type WebhookResponse struct {
+	ID         uuid.UUID `json:"id" example:"1b4e28ba-2fa1-11d2-883f-0016d3cca427"`
+	URL        string    `json:"url" example:"https://partner.example.com/hooks/cars"`
+	EventTypes []string  `json:"event_types" example:"car.created,car.updated"`
+	Secret     string    `json:"secret,omitempty" example:"whsec_2f7c1b9e4d3a8f6c5b2e1d0a9f8e7d6c"`
+	Active     bool      `json:"active" example:"true"`
+	CreatedAt  string    `json:"created_at" example:"2024-01-01T10:00:00Z"`
+	UpdatedAt  string    `json:"updated_at" example:"2024-01-01T10:00:00Z"`
+}
+
+// WebhookDeliveryFilter represents the query parameters for listing deliveries
+type WebhookDeliveryFilter struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending succeeded dead" example:"dead"`
+	Page     int    `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
+}
+
+// WebhookDeliveryResponse represents a delivery of one event to a subscription
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID                        `json:"id" example:"9f8e7d6c-5b4a-3f2e-1d0c-9b8a7f6e5d4c"`
+	SubscriptionID uuid.UUID                        `json:"subscription_id" example:"1b4e28ba-2fa1-11d2-883f-0016d3cca427"`
+	EventID        uuid.UUID                        `json:"event_id" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	EventType      string                           `json:"event_type" example:"car.updated"`
+	Status         string                           `json:"status" example:"pending"`
+	Attempts       int                              `json:"attempts" example:"2"`
+	NextAttemptAt  string                           `json:"next_attempt_at" example:"2024-01-01T10:00:40Z"`
+	LastStatusCode int                              `json:"last_status_code,omitempty" example:"503"`
+	LastError      string                           `json:"last_error,omitempty" example:"unexpected status 503"`
+	CreatedAt      string                           `json:"created_at" example:"2024-01-01T10:00:00Z"`
+	AttemptLog     []WebhookDeliveryAttemptResponse `json:"attempt_log,omitempty"`
+}
+
+// WebhookDeliveryAttemptResponse represents a single HTTP call made for a delivery
+type WebhookDeliveryAttemptResponse struct {
+	Attempt    int    `json:"attempt" example:"1"`
+	StatusCode int    `json:"status_code,omitempty" example:"503"`
+	Error      string `json:"error,omitempty" example:"unexpected status 503"`
+	DurationMs int64  `json:"duration_ms" example:"120"`
+	CreatedAt  string `json:"created_at" example:"2024-01-01T10:00:00Z"`
+}
+
+// WebhookDeliveryListResponse represents a paginated list of deliveries
+type WebhookDeliveryListResponse struct {
+	Data       []WebhookDeliveryResponse `json:"data"`
+	Pagination PaginationMeta            `json:"pagination"`
+}
+
+// Items returns the deliveries of the page
+func (r WebhookDeliveryListResponse) Items() interface{} {
+	return r.Data
+}
+
+// PageInfo returns the pagination metadata
+func (r WebhookDeliveryListResponse) PageInfo() response.PageInfo {
+	return r.Pagination.pageInfo()
+}
+
+// SetDefaults sets default values for pagination
+func (f *WebhookDeliveryFilter) SetDefaults() {
+	if f.Page < 1 {
+		f.Page = DefaultPage
+	}
+	if f.PageSize < 1 {
+		f.PageSize = DefaultPageSize
+	}
+	if f.PageSize > MaxPageSize {
+		f.PageSize = MaxPageSize
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (f *WebhookDeliveryFilter) GetOffset() int {
+	return (f.Page - 1) * f.PageSize
+}
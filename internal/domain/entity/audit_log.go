@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionRevert  = "revert"
+)
+
+// AuditLog is an append-only record of a mutation performed on an entity
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Actor      string          `json:"actor" gorm:"type:varchar(255);not null;index:idx_audit_logs_actor"`
+	Action     string          `json:"action" gorm:"type:varchar(20);not null"`
+	EntityType string          `json:"entity_type" gorm:"type:varchar(50);not null"`
+	EntityID   uuid.UUID       `json:"entity_id" gorm:"type:uuid;not null;index:idx_audit_logs_entity_id"`
+	Changes    json.RawMessage `json:"changes" gorm:"type:jsonb;not null"`
+	ClientIP   string          `json:"client_ip" gorm:"type:varchar(45)"`
+	RequestID  string          `json:"request_id" gorm:"type:varchar(64);index:idx_audit_logs_request_id"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_audit_logs_created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	CarVersionOpCreate  = "create"
+	CarVersionOpUpdate  = "update"
+	CarVersionOpDelete  = "delete"
+	CarVersionOpRestore = "restore"
+	CarVersionOpRevert  = "revert"
+)
+
+// CarVersion is a snapshot of a car taken after each change. The snapshot
+// describes the car from RecordedAt until the next version is recorded.
+type CarVersion struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CarID         uuid.UUID `json:"car_id" gorm:"type:uuid;not null;uniqueIndex:idx_car_versions_car_version,priority:1"`
+	Version       int       `json:"version" gorm:"not null;uniqueIndex:idx_car_versions_car_version,priority:2"`
+	Operation     string    `json:"operation" gorm:"type:varchar(20);not null"`
+	RevertedFrom  *int      `json:"reverted_from,omitempty"`
+	Name          string    `json:"name" gorm:"type:varchar(100);not null"`
+	EngineVersion string    `json:"engine_version" gorm:"type:varchar(10);not null"`
+	Deleted       bool      `json:"deleted" gorm:"not null;default:false"`
+	CarCreatedAt  time.Time `json:"car_created_at" gorm:"not null"`
+	CarUpdatedAt  time.Time `json:"car_updated_at" gorm:"not null"`
+	RecordedAt    time.Time `json:"recorded_at" gorm:"not null;index:idx_car_versions_recorded_at"`
+}
+
+func (CarVersion) TableName() string {
+	return "car_versions"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (v *CarVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job is a long-running operation executed by a background worker. A worker
+// holds a running job for as long as it keeps renewing LockedUntil; a job
+// whose lease lapses is claimed again by another worker.
+type Job struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Kind        string          `gorm:"type:varchar(50);not null"`
+	Status      string          `gorm:"type:varchar(20);not null;index:idx_jobs_status_run_at,priority:1"`
+	Payload     json.RawMessage `gorm:"type:jsonb;not null"`
+	Result      json.RawMessage `gorm:"type:jsonb"`
+	Attempts    int             `gorm:"not null;default:0"`
+	MaxAttempts int             `gorm:"not null"`
+	RunAt       time.Time       `gorm:"not null;index:idx_jobs_status_run_at,priority:2"`
+	// ProgressDone of ProgressTotal units of work, as last reported
+	ProgressDone    int    `gorm:"not null;default:0"`
+	ProgressTotal   int    `gorm:"not null;default:0"`
+	LastError       string `gorm:"type:text"`
+	CancelRequested bool   `gorm:"not null;default:false"`
+	LockedBy        string `gorm:"type:varchar(100)"`
+	LockedUntil     *time.Time
+	// RequestID and Actor of the request that enqueued the job, so its
+	// audit entries are attributed as if the request had done the work
+	RequestID  string `gorm:"type:varchar(100)"`
+	Actor      string `gorm:"type:varchar(255)"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (j *Job) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+// Finished reports whether the job has reached a final status
+func (j *Job) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCancelled
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"encoding/json"
+	"project-simple/internal/domain/event"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxMessage is a domain event waiting to be delivered. It is written in
+// the same transaction as the change that raised it.
+type OutboxMessage struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Sequence      int64           `gorm:"autoIncrement;uniqueIndex:idx_outbox_messages_sequence;not null"`
+	EventType     string          `gorm:"type:varchar(100);not null"`
+	AggregateType string          `gorm:"type:varchar(50);not null"`
+	AggregateID   uuid.UUID       `gorm:"type:uuid;not null;index:idx_outbox_messages_aggregate_id"`
+	Payload       json.RawMessage `gorm:"type:jsonb;not null"`
+	OccurredAt    time.Time       `gorm:"not null"`
+	PublishedAt   *time.Time      `gorm:"index:idx_outbox_messages_published_at"`
+	Attempts      int             `gorm:"not null;default:0"`
+	LastError     string          `gorm:"type:text"`
+	// NextAttemptAt delays the retry of a failed event; nil means now
+	NextAttemptAt *time.Time
+	// DeadLetteredAt is set once the relay gives up on the event, which
+	// then no longer holds back the later events of its aggregate
+	DeadLetteredAt *time.Time `gorm:"index:idx_outbox_messages_dead_lettered_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (m *OutboxMessage) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// Envelope converts the message into the form handed to sinks
+func (m *OutboxMessage) Envelope() event.Envelope {
+	return event.Envelope{
+		ID:            m.ID,
+		Sequence:      m.Sequence,
+		Type:          m.EventType,
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID,
+		OccurredAt:    m.OccurredAt,
+		Payload:       m.Payload,
+	}
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription registers a partner URL for a set of event types
+type WebhookSubscription struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	URL        string         `gorm:"type:varchar(2048);not null"`
+	EventTypes StringList     `gorm:"type:jsonb;not null"`
+	Secret     string         `gorm:"type:varchar(255);not null"`
+	Active     bool           `gorm:"not null;default:true"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt `gorm:"index:idx_webhook_subscriptions_deleted_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (w *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookDelivery is one event to be delivered to one subscription. It is
+// retried until it succeeds or runs out of attempts and becomes dead.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SubscriptionID uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_subscription_event,priority:1"`
+	EventID        uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_subscription_event,priority:2"`
+	EventType      string          `gorm:"type:varchar(100);not null"`
+	Payload        json.RawMessage `gorm:"type:jsonb;not null"`
+	Status         string          `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_status_next,priority:1"`
+	Attempts       int             `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time       `gorm:"not null;index:idx_webhook_deliveries_status_next,priority:2"`
+	LastStatusCode int
+	LastError      string    `gorm:"type:text"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookDeliveryAttempt records the outcome of a single HTTP call
+type WebhookDeliveryAttempt struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DeliveryID     uuid.UUID `gorm:"type:uuid;not null;index:idx_webhook_attempts_delivery_id"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null;index:idx_webhook_attempts_subscription_id"`
+	Attempt        int       `gorm:"not null"`
+	StatusCode     int
+	Error          string    `gorm:"type:text"`
+	DurationMs     int64     `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+// BeforeCreate hook to generate UUID before creating
+func (a *WebhookDeliveryAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
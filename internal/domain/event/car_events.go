@@ -0,0 +1,54 @@
+package event
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	AggregateCar = "car"
+
+	TypeCarCreated  = "car.created"
+	TypeCarUpdated  = "car.updated"
+	TypeCarDeleted  = "car.deleted"
+	TypeCarRestored = "car.restored"
+)
+
+// CarState is the public representation of a car carried by car events
+type CarState struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	EngineVersion string    `json:"engine_version"`
+}
+
+type CarCreated struct {
+	Car CarState `json:"car"`
+}
+
+func (e CarCreated) EventType() string      { return TypeCarCreated }
+func (e CarCreated) AggregateType() string  { return AggregateCar }
+func (e CarCreated) AggregateID() uuid.UUID { return e.Car.ID }
+
+type CarUpdated struct {
+	Before CarState `json:"before"`
+	After  CarState `json:"after"`
+}
+
+func (e CarUpdated) EventType() string      { return TypeCarUpdated }
+func (e CarUpdated) AggregateType() string  { return AggregateCar }
+func (e CarUpdated) AggregateID() uuid.UUID { return e.After.ID }
+
+type CarDeleted struct {
+	Car CarState `json:"car"`
+}
+
+func (e CarDeleted) EventType() string      { return TypeCarDeleted }
+func (e CarDeleted) AggregateType() string  { return AggregateCar }
+func (e CarDeleted) AggregateID() uuid.UUID { return e.Car.ID }
+
+type CarRestored struct {
+	Car CarState `json:"car"`
+}
+
+func (e CarRestored) EventType() string      { return TypeCarRestored }
+func (e CarRestored) AggregateType() string  { return AggregateCar }
+func (e CarRestored) AggregateID() uuid.UUID { return e.Car.ID }
@@ -0,0 +1,29 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a fact about a change to an aggregate, raised by the service
+// layer and delivered to downstream systems through the outbox
+type Event interface {
+	EventType() string
+	AggregateType() string
+	AggregateID() uuid.UUID
+}
+
+// Envelope is the serialised form of an event as stored in the outbox and
+// handed to sinks. Sequence orders the events of one aggregate in the order
+// their changes were committed.
+type Envelope struct {
+	ID            uuid.UUID       `json:"id"`
+	Sequence      int64           `json:"sequence"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,162 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"project-simple/internal/domain/dto"
+	"project-simple/pkg/apperror"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// cursorPrefix marks a cursor as a position in a car listing. Cursors are
+// opaque to clients; they may only pass them back.
+const cursorPrefix = "car:"
+
+// carConnection is a Relay connection over a listing of cars
+type carConnection struct {
+	Edges      []carEdge
+	PageInfo   pageInfo
+	TotalCount int64
+}
+
+type carEdge struct {
+	Cursor string
+	Node   *dto.CarResponse
+}
+
+type pageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+// carsArgs are the arguments of the cars query
+type carsArgs struct {
+	First  *int          `json:"first" binding:"omitempty,min=0,max=100"`
+	Last   *int          `json:"last" binding:"omitempty,min=0,max=100"`
+	After  string        `json:"after"`
+	Before string        `json:"before"`
+	Filter dto.CarFilter `json:"filter"`
+	Sort   carSort       `json:"sort"`
+}
+
+type carSort struct {
+	Field     string
+	Direction string
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	invalid := &Error{Code: apperror.InvalidRequest, Message: "Invalid cursor"}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, invalid
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || offset < 0 || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, invalid
+	}
+	return offset, nil
+}
+
+// listCars returns the page of cars args select. Cursors are positions in
+// the listing, so first/after and last/before work as Relay specifies over
+// the offset pagination the service offers.
+func (r *resolver) listCars(ctx context.Context, args *carsArgs) (*carConnection, error) {
+	if err := binding.Validator.ValidateStruct(args); err != nil {
+		return nil, err
+	}
+	if args.First != nil && args.Last != nil {
+		return nil, &Error{Code: apperror.InvalidRequest, Message: "Pass first or last, not both"}
+	}
+
+	start, end := 0, -1
+	if args.After != "" {
+		after, err := decodeCursor(args.After)
+		if err != nil {
+			return nil, err
+		}
+		start = after + 1
+	}
+	if args.Before != "" {
+		before, err := decodeCursor(args.Before)
+		if err != nil {
+			return nil, err
+		}
+		end = max(before, start)
+	}
+
+	req := dto.PaginationRequest{
+		SortBy:    args.Sort.Field,
+		SortDir:   args.Sort.Direction,
+		CarFilter: args.Filter,
+	}
+
+	var offset, limit int
+	if args.Last != nil {
+		if end < 0 {
+			// Counting from the end needs to know where the end is
+			page, err := r.page(ctx, req, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			end = max(int(page.Pagination.TotalRecords), start)
+		}
+		offset = max(start, end-*args.Last)
+		limit = end - offset
+	} else {
+		offset = start
+		limit = dto.DefaultPageSize
+		if args.First != nil {
+			limit = *args.First
+		}
+		if end >= 0 {
+			limit = min(limit, end-start)
+		}
+	}
+
+	page, err := r.page(ctx, req, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	total := page.Pagination.TotalRecords
+	conn := &carConnection{
+		Edges:      make([]carEdge, len(page.Data)),
+		TotalCount: total,
+		PageInfo: pageInfo{
+			HasPreviousPage: offset > 0,
+			HasNextPage:     int64(offset+len(page.Data)) < total,
+		},
+	}
+	for i := range page.Data {
+		conn.Edges[i] = carEdge{Cursor: encodeCursor(offset + i), Node: &page.Data[i]}
+	}
+	if n := len(conn.Edges); n > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[n-1].Cursor
+	}
+	return conn, nil
+}
+
+// page fetches limit cars from offset. A limit of zero still fetches the
+// total, which every connection reports.
+func (r *resolver) page(ctx context.Context, req dto.PaginationRequest, offset, limit int) (*dto.PaginatedResponse, error) {
+	req.Offset = offset
+	req.PageSize = max(limit, 1)
+	page, err := r.cars.GetAllCars(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		page.Data = nil
+	}
+	return page, nil
+}
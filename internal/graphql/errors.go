@@ -0,0 +1,103 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"project-simple/internal/logging"
+	"project-simple/internal/validation"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+)
+
+// Error is a resolver error. Its code from pkg/apperror, and for invalid
+// input the fields at fault, are reported under the error's extensions.
+type Error struct {
+	Code    apperror.Code
+	Message string
+	Fields  []response.ValidationError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": string(e.Code)}
+	if len(e.Fields) > 0 {
+		extensions["fields"] = e.Fields
+	}
+	return extensions
+}
+
+// HasCode reports whether any error in errs carries code
+func HasCode(errs []gqlerrors.FormattedError, code apperror.Code) bool {
+	for _, err := range errs {
+		if err.Extensions["code"] == string(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestError reports a request that was not executed at all
+func RequestError(code apperror.Code, message string) gqlerrors.FormattedError {
+	return gqlerrors.FormattedError{
+		Message:    message,
+		Locations:  []location.SourceLocation{},
+		Extensions: (&Error{Code: code, Message: message}).Extensions(),
+	}
+}
+
+// resolverError turns a service error into an Error, in the same terms the
+// REST handlers report it
+func resolverError(ctx context.Context, err error) error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		return gqlErr
+	}
+	if fields := validation.Translate(err, translatorFrom(ctx)); fields != nil {
+		return &Error{Code: apperror.ValidationFailed, Message: "Validation failed", Fields: schemaFields(fields)}
+	}
+	if code, ok := apperror.CodeOf(err); ok {
+		return &Error{Code: code, Message: apperror.Lookup(code).Title}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Code: apperror.Timeout, Message: "The request took too long to complete"}
+	case errors.Is(err, context.Canceled):
+		return &Error{Code: apperror.ServiceUnavailable, Message: "The request was cancelled, please retry"}
+	}
+
+	logging.FromContext(ctx).Error("GraphQL resolver failed", slog.Any("error", err))
+	return &Error{Code: apperror.InternalError, Message: apperror.Lookup(apperror.InternalError).Title}
+}
+
+// schemaFields names invalid fields as the schema does: validation reports
+// the json names of the request DTOs, which are snake_case
+func schemaFields(fields []response.ValidationError) []response.ValidationError {
+	for i, field := range fields {
+		name := camelCase(field.Field)
+		fields[i] = response.ValidationError{
+			Field:   name,
+			Message: strings.Replace(field.Message, field.Field, name, 1),
+		}
+	}
+	return fields
+}
+
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
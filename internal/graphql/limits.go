@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"encoding/json"
+	"project-simple/internal/domain/dto"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// paginatedFields are the fields returning a page of cars, whose selections
+// are resolved once per car
+var paginatedFields = map[string]bool{
+	"cars": true,
+}
+
+// measurer works out the depth and complexity of an operation
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	visiting  map[string]bool
+}
+
+// measure returns how deeply op's selections nest and how many fields it
+// resolves. Every field costs one; the selections of a paginated field cost
+// once per car it may return. Introspection is free, so tools can always load
+// the schema.
+func measure(doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) (depth, complexity int) {
+	m := &measurer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		visiting:  make(map[string]bool),
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[frag.Name.Value] = frag
+		}
+	}
+	return m.selectionSet(op.SelectionSet, 0)
+}
+
+func (m *measurer) selectionSet(set *ast.SelectionSet, depth int) (maxDepth, cost int) {
+	maxDepth = depth
+	if set == nil {
+		return maxDepth, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			d, c = m.selectionSet(selection.SelectionSet, depth+1)
+			c = 1 + m.multiplier(selection)*c
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			frag, ok := m.fragments[name]
+			if !ok || m.visiting[name] {
+				continue
+			}
+			m.visiting[name] = true
+			d, c = m.selectionSet(frag.SelectionSet, depth)
+			delete(m.visiting, name)
+		}
+		maxDepth = max(maxDepth, d)
+		cost += c
+	}
+	return maxDepth, cost
+}
+
+// multiplier is how many times field's selections are resolved
+func (m *measurer) multiplier(field *ast.Field) int {
+	if !paginatedFields[field.Name.Value] {
+		return 1
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value == "first" || arg.Name.Value == "last" {
+			if n, ok := m.intValue(arg.Value); ok {
+				return max(n, 1)
+			}
+		}
+	}
+	return dto.DefaultPageSize
+}
+
+func (m *measurer) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch v := m.variables[value.Name.Value].(type) {
+		case int:
+			return v, true
+		case float64:
+			return int(v), true
+		case json.Number:
+			n, err := v.Int64()
+			return int(n), err == nil
+		}
+	}
+	return 0, false
+}
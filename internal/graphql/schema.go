@@ -0,0 +1,284 @@
+package graphql
+
+import (
+	"errors"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+// resolver answers the schema's fields through CarService, so GraphQL
+// clients get the same rules, audit entries and events as REST ones
+type resolver struct {
+	cars service.CarService
+}
+
+// newSchema builds the schema over cars
+func newSchema(cars service.CarService) (graphql.Schema, error) {
+	r := &resolver{cars: cars}
+
+	carType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Car",
+		Description: "A car",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*dto.CarResponse).ID.String(), nil
+				},
+			},
+			"name":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"engineVersion": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "RFC 3339 time the car was created"},
+			"updatedAt":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "RFC 3339 time the car was last changed"},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"startCursor":     &graphql.Field{Type: graphql.String},
+			"endCursor":       &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CarEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(carType)},
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CarConnection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Cars matching the filter, on every page"},
+		},
+	})
+
+	filterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CarFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":          &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Cars whose name contains this text, ignoring case"},
+			"engineVersion": &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Cars with this engine version"},
+		},
+	})
+
+	sortFieldType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "CarSortField",
+		Values: graphql.EnumValueConfigMap{
+			"NAME":           &graphql.EnumValueConfig{Value: "name"},
+			"ENGINE_VERSION": &graphql.EnumValueConfig{Value: "engine_version"},
+			"CREATED_AT":     &graphql.EnumValueConfig{Value: "created_at"},
+		},
+	})
+
+	sortDirectionType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SortDirection",
+		Values: graphql.EnumValueConfigMap{
+			"ASC":  &graphql.EnumValueConfig{Value: "asc"},
+			"DESC": &graphql.EnumValueConfig{Value: "desc"},
+		},
+	})
+
+	sortType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CarSort",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"field":     &graphql.InputObjectFieldConfig{Type: sortFieldType, DefaultValue: "created_at"},
+			"direction": &graphql.InputObjectFieldConfig{Type: sortDirectionType, DefaultValue: "desc"},
+		},
+	})
+
+	createInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateCarInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":          &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"engineVersion": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	updateInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "UpdateCarInput",
+		Description: "Fields to change; omitted fields are kept",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"engineVersion": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"car": &graphql.Field{
+				Type:        carType,
+				Description: "A car by ID, or null when there is none",
+				Args:        idArg,
+				Resolve:     resolve(r.car),
+			},
+			"cars": &graphql.Field{
+				Type:        graphql.NewNonNull(connectionType),
+				Description: "Cars, a page at a time. Pass first (default 10, at most 100) with after to page forward, or last with before to page back.",
+				Args: graphql.FieldConfigArgument{
+					"first":  &graphql.ArgumentConfig{Type: graphql.Int},
+					"after":  &graphql.ArgumentConfig{Type: graphql.String},
+					"last":   &graphql.ArgumentConfig{Type: graphql.Int},
+					"before": &graphql.ArgumentConfig{Type: graphql.String},
+					"filter": &graphql.ArgumentConfig{Type: filterType},
+					"sort":   &graphql.ArgumentConfig{Type: sortType},
+				},
+				Resolve: resolve(r.carsConnection),
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCar": &graphql.Field{
+				Type: graphql.NewNonNull(carType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createInputType)},
+				},
+				Resolve: resolve(r.createCar),
+			},
+			"updateCar": &graphql.Field{
+				Type: graphql.NewNonNull(carType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateInputType)},
+				},
+				Resolve: resolve(r.updateCar),
+			},
+			"deleteCar": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Deletes a car and returns its ID. Deleted cars can be restored through REST.",
+				Args:        idArg,
+				Resolve:     resolve(r.deleteCar),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// resolve reports fn's errors in the terms of the error catalog
+func resolve(fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := fn(p)
+		if err != nil {
+			return nil, resolverError(p.Context, err)
+		}
+		return result, nil
+	}
+}
+
+func (r *resolver) car(p graphql.ResolveParams) (interface{}, error) {
+	id, err := carID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := r.cars.GetCarByID(p.Context, id)
+	if errors.Is(err, service.ErrCarNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return car, nil
+}
+
+func (r *resolver) carsConnection(p graphql.ResolveParams) (interface{}, error) {
+	args := &carsArgs{
+		After:  stringArg(p.Args, "after"),
+		Before: stringArg(p.Args, "before"),
+	}
+	if first, ok := p.Args["first"].(int); ok {
+		args.First = &first
+	}
+	if last, ok := p.Args["last"].(int); ok {
+		args.Last = &last
+	}
+	if filter, ok := p.Args["filter"].(map[string]interface{}); ok {
+		args.Filter = dto.CarFilter{
+			Name:          stringArg(filter, "name"),
+			EngineVersion: stringArg(filter, "engineVersion"),
+		}
+	}
+	if sort, ok := p.Args["sort"].(map[string]interface{}); ok {
+		args.Sort = carSort{
+			Field:     stringArg(sort, "field"),
+			Direction: stringArg(sort, "direction"),
+		}
+	}
+	return r.listCars(p.Context, args)
+}
+
+func (r *resolver) createCar(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args["input"].(map[string]interface{})
+	req := &dto.CreateCarRequest{
+		Name:          stringArg(input, "name"),
+		EngineVersion: stringArg(input, "engineVersion"),
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return r.cars.CreateCar(p.Context, req)
+}
+
+func (r *resolver) updateCar(p graphql.ResolveParams) (interface{}, error) {
+	id, err := carID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	input, _ := p.Args["input"].(map[string]interface{})
+	req := &dto.UpdateCarRequest{
+		Name:          stringArg(input, "name"),
+		EngineVersion: stringArg(input, "engineVersion"),
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return r.cars.UpdateCar(p.Context, id, req)
+}
+
+func (r *resolver) deleteCar(p graphql.ResolveParams) (interface{}, error) {
+	id, err := carID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cars.DeleteCar(p.Context, id); err != nil {
+		return nil, err
+	}
+	return id.String(), nil
+}
+
+func carID(args map[string]interface{}) (uuid.UUID, error) {
+	id, err := uuid.Parse(stringArg(args, "id"))
+	if err != nil {
+		return uuid.Nil, &Error{Code: apperror.InvalidID, Message: "Invalid car ID format"}
+	}
+	return id, nil
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
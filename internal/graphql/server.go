@@ -0,0 +1,124 @@
+// Package graphql serves the car domain over GraphQL. Resolvers go through
+// CarService like the REST handlers, and report errors with the same codes.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Config limits the queries a server runs
+type Config struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Server executes GraphQL requests against the car schema
+type Server struct {
+	schema graphql.Schema
+	cfg    Config
+}
+
+func NewServer(cars service.CarService, cfg Config) (*Server, error) {
+	schema, err := newSchema(cars)
+	if err != nil {
+		return nil, fmt.Errorf("build graphql schema: %w", err)
+	}
+	return &Server{schema: schema, cfg: cfg}, nil
+}
+
+// Params is one GraphQL request
+type Params struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+	// Translator words validation messages in the client's language
+	Translator ut.Translator
+	// QueriesOnly rejects mutations, for requests that must be safe such as
+	// those sent with GET
+	QueriesOnly bool
+}
+
+// Execute parses, validates and runs a request. Requests that are too deep or
+// too complex are rejected before any resolver runs.
+func (s *Server) Execute(ctx context.Context, p Params) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(p.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if checked := graphql.ValidateDocument(&s.schema, doc, nil); !checked.IsValid {
+		return &graphql.Result{Errors: checked.Errors}
+	}
+
+	if op := operation(doc, p.OperationName); op != nil {
+		if p.QueriesOnly && op.Operation != ast.OperationTypeQuery {
+			return &graphql.Result{Errors: []gqlerrors.FormattedError{
+				RequestError(apperror.InvalidRequest, fmt.Sprintf("A %s must be sent with POST", op.Operation)),
+			}}
+		}
+
+		depth, complexity := measure(doc, op, p.Variables)
+		if depth > s.cfg.MaxDepth {
+			return &graphql.Result{Errors: []gqlerrors.FormattedError{
+				RequestError(apperror.QueryTooComplex, fmt.Sprintf("Query depth %d exceeds the limit of %d", depth, s.cfg.MaxDepth)),
+			}}
+		}
+		if complexity > s.cfg.MaxComplexity {
+			return &graphql.Result{Errors: []gqlerrors.FormattedError{
+				RequestError(apperror.QueryTooComplex, fmt.Sprintf("Query complexity %d exceeds the limit of %d", complexity, s.cfg.MaxComplexity)),
+			}}
+		}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: p.OperationName,
+		Args:          p.Variables,
+		Context:       withTranslator(ctx, p.Translator),
+	})
+}
+
+// operation returns the operation a request runs, or nil when it names none
+// that exists, which Execute reports
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+type translatorKey struct{}
+
+func withTranslator(ctx context.Context, trans ut.Translator) context.Context {
+	return context.WithValue(ctx, translatorKey{}, trans)
+}
+
+func translatorFrom(ctx context.Context) ut.Translator {
+	trans, _ := ctx.Value(translatorKey{}).(ut.Translator)
+	return trans
+}
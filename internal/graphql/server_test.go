@@ -0,0 +1,282 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/service"
+	"project-simple/internal/validation"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCarService lists cars in a fixed order and records the last listing
+type fakeCarService struct {
+	service.CarService
+	cars    []dto.CarResponse
+	listed  *dto.PaginationRequest
+	created *dto.CreateCarRequest
+}
+
+func newFakeCarService(n int) *fakeCarService {
+	f := &fakeCarService{}
+	for i := range n {
+		f.cars = append(f.cars, dto.CarResponse{ID: uuid.New(), Name: fmt.Sprintf("Car %d", i), EngineVersion: "2.0"})
+	}
+	return f
+}
+
+func (f *fakeCarService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	for _, car := range f.cars {
+		if car.ID == id {
+			return &car, nil
+		}
+	}
+	return nil, service.ErrCarNotFound
+}
+
+func (f *fakeCarService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	pagination.SetDefaults()
+	f.listed = pagination
+	start := min(pagination.GetOffset(), len(f.cars))
+	end := min(start+pagination.PageSize, len(f.cars))
+	return &dto.PaginatedResponse{
+		Data:       append([]dto.CarResponse(nil), f.cars[start:end]...),
+		Pagination: dto.PaginationMeta{PageSize: pagination.PageSize, TotalRecords: int64(len(f.cars))},
+	}, nil
+}
+
+func (f *fakeCarService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	f.created = req
+	return &dto.CarResponse{ID: uuid.New(), Name: req.Name, EngineVersion: req.EngineVersion}, nil
+}
+
+func (f *fakeCarService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	if _, err := f.GetCarByID(ctx, id); err != nil {
+		return err
+	}
+	return errors.New("connection refused")
+}
+
+func newTestServer(t *testing.T, cars service.CarService) *Server {
+	require.NoError(t, validation.Setup())
+	server, err := NewServer(cars, Config{MaxDepth: 6, MaxComplexity: 200})
+	require.NoError(t, err)
+	return server
+}
+
+// execute runs query and decodes the result as a client would see it
+func execute(t *testing.T, server *Server, p Params) map[string]interface{} {
+	data, err := json.Marshal(server.Execute(context.Background(), p))
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &result))
+	return result
+}
+
+func firstError(t *testing.T, result map[string]interface{}) map[string]interface{} {
+	errs, ok := result["errors"].([]interface{})
+	require.True(t, ok, "expected errors in %v", result)
+	return errs[0].(map[string]interface{})
+}
+
+func TestServer_Car(t *testing.T) {
+	cars := newFakeCarService(1)
+	server := newTestServer(t, cars)
+	query := `query($id: ID!) { car(id: $id) { id name engineVersion } }`
+
+	t.Run("Success - Returns the requested fields", func(t *testing.T) {
+		result := execute(t, server, Params{Query: query, Variables: map[string]interface{}{"id": cars.cars[0].ID.String()}})
+
+		assert.Nil(t, result["errors"])
+		assert.Equal(t, map[string]interface{}{
+			"id": cars.cars[0].ID.String(), "name": "Car 0", "engineVersion": "2.0",
+		}, result["data"].(map[string]interface{})["car"])
+	})
+
+	t.Run("Should return null for an unknown car", func(t *testing.T) {
+		result := execute(t, server, Params{Query: query, Variables: map[string]interface{}{"id": uuid.NewString()}})
+
+		assert.Nil(t, result["errors"])
+		assert.Nil(t, result["data"].(map[string]interface{})["car"])
+	})
+
+	t.Run("Should reject an invalid ID", func(t *testing.T) {
+		result := execute(t, server, Params{Query: query, Variables: map[string]interface{}{"id": "nope"}})
+
+		assert.Equal(t, "INVALID_ID", firstError(t, result)["extensions"].(map[string]interface{})["code"])
+	})
+}
+
+func TestServer_Cars(t *testing.T) {
+	const query = `query($first: Int, $after: String, $last: Int, $before: String) {
+		cars(first: $first, after: $after, last: $last, before: $before, filter: {engineVersion: "2.0"}, sort: {field: NAME, direction: ASC}) {
+			totalCount
+			edges { cursor node { name } }
+			pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+		}
+	}`
+
+	connection := func(t *testing.T, server *Server, variables map[string]interface{}) ([]string, map[string]interface{}) {
+		result := execute(t, server, Params{Query: query, Variables: variables})
+		require.Nil(t, result["errors"])
+		conn := result["data"].(map[string]interface{})["cars"].(map[string]interface{})
+		var names []string
+		for _, edge := range conn["edges"].([]interface{}) {
+			names = append(names, edge.(map[string]interface{})["node"].(map[string]interface{})["name"].(string))
+		}
+		return names, conn["pageInfo"].(map[string]interface{})
+	}
+
+	t.Run("Success - Pages forward with first and after", func(t *testing.T) {
+		cars := newFakeCarService(5)
+		server := newTestServer(t, cars)
+
+		names, page := connection(t, server, map[string]interface{}{"first": 2})
+		assert.Equal(t, []string{"Car 0", "Car 1"}, names)
+		assert.Equal(t, true, page["hasNextPage"])
+		assert.Equal(t, false, page["hasPreviousPage"])
+		assert.Equal(t, "name", cars.listed.SortBy)
+		assert.Equal(t, "asc", cars.listed.SortDir)
+		assert.Equal(t, "2.0", cars.listed.EngineVersion)
+
+		names, page = connection(t, server, map[string]interface{}{"first": 2, "after": page["endCursor"]})
+		assert.Equal(t, []string{"Car 2", "Car 3"}, names)
+		assert.Equal(t, true, page["hasPreviousPage"])
+
+		names, page = connection(t, server, map[string]interface{}{"first": 2, "after": page["endCursor"]})
+		assert.Equal(t, []string{"Car 4"}, names)
+		assert.Equal(t, false, page["hasNextPage"])
+	})
+
+	t.Run("Success - Pages back with last and before", func(t *testing.T) {
+		server := newTestServer(t, newFakeCarService(5))
+
+		names, page := connection(t, server, map[string]interface{}{"last": 2})
+		assert.Equal(t, []string{"Car 3", "Car 4"}, names)
+		assert.Equal(t, true, page["hasPreviousPage"])
+
+		names, _ = connection(t, server, map[string]interface{}{"last": 2, "before": page["startCursor"]})
+		assert.Equal(t, []string{"Car 1", "Car 2"}, names)
+	})
+
+	t.Run("Should reject an invalid page size with a field error", func(t *testing.T) {
+		server := newTestServer(t, newFakeCarService(1))
+
+		err := firstError(t, execute(t, server, Params{Query: query, Variables: map[string]interface{}{"first": -1}}))
+
+		extensions := err["extensions"].(map[string]interface{})
+		assert.Equal(t, "VALIDATION_FAILED", extensions["code"])
+		assert.Equal(t, "first", extensions["fields"].([]interface{})[0].(map[string]interface{})["field"])
+	})
+
+	t.Run("Should reject a forged cursor", func(t *testing.T) {
+		server := newTestServer(t, newFakeCarService(1))
+
+		err := firstError(t, execute(t, server, Params{Query: query, Variables: map[string]interface{}{"after": "bm9wZQ"}}))
+
+		assert.Equal(t, "Invalid cursor", err["message"])
+	})
+}
+
+func TestServer_Mutations(t *testing.T) {
+	t.Run("Success - Creates a car through the service", func(t *testing.T) {
+		cars := newFakeCarService(0)
+		server := newTestServer(t, cars)
+
+		result := execute(t, server, Params{Query: `mutation { createCar(input: {name: "Civic", engineVersion: "2.0"}) { name } }`})
+
+		assert.Nil(t, result["errors"])
+		assert.Equal(t, &dto.CreateCarRequest{Name: "Civic", EngineVersion: "2.0"}, cars.created)
+	})
+
+	t.Run("Should report invalid fields in the client's language", func(t *testing.T) {
+		cars := newFakeCarService(0)
+		server := newTestServer(t, cars)
+		trans, _ := validation.Negotiate("pt-BR")
+
+		err := firstError(t, execute(t, server, Params{
+			Query:      `mutation { createCar(input: {name: "Civic", engineVersion: "9.9"}) { id } }`,
+			Translator: trans,
+		}))
+
+		extensions := err["extensions"].(map[string]interface{})
+		assert.Equal(t, "VALIDATION_FAILED", extensions["code"])
+		field := extensions["fields"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "engineVersion", field["field"])
+		assert.Contains(t, field["message"], "engineVersion deve ser um de")
+		assert.Equal(t, []interface{}{"createCar"}, err["path"])
+		assert.Nil(t, cars.created)
+	})
+
+	t.Run("Should report service errors by code", func(t *testing.T) {
+		server := newTestServer(t, newFakeCarService(0))
+
+		err := firstError(t, execute(t, server, Params{Query: fmt.Sprintf(`mutation { deleteCar(id: %q) }`, uuid.NewString())}))
+
+		assert.Equal(t, "CAR_NOT_FOUND", err["extensions"].(map[string]interface{})["code"])
+	})
+
+	t.Run("Should hide unexpected errors", func(t *testing.T) {
+		cars := newFakeCarService(1)
+		server := newTestServer(t, cars)
+
+		err := firstError(t, execute(t, server, Params{Query: fmt.Sprintf(`mutation { deleteCar(id: %q) }`, cars.cars[0].ID)}))
+
+		assert.Equal(t, "INTERNAL_ERROR", err["extensions"].(map[string]interface{})["code"])
+		assert.NotContains(t, err["message"], "connection refused")
+	})
+
+	t.Run("Should refuse mutations when only queries are allowed", func(t *testing.T) {
+		cars := newFakeCarService(0)
+		server := newTestServer(t, cars)
+
+		err := firstError(t, execute(t, server, Params{
+			Query:       `mutation { createCar(input: {name: "Civic", engineVersion: "2.0"}) { id } }`,
+			QueriesOnly: true,
+		}))
+
+		assert.Equal(t, "INVALID_REQUEST", err["extensions"].(map[string]interface{})["code"])
+		assert.Nil(t, cars.created)
+	})
+}
+
+func TestServer_Limits(t *testing.T) {
+	server := newTestServer(t, newFakeCarService(1))
+
+	t.Run("Should reject queries nested too deeply", func(t *testing.T) {
+		shallow, err := NewServer(newFakeCarService(1), Config{MaxDepth: 3, MaxComplexity: 200})
+		require.NoError(t, err)
+
+		gqlErr := firstError(t, execute(t, shallow, Params{
+			Query: `{ cars(first: 1) { edges { ... on CarEdge { node { ...fields } } } } } fragment fields on Car { id }`,
+		}))
+
+		assert.Equal(t, "QUERY_TOO_COMPLEX", gqlErr["extensions"].(map[string]interface{})["code"])
+		assert.Contains(t, gqlErr["message"], "depth 4")
+	})
+
+	t.Run("Should reject queries selecting too many cars", func(t *testing.T) {
+		err := firstError(t, execute(t, server, Params{
+			Query:     `query($n: Int) { cars(first: $n) { edges { node { id name engineVersion createdAt } } } }`,
+			Variables: map[string]interface{}{"n": float64(100)},
+		}))
+
+		assert.Equal(t, "QUERY_TOO_COMPLEX", err["extensions"].(map[string]interface{})["code"])
+		assert.Contains(t, err["message"], "complexity 601")
+	})
+
+	t.Run("Success - Introspection is not limited", func(t *testing.T) {
+		result := execute(t, server, Params{
+			Query: `{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name } } } } } } } }`,
+		})
+
+		assert.Nil(t, result["errors"])
+	})
+}
@@ -0,0 +1,174 @@
+package grpcserver
+
+import (
+	"context"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
+	carv1 "project-simple/pkg/pb/car/v1"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WriteMethods are the calls that change cars, for rate limit policies that
+// only apply to writes
+var WriteMethods = []string{
+	carv1.CarService_CreateCar_FullMethodName,
+	carv1.CarService_UpdateCar_FullMethodName,
+	carv1.CarService_DeleteCar_FullMethodName,
+}
+
+// carServer implements car.v1.CarService over CarService
+type carServer struct {
+	carv1.UnimplementedCarServiceServer
+	cars service.CarService
+}
+
+func (s *carServer) GetCar(ctx context.Context, req *carv1.GetCarRequest) (*carv1.Car, error) {
+	id, err := carID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := s.cars.GetCarByID(ctx, id)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toProto(car), nil
+}
+
+func (s *carServer) ListCars(ctx context.Context, req *carv1.ListCarsRequest) (*carv1.ListCarsResponse, error) {
+	filter := carFilter(req.GetFilter())
+	if err := validate(ctx, &filter, "filter."); err != nil {
+		return nil, err
+	}
+	pagination := &dto.PaginationRequest{
+		Page:      int(req.GetPage()),
+		PageSize:  int(req.GetPageSize()),
+		SortBy:    req.GetSortBy(),
+		SortDir:   req.GetSortDir(),
+		CarFilter: filter,
+	}
+	if err := validate(ctx, pagination, ""); err != nil {
+		return nil, err
+	}
+
+	page, err := s.cars.GetAllCars(ctx, pagination)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	resp := &carv1.ListCarsResponse{
+		Cars:         make([]*carv1.Car, 0, len(page.Data)),
+		CurrentPage:  int32(page.Pagination.CurrentPage),
+		PageSize:     int32(page.Pagination.PageSize),
+		TotalPages:   int32(page.Pagination.TotalPages),
+		TotalRecords: page.Pagination.TotalRecords,
+	}
+	for i := range page.Data {
+		resp.Cars = append(resp.Cars, toProto(&page.Data[i]))
+	}
+	return resp, nil
+}
+
+func (s *carServer) StreamCars(req *carv1.StreamCarsRequest, stream carv1.CarService_StreamCarsServer) error {
+	ctx := stream.Context()
+	filter := carFilter(req.GetFilter())
+	if err := validate(ctx, &filter, "filter."); err != nil {
+		return err
+	}
+
+	err := s.cars.ExportCars(ctx, &dto.CarExportRequest{CarFilter: filter}, func(car *dto.CarResponse) error {
+		return stream.Send(toProto(car))
+	})
+	if err != nil {
+		return statusError(ctx, err)
+	}
+	return nil
+}
+
+func (s *carServer) CreateCar(ctx context.Context, req *carv1.CreateCarRequest) (*carv1.Car, error) {
+	createReq := &dto.CreateCarRequest{
+		Name:          req.GetName(),
+		EngineVersion: req.GetEngineVersion(),
+	}
+	if err := validate(ctx, createReq, ""); err != nil {
+		return nil, err
+	}
+
+	car, err := s.cars.CreateCar(ctx, createReq)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toProto(car), nil
+}
+
+func (s *carServer) UpdateCar(ctx context.Context, req *carv1.UpdateCarRequest) (*carv1.Car, error) {
+	id, err := carID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &dto.UpdateCarRequest{
+		Name:          req.GetName(),
+		EngineVersion: req.GetEngineVersion(),
+	}
+	if err := validate(ctx, updateReq, ""); err != nil {
+		return nil, err
+	}
+
+	car, err := s.cars.UpdateCar(ctx, id, updateReq)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toProto(car), nil
+}
+
+func (s *carServer) DeleteCar(ctx context.Context, req *carv1.DeleteCarRequest) (*emptypb.Empty, error) {
+	id, err := carID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cars.DeleteCar(ctx, id); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func carID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, errorStatus(apperror.InvalidID, "Invalid car ID format").Err()
+	}
+	return id, nil
+}
+
+func carFilter(filter *carv1.CarFilter) dto.CarFilter {
+	return dto.CarFilter{
+		Name:          filter.GetName(),
+		EngineVersion: filter.GetEngineVersion(),
+	}
+}
+
+func toProto(car *dto.CarResponse) *carv1.Car {
+	return &carv1.Car{
+		Id:            car.ID.String(),
+		Name:          car.Name,
+		EngineVersion: car.EngineVersion,
+		CreateTime:    timestamp(car.CreatedAt),
+		UpdateTime:    timestamp(car.UpdatedAt),
+	}
+}
+
+// timestamp converts the RFC 3339 times of CarResponse
+func timestamp(value string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,103 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"project-simple/internal/logging"
+	"project-simple/internal/validation"
+	"project-simple/pkg/apperror"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the ErrorInfo domain of every error the server reports
+const errorDomain = "car-api"
+
+// grpcCodes maps the HTTP status of a catalog code to the gRPC code that
+// means the same
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusNotAcceptable:         codes.InvalidArgument,
+	http.StatusConflict:              codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnsupportedMediaType:  codes.InvalidArgument,
+	http.StatusUnprocessableEntity:   codes.FailedPrecondition,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// errorStatus is the status of a catalog error. Its ErrorInfo reason is the
+// code, which clients match on as REST clients do.
+func errorStatus(code apperror.Code, message string, details ...protoadapt.MessageV1) *status.Status {
+	grpcCode, ok := grpcCodes[apperror.Lookup(code).Status]
+	if !ok {
+		grpcCode = codes.Internal
+	}
+	if code == apperror.ValidationFailed {
+		grpcCode = codes.InvalidArgument
+	}
+
+	st := status.New(grpcCode, message)
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(code), Domain: errorDomain}}, details...)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// statusError turns a service error into a status, in the same terms the
+// REST handlers report it
+func statusError(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	// A call whose context ended fails because of it, whatever err says
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if code, ok := apperror.CodeOf(err); ok {
+		return errorStatus(code, apperror.Lookup(code).Title).Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errorStatus(apperror.Timeout, "The request took too long to complete").Err()
+	}
+
+	logging.FromContext(ctx).Error("gRPC call failed", slog.Any("error", err))
+	return errorStatus(apperror.InternalError, apperror.Lookup(apperror.InternalError).Title).Err()
+}
+
+// validate checks v against its binding rules. Invalid fields are listed in
+// a BadRequest, in the language of the call's accept-language metadata and
+// named from the request message by prefix.
+func validate(ctx context.Context, v interface{}, prefix string) error {
+	err := binding.Validator.ValidateStruct(v)
+	if err == nil {
+		return nil
+	}
+
+	trans, _ := validation.Negotiate(firstValue(ctx, acceptLanguageKey))
+	fields := validation.Translate(err, trans)
+	if fields == nil {
+		return statusError(ctx, err)
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, field := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       prefix + field.Field,
+			Description: field.Message,
+		})
+	}
+	return errorStatus(apperror.ValidationFailed, "Validation failed",
+		&errdetails.BadRequest{FieldViolations: violations}).Err()
+}
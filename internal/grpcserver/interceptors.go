@@ -0,0 +1,251 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net"
+	"project-simple/internal/logging"
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/requestctx"
+	"project-simple/pkg/apperror"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Metadata keys, named after the REST headers they mirror
+const (
+	requestIDKey      = "x-request-id"
+	actorKey          = "x-actor"
+	authorizationKey  = "authorization"
+	acceptLanguageKey = "accept-language"
+)
+
+// interceptor wraps a call, unary or streaming alike. It runs the rest of
+// the chain with next, passing the context the call should continue with.
+type interceptor func(ctx context.Context, method string, next func(context.Context) error) error
+
+func unary(interceptors ...interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var resp interface{}
+		err := chain(ctx, info.FullMethod, interceptors, func(ctx context.Context) error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+func stream(interceptors ...interceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return chain(ss.Context(), info.FullMethod, interceptors, func(ctx context.Context) error {
+			return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+}
+
+func chain(ctx context.Context, method string, interceptors []interceptor, call func(context.Context) error) error {
+	if len(interceptors) == 0 {
+		return call(ctx)
+	}
+	return interceptors[0](ctx, method, func(ctx context.Context) error {
+		return chain(ctx, method, interceptors[1:], call)
+	})
+}
+
+// serverStream hands a streaming handler the context built by interceptors
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// recovery turns a panic into an internal error instead of crashing the
+// process
+func recovery(ctx context.Context, method string, next func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "panic recovered",
+				slog.Any("panic", r),
+				slog.String("stack", string(debug.Stack())))
+
+			err = errorStatus(apperror.InternalError, "An unexpected error occurred").Err()
+		}
+	}()
+	return next(ctx)
+}
+
+// requestContext stores request metadata on the context, as the RequestID
+// and RequestContext middleware do, and echoes the request ID in the
+// response header
+func requestContext(ctx context.Context, method string, next func(context.Context) error) error {
+	requestID := firstValue(ctx, requestIDKey)
+	if !requestctx.ValidRequestID(requestID) {
+		requestID = uuid.New().String()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	ctx = requestctx.WithMetadata(ctx, requestctx.Metadata{
+		RequestID: requestID,
+		Actor:     requestctx.Actor(firstValue(ctx, actorKey)),
+		ClientIP:  clientIP(ctx),
+	})
+	return next(ctx)
+}
+
+// logger attaches a call-scoped logger to the context and logs every call
+// once it completes, as the Logger middleware does for requests
+func logger(ctx context.Context, method string, next func(context.Context) error) error {
+	startTime := time.Now()
+	md := requestctx.FromContext(ctx)
+
+	attrs := []any{
+		slog.String("request_id", md.RequestID),
+		slog.String("method", method),
+	}
+	if md.Actor != "" {
+		attrs = append(attrs, slog.String("user", md.Actor))
+	}
+
+	log := slog.Default().With(attrs...)
+	err := next(logging.WithLogger(ctx, log))
+
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	log.LogAttrs(ctx, level, "request completed",
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(startTime)),
+		slog.String("client_ip", md.ClientIP),
+	)
+	return err
+}
+
+// auth requires the bearer token when one is set, as AdminAuth does, and
+// marks the calls presenting it as authenticated. Health checks are always
+// allowed, like the REST probes.
+func auth(token string) interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		if token == "" || isHealthCheck(method) {
+			return next(ctx)
+		}
+
+		provided, ok := strings.CutPrefix(firstValue(ctx, authorizationKey), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return errorStatus(apperror.Unauthorized, "A valid token is required").Err()
+		}
+
+		md := requestctx.FromContext(ctx)
+		md.Authenticated = true
+		return next(requestctx.WithMetadata(ctx, md))
+	}
+}
+
+// RateLimitPolicy limits the calls it matches, like its REST counterpart.
+// Policies sharing a name with a REST one share its buckets, so switching
+// protocol does not double a client's allowance.
+type RateLimitPolicy struct {
+	// Name identifies the policy's buckets
+	Name  string
+	Limit ratelimit.Limit
+	// Methods restricts the policy to these full method names, such as
+	// WriteMethods; empty matches all
+	Methods []string
+	// PerPrincipal counts authenticated calls per x-actor instead of per
+	// client IP. Other calls are counted per IP whatever their x-actor.
+	PerPrincipal bool
+}
+
+func (p RateLimitPolicy) matches(method string) bool {
+	return len(p.Methods) == 0 || slices.Contains(p.Methods, method)
+}
+
+func (p RateLimitPolicy) key(md requestctx.Metadata) string {
+	if p.PerPrincipal && md.Authenticated && md.Actor != "" {
+		return p.Name + ":actor:" + md.Actor
+	}
+	return p.Name + ":ip:" + md.ClientIP
+}
+
+// rateLimit applies every matching policy in order and rejects the call as
+// soon as one is exhausted, with a RetryInfo saying when to try again. As
+// for REST, calls are let through when the store fails, and health checks
+// are never limited.
+func rateLimit(store ratelimit.Store, policies []RateLimitPolicy) interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		if isHealthCheck(method) {
+			return next(ctx)
+		}
+
+		md := requestctx.FromContext(ctx)
+		for _, p := range policies {
+			if !p.matches(method) {
+				continue
+			}
+
+			res, err := store.Allow(ctx, p.key(md), p.Limit)
+			if err != nil {
+				logging.FromContext(ctx).Warn("Rate limit store failed, allowing request",
+					slog.String("policy", p.Name), slog.Any("error", err))
+				continue
+			}
+			if !res.Allowed {
+				return errorStatus(apperror.RateLimited, "Too many requests. Please try again later.",
+					&errdetails.RetryInfo{RetryDelay: durationpb.New(res.RetryAfter)},
+					&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+						Subject:     p.Name,
+						Description: fmt.Sprintf("%d requests per %s", p.Limit.Requests, p.Limit.Period),
+					}}},
+				).Err()
+			}
+		}
+		return next(ctx)
+	}
+}
+
+func isHealthCheck(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// firstValue returns the first incoming metadata value for key
+func firstValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
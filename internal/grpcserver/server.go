@@ -0,0 +1,93 @@
+// Package grpcserver serves the car domain over gRPC as car.v1.CarService.
+// Calls go through CarService like the REST handlers, and interceptors give
+// them the same request IDs, logs, authentication and rate limits.
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/service"
+	carv1 "project-simple/pkg/pb/car/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Config sets how a server admits calls
+type Config struct {
+	// AuthToken is required as "authorization: Bearer <token>" metadata on
+	// every call but health checks; empty disables the check
+	AuthToken string
+	// RateLimits apply in order, as the REST policies do
+	RateLimits []RateLimitPolicy
+}
+
+// Server is the gRPC server, with the standard health and reflection
+// services next to car.v1.CarService
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+func NewServer(cars service.CarService, store ratelimit.Store, cfg Config) *Server {
+	// Order matters, as for the REST middleware: the logger must see the
+	// request ID, and rejected calls must still be logged
+	interceptors := []interceptor{
+		recovery,
+		requestContext,
+		logger,
+		auth(cfg.AuthToken),
+		rateLimit(store, cfg.RateLimits),
+	}
+
+	s := &Server{
+		grpc: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(unary(interceptors...)),
+			grpc.ChainStreamInterceptor(stream(interceptors...)),
+		),
+		health: health.NewServer(),
+	}
+
+	carv1.RegisterCarServiceServer(s.grpc, &carServer{cars: cars})
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+	s.health.SetServingStatus(carv1.CarService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return s
+}
+
+// Serve accepts connections on lis until the server stops
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Drain reports every service as not serving, so clients watching health
+// checks move to other instances while calls are still accepted
+func (s *Server) Drain() {
+	s.health.Shutdown()
+}
+
+// Shutdown stops accepting calls and waits for running ones to finish, as
+// http.Server.Shutdown does. Calls still running when ctx is done, such as
+// long streams, are cancelled and ctx's error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.Drain()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
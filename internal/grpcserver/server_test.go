@@ -0,0 +1,337 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/requestctx"
+	"project-simple/internal/service"
+	"project-simple/internal/validation"
+	carv1 "project-simple/pkg/pb/car/v1"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeCarService keeps cars in memory and records the metadata of the last
+// call that changed them
+type fakeCarService struct {
+	service.CarService
+	cars     []dto.CarResponse
+	metadata requestctx.Metadata
+	listed   *dto.PaginationRequest
+	block    chan struct{}
+}
+
+func (f *fakeCarService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	for _, car := range f.cars {
+		if car.ID == id {
+			return &car, nil
+		}
+	}
+	return nil, service.ErrCarNotFound
+}
+
+func (f *fakeCarService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	pagination.SetDefaults()
+	f.listed = pagination
+	return &dto.PaginatedResponse{
+		Data:       f.cars,
+		Pagination: dto.PaginationMeta{CurrentPage: pagination.Page, PageSize: pagination.PageSize, TotalPages: 1, TotalRecords: int64(len(f.cars))},
+	}, nil
+}
+
+func (f *fakeCarService) ExportCars(ctx context.Context, filter *dto.CarExportRequest, fn func(*dto.CarResponse) error) error {
+	for i := range f.cars {
+		if err := fn(&f.cars[i]); err != nil {
+			return err
+		}
+	}
+	if f.block != nil {
+		close(f.block)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (f *fakeCarService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	f.metadata = requestctx.FromContext(ctx)
+	car := dto.CarResponse{ID: uuid.New(), Name: req.Name, EngineVersion: req.EngineVersion, CreatedAt: "2024-01-01T10:00:00Z", UpdatedAt: "2024-01-01T10:00:00Z"}
+	f.cars = append(f.cars, car)
+	return &car, nil
+}
+
+func (f *fakeCarService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	if _, err := f.GetCarByID(ctx, id); err != nil {
+		return err
+	}
+	return errors.New("connection refused")
+}
+
+// startServer serves cars over an in-memory listener and returns a client
+// connection to it
+func startServer(t *testing.T, cars service.CarService, cfg Config) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	require.NoError(t, validation.Setup())
+
+	server := NewServer(cars, ratelimit.NewMemoryStore(), cfg)
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() { server.grpc.Stop() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return server, conn
+}
+
+// errorInfo returns the status of err and the catalog code it carries
+func errorInfo(t *testing.T, err error) (*status.Status, string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "expected a status, got %v", err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return st, info.GetReason()
+		}
+	}
+	return st, ""
+}
+
+func TestCarServer(t *testing.T) {
+	cars := &fakeCarService{cars: []dto.CarResponse{
+		{ID: uuid.New(), Name: "Civic", EngineVersion: "2.0", CreatedAt: "2024-01-01T10:00:00Z", UpdatedAt: "2024-01-02T10:00:00Z"},
+		{ID: uuid.New(), Name: "Corolla", EngineVersion: "1.8", CreatedAt: "2024-01-03T10:00:00Z", UpdatedAt: "2024-01-03T10:00:00Z"},
+	}}
+	_, conn := startServer(t, cars, Config{})
+	client := carv1.NewCarServiceClient(conn)
+	ctx := context.Background()
+
+	t.Run("Success - Gets a car and echoes the request ID", func(t *testing.T) {
+		var header metadata.MD
+		car, err := client.GetCar(metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-123"),
+			&carv1.GetCarRequest{Id: cars.cars[0].ID.String()}, grpc.Header(&header))
+
+		require.NoError(t, err)
+		assert.Equal(t, "Civic", car.GetName())
+		assert.Equal(t, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), car.GetUpdateTime().AsTime())
+		assert.Equal(t, []string{"req-123"}, header.Get("x-request-id"))
+	})
+
+	t.Run("Should report missing cars by code", func(t *testing.T) {
+		_, err := client.GetCar(ctx, &carv1.GetCarRequest{Id: uuid.NewString()})
+
+		st, reason := errorInfo(t, err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "CAR_NOT_FOUND", reason)
+	})
+
+	t.Run("Should reject an invalid ID", func(t *testing.T) {
+		_, err := client.DeleteCar(ctx, &carv1.DeleteCarRequest{Id: "nope"})
+
+		st, reason := errorInfo(t, err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, "INVALID_ID", reason)
+	})
+
+	t.Run("Success - Lists a page of cars", func(t *testing.T) {
+		resp, err := client.ListCars(ctx, &carv1.ListCarsRequest{PageSize: 5, SortBy: "name", Filter: &carv1.CarFilter{EngineVersion: "2.0"}})
+
+		require.NoError(t, err)
+		assert.Len(t, resp.GetCars(), 2)
+		assert.Equal(t, int64(2), resp.GetTotalRecords())
+		assert.Equal(t, "2.0", cars.listed.EngineVersion)
+		assert.Equal(t, "name", cars.listed.SortBy)
+	})
+
+	t.Run("Should name invalid fields after the request message", func(t *testing.T) {
+		_, err := client.ListCars(metadata.AppendToOutgoingContext(ctx, "accept-language", "pt-BR"),
+			&carv1.ListCarsRequest{Filter: &carv1.CarFilter{EngineVersion: "9.9"}})
+
+		st, reason := errorInfo(t, err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, "VALIDATION_FAILED", reason)
+		var badRequest *errdetails.BadRequest
+		for _, detail := range st.Details() {
+			if br, ok := detail.(*errdetails.BadRequest); ok {
+				badRequest = br
+			}
+		}
+		require.NotNil(t, badRequest)
+		assert.Equal(t, "filter.engine_version", badRequest.GetFieldViolations()[0].GetField())
+		assert.Contains(t, badRequest.GetFieldViolations()[0].GetDescription(), "deve ser um de")
+	})
+
+	t.Run("Success - Streams every car", func(t *testing.T) {
+		stream, err := client.StreamCars(ctx, &carv1.StreamCarsRequest{})
+		require.NoError(t, err)
+
+		var names []string
+		for {
+			car, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, car.GetName())
+		}
+		assert.Equal(t, []string{"Civic", "Corolla"}, names)
+	})
+
+	t.Run("Success - Creates a car with the caller's metadata", func(t *testing.T) {
+		car, err := client.CreateCar(metadata.AppendToOutgoingContext(ctx, "x-actor", "alice", "x-request-id", "req-456"),
+			&carv1.CreateCarRequest{Name: "Golf", EngineVersion: "1.4"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Golf", car.GetName())
+		assert.Equal(t, "alice", cars.metadata.Actor)
+		assert.Equal(t, "req-456", cars.metadata.RequestID)
+	})
+
+	t.Run("Should hide unexpected errors", func(t *testing.T) {
+		_, err := client.DeleteCar(ctx, &carv1.DeleteCarRequest{Id: cars.cars[0].ID.String()})
+
+		st, reason := errorInfo(t, err)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "INTERNAL_ERROR", reason)
+		assert.NotContains(t, st.Message(), "connection refused")
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	_, conn := startServer(t, &fakeCarService{}, Config{AuthToken: "secret"})
+	client := carv1.NewCarServiceClient(conn)
+
+	t.Run("Should reject calls without the token", func(t *testing.T) {
+		_, err := client.ListCars(context.Background(), &carv1.ListCarsRequest{})
+
+		st, reason := errorInfo(t, err)
+		assert.Equal(t, codes.Unauthenticated, st.Code())
+		assert.Equal(t, "UNAUTHORIZED", reason)
+	})
+
+	t.Run("Success - Accepts calls with the token", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+
+		_, err := client.ListCars(ctx, &carv1.ListCarsRequest{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - Health checks need no token", func(t *testing.T) {
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+			&healthpb.HealthCheckRequest{Service: carv1.CarService_ServiceDesc.ServiceName})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	cars := &fakeCarService{}
+	_, conn := startServer(t, cars, Config{RateLimits: []RateLimitPolicy{{
+		Name:         "writes",
+		Limit:        ratelimit.Limit{Requests: 1, Period: time.Minute},
+		Methods:      WriteMethods,
+		PerPrincipal: true,
+	}}})
+	client := carv1.NewCarServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-actor", "alice")
+	create := &carv1.CreateCarRequest{Name: "Golf", EngineVersion: "1.4"}
+
+	_, err := client.CreateCar(ctx, create)
+	require.NoError(t, err)
+
+	t.Run("Should reject writes over the limit with a retry delay", func(t *testing.T) {
+		_, err := client.CreateCar(ctx, create)
+
+		st, reason := errorInfo(t, err)
+		assert.Equal(t, codes.ResourceExhausted, st.Code())
+		assert.Equal(t, "RATE_LIMITED", reason)
+		var retry *errdetails.RetryInfo
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				retry = info
+			}
+		}
+		require.NotNil(t, retry)
+		assert.Positive(t, retry.GetRetryDelay().AsDuration())
+	})
+
+	t.Run("Should not give a fresh bucket to an unauthenticated x-actor", func(t *testing.T) {
+		_, err := client.CreateCar(metadata.AppendToOutgoingContext(context.Background(), "x-actor", "bob"), create)
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("Success - Reads are not limited", func(t *testing.T) {
+		_, err := client.ListCars(ctx, &carv1.ListCarsRequest{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - Authenticated principals have their own buckets", func(t *testing.T) {
+		_, conn := startServer(t, cars, Config{AuthToken: "secret-token", RateLimits: []RateLimitPolicy{{
+			Name:         "writes",
+			Limit:        ratelimit.Limit{Requests: 1, Period: time.Minute},
+			Methods:      WriteMethods,
+			PerPrincipal: true,
+		}}})
+		client := carv1.NewCarServiceClient(conn)
+		as := func(actor string) context.Context {
+			return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret-token", "x-actor", actor)
+		}
+
+		_, err := client.CreateCar(as("alice"), create)
+		require.NoError(t, err)
+		_, err = client.CreateCar(as("alice"), create)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		_, err = client.CreateCar(as("bob"), create)
+		assert.NoError(t, err)
+	})
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Run("Should cancel streams still running at the deadline", func(t *testing.T) {
+		cars := &fakeCarService{
+			cars:  []dto.CarResponse{{ID: uuid.New(), Name: "Civic", EngineVersion: "2.0"}},
+			block: make(chan struct{}),
+		}
+		server, conn := startServer(t, cars, Config{})
+
+		stream, err := carv1.NewCarServiceClient(conn).StreamCars(context.Background(), &carv1.StreamCarsRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		<-cars.block
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+		_, err = stream.Recv()
+		assert.Error(t, err)
+
+		resp, err := server.health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+	})
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"project-simple/internal/config"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/logging"
+	"project-simple/internal/querystats"
+	"project-simple/pkg/response"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves operational and debug endpoints on the admin server only
+type AdminHandler struct {
+	cfg        *config.Config
+	queryStats *querystats.Recorder
+}
+
+func NewAdminHandler(cfg *config.Config, queryStats *querystats.Recorder) *AdminHandler {
+	return &AdminHandler{
+		cfg:        cfg,
+		queryStats: queryStats,
+	}
+}
+
+// GetQueryStats godoc
+// @Summary Slowest query shapes
+// @Description Get the N slowest normalized query shapes with rolling percentiles over their most recent executions. Served on the admin server.
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of shapes (default: 10, max: 100)" minimum(1) maximum(100)
+// @Param sort query string false "Order: p95 (default), p99, max, total or count"
+// @Success 200 {object} response.Response{data=[]querystats.ShapeStats}
+// @Failure 422 {object} response.ErrorResponse
+// @Router /admin/queries [get]
+func (h *AdminHandler) GetQueryStats(c *gin.Context) {
+	var req dto.QueryStatsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	req.SetDefaults()
+
+	response.Success(c, "Query statistics retrieved successfully", h.queryStats.Top(req.Limit, req.Sort))
+}
+
+// ResetQueryStats godoc
+// @Summary Reset query statistics
+// @Description Discard all recorded query statistics, e.g. after a fix is deployed. Served on the admin server.
+// @Tags admin
+// @Success 204 "No Content"
+// @Router /admin/queries [delete]
+func (h *AdminHandler) ResetQueryStats(c *gin.Context) {
+	h.queryStats.Reset()
+	response.NoContent(c)
+}
+
+// Pprof serves the net/http/pprof endpoints under /debug/pprof/
+func (h *AdminHandler) Pprof(c *gin.Context) {
+	switch c.Param("profile") {
+	case "/cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "/profile":
+		h.liftWriteDeadline(c)
+		pprof.Profile(c.Writer, c.Request)
+	case "/symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "/trace":
+		h.liftWriteDeadline(c)
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// Index also serves named profiles such as /heap and /goroutine
+		pprof.Index(c.Writer, c.Request)
+	}
+}
+
+// Goroutines godoc
+// @Summary Goroutine dump
+// @Description Full stack traces of all goroutines in plain text. Served on the admin server.
+// @Tags admin
+// @Produce plain
+// @Success 200 {string} string "Goroutine stacks"
+// @Router /debug/goroutines [get]
+func (h *AdminHandler) Goroutines(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := rpprof.Lookup("goroutine").WriteTo(c.Writer, 2); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to write goroutine dump", slog.Any("error", err))
+	}
+}
+
+// GetRuntimeStats godoc
+// @Summary Runtime statistics
+// @Description Go runtime, memory and garbage collector statistics. Served on the admin server.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.RuntimeStatsResponse}
+// @Router /debug/runtime [get]
+func (h *AdminHandler) GetRuntimeStats(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := dto.RuntimeStatsResponse{
+		GoVersion:    runtime.Version(),
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapSys:      mem.HeapSys,
+		HeapObjects:  mem.HeapObjects,
+		TotalAlloc:   mem.TotalAlloc,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		PauseTotalMs: float64(mem.PauseTotalNs) / float64(time.Millisecond),
+		NextGC:       mem.NextGC,
+		GCCPUPercent: mem.GCCPUFraction * 100,
+	}
+	if mem.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC)).UTC().Format(time.RFC3339)
+	}
+
+	response.Success(c, "Runtime statistics retrieved successfully", stats)
+}
+
+// GetConfig godoc
+// @Summary Effective configuration
+// @Description The configuration the server is running with. Passwords, tokens and other secrets are redacted. Served on the admin server.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.Response{data=map[string]interface{}}
+// @Router /debug/config [get]
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	response.Success(c, "Configuration retrieved successfully", h.cfg.Redacted())
+}
+
+// GetBuildInfo godoc
+// @Summary Build information
+// @Description Go version, module version, VCS revision and dependencies of the running binary. Served on the admin server.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.BuildInfoResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Router /debug/build [get]
+func (h *AdminHandler) GetBuildInfo(c *gin.Context) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		response.NotFound(c, "Build information is not available")
+		return
+	}
+
+	build := dto.BuildInfoResponse{
+		GoVersion:    info.GoVersion,
+		Path:         info.Path,
+		Version:      info.Main.Version,
+		Settings:     make(map[string]string, len(info.Settings)),
+		Dependencies: make(map[string]string, len(info.Deps)),
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.RevisionTime = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		default:
+			build.Settings[setting.Key] = setting.Value
+		}
+	}
+	for _, dep := range info.Deps {
+		build.Dependencies[dep.Path] = dep.Version
+	}
+
+	response.Success(c, "Build information retrieved successfully", build)
+}
+
+// GetLogLevel godoc
+// @Summary Current log level
+// @Description Served on the admin server.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.LogLevelResponse}
+// @Router /debug/log-level [get]
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	response.Success(c, "Log level retrieved successfully", dto.LogLevelResponse{
+		Level: logging.Level.Level().String(),
+	})
+}
+
+// SetLogLevel godoc
+// @Summary Change the log level
+// @Description Change the log level at runtime without a restart. The change is not persisted. Served on the admin server.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.LogLevelRequest true "New level"
+// @Success 200 {object} response.Response{data=dto.LogLevelResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Router /debug/log-level [put]
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req dto.LogLevelRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := logging.SetLevel(req.Level); err != nil {
+		response.BadRequest(c, "Invalid log level", err.Error())
+		return
+	}
+
+	response.Success(c, "Log level updated successfully", dto.LogLevelResponse{
+		Level: logging.Level.Level().String(),
+	})
+}
+
+// liftWriteDeadline lets CPU profiles and traces run longer than the admin
+// server's write timeout
+func (h *AdminHandler) liftWriteDeadline(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"project-simple/internal/config"
+	"project-simple/internal/logging"
+	"project-simple/internal/querystats"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{Host: "db", Password: "hunter2"},
+		Admin:    config.AdminConfig{Addr: "0.0.0.0:6060", Token: "s3cret"},
+	}
+	h := NewAdminHandler(cfg, querystats.NewRecorder(10, 10))
+
+	router := gin.New()
+	router.GET("/debug/config", h.GetConfig)
+	router.GET("/debug/runtime", h.GetRuntimeStats)
+	router.GET("/debug/log-level", h.GetLogLevel)
+	router.PUT("/debug/log-level", h.SetLogLevel)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Should redact secrets in the config dump", func(t *testing.T) {
+		w := serve("GET", "/debug/config", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"db"`)
+		assert.NotContains(t, w.Body.String(), "hunter2")
+		assert.NotContains(t, w.Body.String(), "s3cret")
+		assert.Contains(t, w.Body.String(), logging.Redacted)
+	})
+
+	t.Run("Success - Runtime stats", func(t *testing.T) {
+		w := serve("GET", "/debug/runtime", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"goroutines"`)
+	})
+
+	t.Run("Should change the log level at runtime", func(t *testing.T) {
+		previous := logging.Level.Level()
+		t.Cleanup(func() { logging.Level.Set(previous) })
+
+		w := serve("PUT", "/debug/log-level", `{"level":"debug"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, slog.LevelDebug, logging.Level.Level())
+
+		w = serve("GET", "/debug/log-level", "")
+		assert.Contains(t, w.Body.String(), `"DEBUG"`)
+	})
+
+	t.Run("Should reject an unknown log level", func(t *testing.T) {
+		w := serve("PUT", "/debug/log-level", `{"level":"verbose"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
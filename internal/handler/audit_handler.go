@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/service"
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLogs godoc
+// @Summary List audit log entries
+// @Description Get a paginated list of audit log entries, newest first, optionally filtered by entity, actor and time range
+// @Tags audit
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param entity_id query string false "Entity ID (UUID)"
+// @Param actor query string false "Actor who performed the change"
+// @Param from query string false "Start of time range (RFC 3339)"
+// @Param to query string false "End of time range (RFC 3339)"
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param page_size query int false "Items per page (default: 10, max: 100)" minimum(1) maximum(100)
+// @Success 200 {object} response.Response{data=dto.AuditLogListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/audit [get]
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	var filter dto.AuditLogFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	result, err := h.auditService.ListAuditLogs(c.Request.Context(), &filter)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve audit logs")
+		return
+	}
+
+	response.Success(c, "Audit logs retrieved successfully", result)
+}
@@ -1,23 +1,41 @@
 package handler
 
 import (
+	"compress/gzip"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
 	"project-simple/internal/domain/dto"
+	"project-simple/internal/logging"
 	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
 	"project-simple/pkg/response"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 )
 
+// exportFlushRows is how many exported cars are buffered before a flush
+const exportFlushRows = 500
+
 type CarHandler struct {
-	carService service.CarService
+	carService         service.CarService
+	jobService         service.JobService
+	exportWriteTimeout time.Duration
 }
 
-func NewCarHandler(carService service.CarService) *CarHandler {
+func NewCarHandler(carService service.CarService, jobService service.JobService, exportWriteTimeout time.Duration) *CarHandler {
 	return &CarHandler{
-		carService: carService,
+		carService:         carService,
+		jobService:         jobService,
+		exportWriteTimeout: exportWriteTimeout,
 	}
 }
 
@@ -25,19 +43,20 @@ func NewCarHandler(carService service.CarService) *CarHandler {
 // @Summary Create a new car
 // @Description Create a new car with the provided information
 // @Tags cars
-// @Accept json
-// @Produce json
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
 // @Param car body dto.CreateCarRequest true "Car information"
 // @Success 201 {object} response.Response{data=dto.CarResponse}
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /api/v1/cars [post]
 func (h *CarHandler) CreateCar(c *gin.Context) {
 	var req dto.CreateCarRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		validationErrors := h.formatValidationErrors(err)
+	if err := response.Bind(c, &req); err != nil {
+		validationErrors := formatValidationErrors(c, err)
 		if validationErrors != nil {
 			response.UnprocessableEntity(c, "Validation failed", validationErrors)
 			return
@@ -46,42 +65,173 @@ func (h *CarHandler) CreateCar(c *gin.Context) {
 		return
 	}
 
-	car, err := h.carService.CreateCar(&req)
+	car, err := h.carService.CreateCar(c.Request.Context(), &req)
 	if err != nil {
-		response.InternalServerError(c, "Failed to create car")
+		respondError(c, err, "Failed to create car")
 		return
 	}
 
 	response.Created(c, "Car created successfully", car)
 }
 
+// ImportCars godoc
+// @Summary Import cars from a file
+// @Description Create cars from a CSV or NDJSON file, sent as the request body or as the multipart field "file". Columns are matched to car fields through header aliases (e.g. model, motor, hubraum) and every row is validated like POST /cars. Rows with an id update the matching car according to on_conflict; rows without one create a car. Rows are committed in batches of 500, and the report lists the outcome of each row. With async=true the file is checked and queued as a job, answered with 202 and a Location to poll; the report is the job's result.
+// @Tags cars
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param dry_run query bool false "Validate and apply the rows, then roll back"
+// @Param on_conflict query string false "What to do when a row's id matches an existing car" Enums(fail, skip, update) default(fail)
+// @Param async query bool false "Queue the import as a job instead of waiting for it"
+// @Param file formData file false "CSV or NDJSON file, when uploading a form"
+// @Success 200 {object} response.Response{data=dto.ImportCarsResponse}
+// @Success 202 {object} response.Response{data=dto.JobResponse}
+// @Header 202 {string} Location "URL of the job to poll"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 415 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/cars/import [post]
+func (h *CarHandler) ImportCars(c *gin.Context) {
+	var query dto.ImportCarsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Invalid import options", err.Error())
+		return
+	}
+
+	body, format, err := openImport(c)
+	if err == nil {
+		defer body.Close()
+	}
+	var file *importFile
+	if err == nil {
+		file, err = parseImport(body, format)
+	}
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	req := &dto.ImportCarsRequest{
+		Rows:       make([]dto.ImportCarRow, 0, len(file.rows)),
+		OnConflict: query.OnConflict,
+		DryRun:     query.DryRun,
+	}
+	for _, row := range file.rows {
+		if err := binding.Validator.ValidateStruct(&row); err != nil {
+			file.invalid = append(file.invalid, dto.ImportRowResult{
+				Row:    row.Row,
+				Status: dto.ImportStatusFailed,
+				Errors: formatValidationErrors(c, err),
+			})
+			continue
+		}
+		req.Rows = append(req.Rows, row)
+	}
+
+	if query.Async {
+		h.enqueueImport(c, req, file)
+		return
+	}
+
+	report, err := h.carService.ImportCars(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Failed to import cars")
+		return
+	}
+
+	// Rows that never reached the service are reported in file order with the rest
+	report.Merge(file.invalid)
+	report.IgnoredColumns = file.ignored
+
+	message := "Cars imported"
+	if query.DryRun {
+		message = "Import checked; nothing was saved"
+	}
+	response.Success(c, message, report)
+}
+
+// enqueueImport queues the valid rows as a job. Rows without an id are given
+// one now, so a retried job does not create them twice.
+func (h *CarHandler) enqueueImport(c *gin.Context, req *dto.ImportCarsRequest, file *importFile) {
+	for i := range req.Rows {
+		if req.Rows[i].ID == "" {
+			req.Rows[i].ID = uuid.NewString()
+			req.Rows[i].AssignedID = true
+		}
+	}
+
+	job, err := h.jobService.EnqueueJob(c.Request.Context(), dto.JobKindCarImport, dto.CarImportJob{
+		Rows:           req.Rows,
+		Invalid:        file.invalid,
+		IgnoredColumns: file.ignored,
+		OnConflict:     req.OnConflict,
+		DryRun:         req.DryRun,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to queue import")
+		return
+	}
+
+	respondJobAccepted(c, "Import queued", job)
+}
+
+// respondImportError reports an upload that could not be read as a whole
+func respondImportError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	var fileErr *importFileError
+	switch {
+	case errors.As(err, &tooLarge):
+		response.Fail(c, apperror.RequestTooLarge, fmt.Sprintf("The file exceeds the %d byte import limit", tooLarge.Limit), nil)
+	case errors.Is(err, errImportUnsupported):
+		response.Fail(c, apperror.UnsupportedMediaType, "Upload CSV (text/csv) or NDJSON (application/x-ndjson)", nil)
+	case errors.Is(err, errImportFileRequired):
+		response.BadRequest(c, "Invalid request body", err.Error())
+	case errors.As(err, &fileErr):
+		response.Fail(c, apperror.ValidationFailed, fileErr.message, fileErr.details)
+	default:
+		response.BadRequest(c, "Invalid import file", err.Error())
+	}
+}
+
 // GetCarByID godoc
 // @Summary Get a car by ID
 // @Description Get detailed information about a specific car
 // @Tags cars
-// @Accept json
-// @Produce json
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
 // @Param id path string true "Car ID (UUID)"
+// @Param as_of query string false "Return the car as it was at this time (RFC 3339)"
 // @Success 200 {object} response.Response{data=dto.CarResponse}
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /api/v1/cars/{id} [get]
 func (h *CarHandler) GetCarByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		response.BadRequest(c, "Invalid car ID format", nil)
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
+		return
+	}
+
+	var query dto.AsOfRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Invalid as_of timestamp, expected RFC 3339", err.Error())
 		return
 	}
 
-	car, err := h.carService.GetCarByID(id)
+	var car *dto.CarResponse
+	if query.AsOf != nil {
+		car, err = h.carService.GetCarAsOf(c.Request.Context(), id, *query.AsOf)
+	} else {
+		car, err = h.carService.GetCarByID(c.Request.Context(), id)
+	}
 	if err != nil {
-		if errors.Is(err, service.ErrCarNotFound) {
-			response.NotFound(c, "Car not found")
-			return
-		}
-		response.InternalServerError(c, "Failed to retrieve car")
+		respondError(c, err, "Failed to retrieve car")
 		return
 	}
 
@@ -90,23 +240,26 @@ func (h *CarHandler) GetCarByID(c *gin.Context) {
 
 // GetAllCars godoc
 // @Summary Get all cars with pagination
-// @Description Get a paginated list of all cars with optional sorting
+// @Description Get a paginated list of all cars with optional filters and sorting
 // @Tags cars
-// @Accept json
-// @Produce json
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
 // @Param page query int false "Page number (default: 1)" minimum(1)
 // @Param page_size query int false "Items per page (default: 10, max: 100)" minimum(1) maximum(100)
 // @Param sort_by query string false "Sort by field (name, engine_version, created_at)" Enums(name, engine_version, created_at)
 // @Param sort_dir query string false "Sort direction (asc, desc)" Enums(asc, desc)
+// @Param name query string false "Cars whose name contains this text, ignoring case"
+// @Param engine_version query string false "Cars with this engine version"
 // @Success 200 {object} response.Response{data=dto.PaginatedResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /api/v1/cars [get]
 func (h *CarHandler) GetAllCars(c *gin.Context) {
 	var pagination dto.PaginationRequest
 
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		validationErrors := h.formatValidationErrors(err)
+		validationErrors := formatValidationErrors(c, err)
 		if validationErrors != nil {
 			response.UnprocessableEntity(c, "Validation failed", validationErrors)
 			return
@@ -115,40 +268,170 @@ func (h *CarHandler) GetAllCars(c *gin.Context) {
 		return
 	}
 
-	result, err := h.carService.GetAllCars(&pagination)
+	result, err := h.carService.GetAllCars(c.Request.Context(), &pagination)
 	if err != nil {
-		response.InternalServerError(c, "Failed to retrieve cars")
+		respondError(c, err, "Failed to retrieve cars")
 		return
 	}
 
 	response.Success(c, "Cars retrieved successfully", result)
 }
 
+// ExportCars godoc
+// @Summary Export all cars
+// @Description Stream every car matching the filters, oldest first, as CSV, NDJSON or gzipped NDJSON. Without a format parameter the Accept header chooses (text/csv, application/x-ndjson or application/gzip), defaulting to CSV. The X-Export-Rows trailer carries the row count; X-Export-Error is set when the export stopped early.
+// @Tags cars
+// @Produce text/csv,application/x-ndjson,application/gzip
+// @Param format query string false "Export format" Enums(csv, ndjson, ndjson.gz)
+// @Param name query string false "Cars whose name contains this text, ignoring case"
+// @Param engine_version query string false "Cars with this engine version"
+// @Param created_from query string false "Cars created at or after this time (RFC 3339)"
+// @Param created_to query string false "Cars created before this time (RFC 3339)"
+// @Success 200 {string} string "Exported cars"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/cars/export [get]
+func (h *CarHandler) ExportCars(c *gin.Context) {
+	var filter dto.CarExportRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+	if filter.Format == "" {
+		filter.Format = exportFormatFor(c.GetHeader("Accept"))
+	}
+
+	rc := http.NewResponseController(c.Writer)
+	// Exports outlive the server's write timeout, so give them their own
+	if err := rc.SetWriteDeadline(time.Now().Add(h.exportWriteTimeout)); err != nil {
+		response.InternalServerError(c, "Streaming is not supported")
+		return
+	}
+
+	var (
+		records response.RecordWriter
+		gz      *gzip.Writer
+		rows    int
+	)
+	// start writes the headers once the first car arrives, so a query that
+	// fails at once can still get a proper error response
+	start := func() {
+		format, contentType, extension := response.FormatCSV, "text/csv; charset=utf-8", "csv"
+		switch filter.Format {
+		case dto.ExportFormatNDJSON:
+			format, contentType, extension = response.FormatNDJSON, "application/x-ndjson", "ndjson"
+		case dto.ExportFormatNDJSONGzip:
+			format, contentType, extension = response.FormatNDJSON, "application/gzip", "ndjson.gz"
+		}
+		filename := fmt.Sprintf("cars-%s.%s", time.Now().UTC().Format("20060102T150405Z"), extension)
+
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		c.Header("Cache-Control", "no-store")
+		c.Header("X-Accel-Buffering", "no")
+		c.Header("Trailer", "X-Export-Rows, X-Export-Error")
+		c.Status(http.StatusOK)
+
+		var out io.Writer = c.Writer
+		if filter.Format == dto.ExportFormatNDJSONGzip {
+			gz = gzip.NewWriter(c.Writer)
+			out = gz
+		}
+		records, _ = response.NewRecordWriter(out, format)
+	}
+	flush := func() error {
+		if err := records.Flush(); err != nil {
+			return err
+		}
+		if gz != nil {
+			if err := gz.Flush(); err != nil {
+				return err
+			}
+		}
+		return rc.Flush()
+	}
+
+	err := h.carService.ExportCars(c.Request.Context(), &filter, func(car *dto.CarResponse) error {
+		if records == nil {
+			start()
+		}
+		if err := records.Write(car); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil && records == nil {
+		respondError(c, err, "Failed to export cars")
+		return
+	}
+	if records == nil {
+		start()
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+
+	c.Writer.Header().Set("X-Export-Rows", strconv.Itoa(rows))
+	if err != nil {
+		// The status is already sent; the trailer and the log tell the story
+		logging.FromContext(c.Request.Context()).WarnContext(c.Request.Context(), "Car export stopped early",
+			slog.Int("rows", rows), slog.Any("error", err))
+		c.Writer.Header().Set("X-Export-Error", "export stopped early")
+	}
+}
+
+// exportFormatFor picks the export format for an Accept header, defaulting
+// to CSV
+func exportFormatFor(accept string) string {
+	switch {
+	case strings.Contains(accept, "application/gzip"):
+		return dto.ExportFormatNDJSONGzip
+	case strings.Contains(accept, "application/x-ndjson"):
+		return dto.ExportFormatNDJSON
+	default:
+		return dto.ExportFormatCSV
+	}
+}
+
 // UpdateCar godoc
 // @Summary Update a car
 // @Description Update an existing car's information
 // @Tags cars
-// @Accept json
-// @Produce json
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
 // @Param id path string true "Car ID (UUID)"
 // @Param car body dto.UpdateCarRequest true "Updated car information"
 // @Success 200 {object} response.Response{data=dto.CarResponse}
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /api/v1/cars/{id} [put]
 func (h *CarHandler) UpdateCar(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		response.BadRequest(c, "Invalid car ID format", nil)
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
 		return
 	}
 
 	var req dto.UpdateCarRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		validationErrors := h.formatValidationErrors(err)
+	if err := response.Bind(c, &req); err != nil {
+		validationErrors := formatValidationErrors(c, err)
 		if validationErrors != nil {
 			response.UnprocessableEntity(c, "Validation failed", validationErrors)
 			return
@@ -157,13 +440,9 @@ func (h *CarHandler) UpdateCar(c *gin.Context) {
 		return
 	}
 
-	car, err := h.carService.UpdateCar(id, &req)
+	car, err := h.carService.UpdateCar(c.Request.Context(), id, &req)
 	if err != nil {
-		if errors.Is(err, service.ErrCarNotFound) {
-			response.NotFound(c, "Car not found")
-			return
-		}
-		response.InternalServerError(c, "Failed to update car")
+		respondError(c, err, "Failed to update car")
 		return
 	}
 
@@ -174,63 +453,163 @@ func (h *CarHandler) UpdateCar(c *gin.Context) {
 // @Summary Delete a car
 // @Description Delete a car by ID (soft delete)
 // @Tags cars
-// @Accept json
-// @Produce json
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
 // @Param id path string true "Car ID (UUID)"
 // @Success 204 "No Content"
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /api/v1/cars/{id} [delete]
 func (h *CarHandler) DeleteCar(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		response.BadRequest(c, "Invalid car ID format", nil)
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
 		return
 	}
 
-	err = h.carService.DeleteCar(id)
+	err = h.carService.DeleteCar(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, service.ErrCarNotFound) {
-			response.NotFound(c, "Car not found")
-			return
-		}
-		response.InternalServerError(c, "Failed to delete car")
+		respondError(c, err, "Failed to delete car")
 		return
 	}
 
 	response.NoContent(c)
 }
 
-func (h *CarHandler) formatValidationErrors(err error) []response.ValidationError {
-	var validationErrors []response.ValidationError
+// RestoreCar godoc
+// @Summary Restore a deleted car
+// @Description Restore a soft-deleted car by ID
+// @Tags cars
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Car ID (UUID)"
+// @Success 200 {object} response.Response{data=dto.CarResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/cars/{id}/restore [post]
+func (h *CarHandler) RestoreCar(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
+		return
+	}
 
-	if validatorErrs, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range validatorErrs {
-			validationErrors = append(validationErrors, response.ValidationError{
-				Field:   e.Field(),
-				Message: h.getErrorMessage(e),
-			})
-		}
-		return validationErrors
+	car, err := h.carService.RestoreCar(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to restore car")
+		return
 	}
 
-	return nil
+	response.Success(c, "Car restored successfully", car)
 }
 
-func (h *CarHandler) getErrorMessage(err validator.FieldError) string {
-	switch err.Tag() {
-	case "required":
-		return "This field is required"
-	case "min":
-		return "Value is too short or small"
-	case "max":
-		return "Value is too long or large"
-	case "oneof":
-		return "Invalid value. Allowed values: " + err.Param()
-	default:
-		return "Invalid value"
+// ListCarVersions godoc
+// @Summary List a car's versions
+// @Description Get every recorded version of a car, newest first
+// @Tags cars
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Car ID (UUID)"
+// @Success 200 {object} response.Response{data=[]dto.CarVersionResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/cars/{id}/versions [get]
+func (h *CarHandler) ListCarVersions(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
+		return
 	}
+
+	versions, err := h.carService.ListCarVersions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve car versions")
+		return
+	}
+
+	response.Success(c, "Car versions retrieved successfully", versions)
 }
 
+// GetCarVersion godoc
+// @Summary Get a specific version of a car
+// @Description Get a single historical snapshot of a car
+// @Tags cars
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Car ID (UUID)"
+// @Param version path int true "Version number" minimum(1)
+// @Success 200 {object} response.Response{data=dto.CarVersionResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/cars/{id}/versions/{version} [get]
+func (h *CarHandler) GetCarVersion(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		response.BadRequest(c, "Invalid version number", nil)
+		return
+	}
+
+	v, err := h.carService.GetCarVersion(c.Request.Context(), id, version)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve car version")
+		return
+	}
+
+	response.Success(c, "Car version retrieved successfully", v)
+}
+
+// RevertCar godoc
+// @Summary Revert a car to an earlier version
+// @Description Restore a car's fields from a previous version. The revert is recorded as a new version.
+// @Tags cars
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Car ID (UUID)"
+// @Param version path int true "Version number to revert to" minimum(1)
+// @Success 200 {object} response.Response{data=dto.CarResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/cars/{id}/versions/{version}/revert [post]
+func (h *CarHandler) RevertCar(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid car ID format", nil)
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		response.BadRequest(c, "Invalid version number", nil)
+		return
+	}
+
+	car, err := h.carService.RevertCar(c.Request.Context(), id, version)
+	if err != nil {
+		respondError(c, err, "Failed to revert car")
+		return
+	}
+
+	response.Success(c, "Car reverted successfully", car)
+}
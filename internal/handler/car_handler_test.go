@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/middleware"
+	"project-simple/internal/repository/mocks"
+	"project-simple/internal/requestctx"
+	"project-simple/internal/service"
+	"project-simple/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCarHandler_ExportCars(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	cars := make([]entity.Car, 1200)
+	for i := range cars {
+		cars[i] = entity.Car{ID: uuid.New(), Name: "Honda Civic", EngineVersion: "2.0", CreatedAt: created, UpdatedAt: created}
+	}
+
+	newServer := func(t *testing.T, cars []entity.Car, err error) (*httptest.Server, *mocks.MockCarRepository) {
+		repo := new(mocks.MockCarRepository)
+		repo.On("Each", mock.Anything, mock.AnythingOfType("*dto.CarExportRequest")).Return(cars, err)
+		carService := service.NewCarService(repo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+
+		router := gin.New()
+		router.GET("/api/v1/cars/export", NewCarHandler(carService, nil, time.Minute).ExportCars)
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+		return server, repo
+	}
+
+	get := func(t *testing.T, url, accept string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		// Keep gzip bodies as sent
+		req.Header.Set("Accept-Encoding", "identity")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+
+	t.Run("Success - CSV with filters", func(t *testing.T) {
+		server, repo := newServer(t, cars, nil)
+
+		res := get(t, server.URL+"/api/v1/cars/export?name=civic&engine_version=2.0&created_from=2024-01-01T00:00:00Z", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+		assert.Regexp(t, `^attachment; filename=cars-\d{8}T\d{6}Z\.csv$`, res.Header.Get("Content-Disposition"))
+
+		rows, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, len(cars)+1)
+		assert.Equal(t, []string{"id", "name", "engine_version", "created_at", "updated_at"}, rows[0])
+		assert.Equal(t, cars[0].ID.String(), rows[1][0])
+		assert.Equal(t, "1200", res.Trailer.Get("X-Export-Rows"))
+		assert.Empty(t, res.Trailer.Get("X-Export-Error"))
+
+		filter := repo.Calls[0].Arguments.Get(1).(*dto.CarExportRequest)
+		assert.Equal(t, "civic", filter.Name)
+		assert.Equal(t, "2.0", filter.EngineVersion)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), filter.CreatedFrom.UTC())
+	})
+
+	t.Run("Success - gzipped NDJSON chosen by Accept", func(t *testing.T) {
+		server, _ := newServer(t, cars[:3], nil)
+
+		res := get(t, server.URL+"/api/v1/cars/export", "application/gzip")
+
+		assert.Equal(t, "application/gzip", res.Header.Get("Content-Type"))
+		assert.Contains(t, res.Header.Get("Content-Disposition"), ".ndjson.gz")
+		gz, err := gzip.NewReader(res.Body)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(gz)
+		var lines int
+		for scanner.Scan() {
+			var car dto.CarResponse
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &car))
+			assert.Equal(t, cars[lines].ID, car.ID)
+			lines++
+		}
+		assert.Equal(t, 3, lines)
+	})
+
+	t.Run("Success - empty export", func(t *testing.T) {
+		server, _ := newServer(t, []entity.Car{}, nil)
+
+		res := get(t, server.URL+"/api/v1/cars/export?format=ndjson", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(res.Body)
+		assert.Empty(t, body)
+		assert.Equal(t, "0", res.Trailer.Get("X-Export-Rows"))
+	})
+
+	t.Run("Should respond with an error when the query fails at once", func(t *testing.T) {
+		server, _ := newServer(t, []entity.Car{}, errors.New("connection refused"))
+
+		res := get(t, server.URL+"/api/v1/cars/export", "")
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("Should flag an export that stops midway", func(t *testing.T) {
+		server, _ := newServer(t, cars[:2], errors.New("connection reset"))
+
+		res := get(t, server.URL+"/api/v1/cars/export?format=ndjson", "")
+		body, _ := io.ReadAll(res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, strings.Count(string(body), "\n"))
+		assert.Equal(t, "2", res.Trailer.Get("X-Export-Rows"))
+		assert.Equal(t, "export stopped early", res.Trailer.Get("X-Export-Error"))
+	})
+
+	t.Run("Should reject unknown formats", func(t *testing.T) {
+		server, _ := newServer(t, cars, nil)
+
+		res := get(t, server.URL+"/api/v1/cars/export?format=xlsx", "")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
+}
+
+func TestCarHandler_ImportCars(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	newServer := func(t *testing.T, maxBytes int64) (*httptest.Server, *mocks.MockCarRepository) {
+		repo := new(mocks.MockCarRepository)
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Car).ID = uuid.New()
+		})
+		audit := new(mocks.MockAuditRepository)
+		audit.On("Create", mock.Anything, mock.Anything).Return(nil)
+		outbox := new(mocks.MockOutboxRepository)
+		outbox.On("Create", mock.Anything, mock.Anything).Return(nil)
+		carService := service.NewCarService(repo, new(mocks.MockCarVersionRepository), audit, outbox, &mocks.MockTransactor{})
+
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		})
+		router.POST("/api/v1/cars/import", NewCarHandler(carService, nil, time.Minute).ImportCars)
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+		return server, repo
+	}
+
+	post := func(t *testing.T, url, contentType string, body io.Reader, header http.Header) (*http.Response, map[string]interface{}) {
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var decoded map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&decoded))
+		return res, decoded
+	}
+
+	t.Run("Success - Spreadsheet CSV with aliases and a bad row", func(t *testing.T) {
+		server, repo := newServer(t, 1<<20)
+		body := "\ufeffModelo;Motor;Cor\nHonda Civic;2,0;preto\nFiat Uno;9,9;branco\n;;\nGolf;1.4L;azul\n"
+
+		res, decoded := post(t, server.URL+"/api/v1/cars/import", "text/csv", strings.NewReader(body),
+			http.Header{"Accept-Language": {"pt-BR"}})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "pt-BR", res.Header.Get("Content-Language"))
+		report := decoded["data"].(map[string]interface{})
+		assert.Equal(t, float64(3), report["total"])
+		assert.Equal(t, float64(2), report["created"])
+		assert.Equal(t, float64(1), report["failed"])
+		assert.Equal(t, []interface{}{"Cor"}, report["ignored_columns"])
+
+		rows := report["rows"].([]interface{})
+		require.Len(t, rows, 3)
+		failed := rows[1].(map[string]interface{})
+		assert.Equal(t, float64(3), failed["row"])
+		assert.Equal(t, "failed", failed["status"])
+		fieldErr := failed["errors"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "engine_version", fieldErr["field"])
+		assert.Contains(t, fieldErr["message"], "engine_version deve ser um de")
+		assert.Equal(t, float64(5), rows[2].(map[string]interface{})["row"])
+
+		repo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.Name == "Honda Civic" && car.EngineVersion == "2.0"
+		}))
+		repo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.Name == "Golf" && car.EngineVersion == "1.4"
+		}))
+	})
+
+	t.Run("Success - NDJSON file in a multipart dry run", func(t *testing.T) {
+		server, _ := newServer(t, 1<<20)
+		var form strings.Builder
+		writer := multipart.NewWriter(&form)
+		part, err := writer.CreateFormFile("file", "cars.ndjson")
+		require.NoError(t, err)
+		_, err = io.WriteString(part, "{\"model\":\"Honda Civic\",\"engine\":2}\nnot json\n")
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		res, decoded := post(t, server.URL+"/api/v1/cars/import?dry_run=true", writer.FormDataContentType(), strings.NewReader(form.String()), nil)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		report := decoded["data"].(map[string]interface{})
+		assert.Equal(t, true, report["dry_run"])
+		assert.Equal(t, float64(1), report["created"])
+		assert.Equal(t, float64(1), report["failed"])
+		rows := report["rows"].([]interface{})
+		assert.NotContains(t, rows[0], "id")
+		assert.Equal(t, float64(2), rows[1].(map[string]interface{})["row"])
+	})
+
+	t.Run("Should reject a CSV without required columns", func(t *testing.T) {
+		server, repo := newServer(t, 1<<20)
+
+		res, decoded := post(t, server.URL+"/api/v1/cars/import", "text/csv", strings.NewReader("name,color\nCivic,black\n"), nil)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		assert.Equal(t, "VALIDATION_FAILED", decoded["code"])
+		assert.Equal(t, "engine_version", decoded["details"].([]interface{})[0].(map[string]interface{})["field"])
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject unsupported uploads", func(t *testing.T) {
+		server, _ := newServer(t, 1<<20)
+
+		res, decoded := post(t, server.URL+"/api/v1/cars/import", "application/pdf", strings.NewReader("%PDF"), nil)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+		assert.Equal(t, "UNSUPPORTED_MEDIA_TYPE", decoded["code"])
+	})
+
+	t.Run("Should reject invalid options", func(t *testing.T) {
+		server, _ := newServer(t, 1<<20)
+
+		res, _ := post(t, server.URL+"/api/v1/cars/import?on_conflict=merge", "text/csv", strings.NewReader("name,engine\nCivic,2.0\n"), nil)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Should reject files over the size limit", func(t *testing.T) {
+		server, _ := newServer(t, 64)
+		body := "name,engine_version\n" + strings.Repeat("Honda Civic,2.0\n", 10)
+
+		res, decoded := post(t, server.URL+"/api/v1/cars/import", "text/csv", strings.NewReader(body), nil)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		assert.Equal(t, "REQUEST_TOO_LARGE", decoded["code"])
+	})
+
+	t.Run("Success - Async import is queued as a job", func(t *testing.T) {
+		repo := new(mocks.MockCarRepository)
+		jobRepo := new(mocks.MockJobRepository)
+		var queued *entity.Job
+		jobRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Job")).Return(nil).Run(func(args mock.Arguments) {
+			queued = args.Get(1).(*entity.Job)
+			queued.ID = uuid.New()
+		})
+		carService := service.NewCarService(repo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+		router := gin.New()
+		router.POST("/api/v1/cars/import", NewCarHandler(carService, service.NewJobService(jobRepo, 3), time.Minute).ImportCars)
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+
+		res, decoded := post(t, server.URL+"/api/v1/cars/import?async=true", "text/csv", strings.NewReader("name,engine\nCivic,2.0\nUno,9.9\n"), nil)
+
+		assert.Equal(t, http.StatusAccepted, res.StatusCode)
+		require.NotNil(t, queued)
+		assert.Equal(t, "/api/v1/jobs/"+queued.ID.String(), res.Header.Get("Location"))
+		job := decoded["data"].(map[string]interface{})
+		assert.Equal(t, "queued", job["status"])
+		links := job["links"].(map[string]interface{})
+		assert.Equal(t, res.Header.Get("Location"), links["self"])
+		assert.Equal(t, res.Header.Get("Location")+"/cancel", links["cancel"])
+
+		var payload dto.CarImportJob
+		require.NoError(t, json.Unmarshal(queued.Payload, &payload))
+		assert.Equal(t, dto.JobKindCarImport, queued.Kind)
+		require.Len(t, payload.Rows, 1)
+		assert.True(t, payload.Rows[0].AssignedID)
+		assert.NotEmpty(t, payload.Rows[0].ID)
+		require.Len(t, payload.Invalid, 1)
+		assert.Equal(t, 3, payload.Invalid[0].Row)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCarHandler_CreateCar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	t.Run("Should save a car whose request ID and actor exceed the audit log columns", func(t *testing.T) {
+		repo := new(mocks.MockCarRepository)
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Car).ID = uuid.New()
+		})
+		// Like Postgres, refuse entries too wide for audit_logs
+		var entry *entity.AuditLog
+		audit := new(mocks.MockAuditRepository)
+		audit.On("Create", mock.Anything, mock.MatchedBy(func(e *entity.AuditLog) bool {
+			return len(e.RequestID) <= requestctx.MaxRequestIDLength && len([]rune(e.Actor)) <= requestctx.MaxActorLength
+		})).Return(nil).Run(func(args mock.Arguments) {
+			entry = args.Get(1).(*entity.AuditLog)
+		})
+		audit.On("Create", mock.Anything, mock.Anything).Return(errors.New("value too long for type character varying"))
+		outbox := new(mocks.MockOutboxRepository)
+		outbox.On("Create", mock.Anything, mock.Anything).Return(nil)
+		carService := service.NewCarService(repo, new(mocks.MockCarVersionRepository), audit, outbox, &mocks.MockTransactor{})
+
+		router := gin.New()
+		router.Use(middleware.RequestID(), middleware.RequestContext())
+		router.POST("/api/v1/cars", NewCarHandler(carService, nil, time.Minute).CreateCar)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/cars", strings.NewReader(`{"name":"Honda Civic","engine_version":"2.0"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-ID", strings.Repeat("r", 100))
+		req.Header.Set(middleware.ActorHeader, strings.Repeat("a", 300))
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		require.NotNil(t, entry)
+		assert.Equal(t, w.Header().Get("X-Request-ID"), entry.RequestID)
+		assert.NotEqual(t, strings.Repeat("r", 100), entry.RequestID)
+		assert.Equal(t, strings.Repeat("a", requestctx.MaxActorLength), entry.Actor)
+	})
+}
+
+func TestNormalizeEngineVersion(t *testing.T) {
+	for input, expected := range map[string]string{
+		"2":     "2.0",
+		"2,0":   "2.0",
+		" 1.6 ": "1.6",
+		"2.0L":  "2.0",
+		"1,4 l": "1.4",
+		"":      "",
+		"V8":    "V8",
+	} {
+		assert.Equal(t, expected, normalizeEngineVersion(input), input)
+	}
+}
@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"project-simple/internal/domain/dto"
+	"project-simple/pkg/response"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importColumns maps normalized column names, as found in spreadsheets
+// exported in English, Portuguese or German, to the car field they fill
+var importColumns = map[string]string{
+	"id":     "id",
+	"car_id": "id",
+	"uuid":   "id",
+
+	"name":        "name",
+	"car_name":    "name",
+	"car":         "name",
+	"model":       "name",
+	"vehicle":     "name",
+	"nome":        "name",
+	"modelo":      "name",
+	"veiculo":     "name",
+	"bezeichnung": "name",
+	"modell":      "name",
+	"fahrzeug":    "name",
+
+	"engine_version": "engine_version",
+	"engine":         "engine_version",
+	"engine_size":    "engine_version",
+	"displacement":   "engine_version",
+	"motor":          "engine_version",
+	"motorizacao":    "engine_version",
+	"cilindrada":     "engine_version",
+	"hubraum":        "engine_version",
+}
+
+// importRequiredColumns must be present in a CSV header
+var importRequiredColumns = []string{"name", "engine_version"}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+var (
+	// errImportFileRequired is returned for a multipart upload without a file part
+	errImportFileRequired = errors.New(`the upload has no "file" part`)
+	// errImportUnsupported is returned for uploads that are neither CSV nor NDJSON
+	errImportUnsupported = errors.New("the upload is neither CSV nor NDJSON")
+)
+
+// importFileError is a problem with the file as a whole, as opposed to one of
+// its rows
+type importFileError struct {
+	message string
+	details []response.ValidationError
+}
+
+func (e *importFileError) Error() string { return e.message }
+
+// importFile holds the rows parsed from an upload. Rows that could not be
+// read at all are already failed.
+type importFile struct {
+	rows    []dto.ImportCarRow
+	invalid []dto.ImportRowResult
+	ignored []string
+}
+
+// openImport returns the uploaded file and its format, taken from the
+// multipart "file" part or else from the request body
+func openImport(c *gin.Context) (io.ReadCloser, response.Format, error) {
+	if c.ContentType() != "multipart/form-data" {
+		format, ok := importFormatOf(c.GetHeader("Content-Type"), "")
+		if !ok {
+			return nil, response.Format{}, errImportUnsupported
+		}
+		return c.Request.Body, format, nil
+	}
+
+	header, err := c.FormFile("file")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, response.Format{}, errImportFileRequired
+	}
+	if err != nil {
+		return nil, response.Format{}, err
+	}
+	format, ok := importFormatOf(header.Header.Get("Content-Type"), header.Filename)
+	if !ok {
+		return nil, response.Format{}, errImportUnsupported
+	}
+	file, err := header.Open()
+	return file, format, err
+}
+
+// importFormatOf picks the parser for an upload from its media type, or from
+// the file name when the media type is missing or generic
+func importFormatOf(contentType, filename string) (response.Format, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, format := range []response.Format{response.FormatCSV, response.FormatNDJSON} {
+		for _, candidate := range format.MediaTypes {
+			if mediaType == candidate {
+				return format, true
+			}
+		}
+	}
+	switch mediaType {
+	case "application/csv", "application/vnd.ms-excel":
+		return response.FormatCSV, true
+	case "", "application/octet-stream", "text/plain":
+		switch strings.ToLower(path.Ext(filename)) {
+		case ".csv":
+			return response.FormatCSV, true
+		case ".ndjson", ".jsonl":
+			return response.FormatNDJSON, true
+		}
+	}
+	return response.Format{}, false
+}
+
+// parseImport reads every row of an upload in the given format
+func parseImport(r io.Reader, format response.Format) (*importFile, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	if bom, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+
+	var file *importFile
+	var err error
+	if format.Name == response.FormatNDJSON.Name {
+		file, err = parseImportNDJSON(br)
+	} else {
+		file, err = parseImportCSV(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(file.rows)+len(file.invalid) == 0 {
+		return nil, &importFileError{message: "The file has no rows to import"}
+	}
+	return file, nil
+}
+
+// parseImportCSV reads a CSV file with a header row. The delimiter may be a
+// comma, a semicolon (as spreadsheets write it in locales with a decimal
+// comma) or a tab.
+func parseImportCSV(br *bufio.Reader) (*importFile, error) {
+	head, _ := br.Peek(br.Size())
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = detectDelimiter(head)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &importFileError{message: "The file has no rows to import"}
+	}
+	if err != nil {
+		return nil, csvFileError(err)
+	}
+
+	file := &importFile{}
+	fields := make([]string, len(header))
+	mapped := map[string]bool{}
+	for i, column := range header {
+		field := importColumns[normalizeColumn(column)]
+		if field == "" || mapped[field] {
+			file.ignored = append(file.ignored, column)
+			continue
+		}
+		fields[i] = field
+		mapped[field] = true
+	}
+
+	var missing []response.ValidationError
+	for _, field := range importRequiredColumns {
+		if !mapped[field] {
+			missing = append(missing, response.ValidationError{Field: field, Message: "no column matches " + field})
+		}
+	}
+	if missing != nil {
+		return nil, &importFileError{message: "The file is missing required columns", details: missing}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, csvFileError(err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		values := map[string]string{}
+		blank := true
+		for i, value := range record {
+			if i >= len(fields) || fields[i] == "" {
+				continue
+			}
+			values[fields[i]] = strings.TrimSpace(value)
+			blank = blank && values[fields[i]] == ""
+		}
+		if blank {
+			continue
+		}
+		file.rows = append(file.rows, importRow(line, values))
+	}
+
+	return file, nil
+}
+
+// parseImportNDJSON reads one JSON object per line. Keys go through the same
+// aliases as CSV columns.
+func parseImportNDJSON(br *bufio.Reader) (*importFile, error) {
+	file := &importFile{}
+	ignored := map[string]bool{}
+
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var object map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil || object == nil {
+			file.invalid = append(file.invalid, dto.ImportRowResult{
+				Row:    line,
+				Status: dto.ImportStatusFailed,
+				Errors: []response.ValidationError{{Message: "the line is not a JSON object"}},
+			})
+			continue
+		}
+
+		// When keys collide the field's own name wins, then the first alias by
+		// name, so the outcome does not depend on map order
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if ci, cj := isFieldName(keys[i]), isFieldName(keys[j]); ci != cj {
+				return ci
+			}
+			return keys[i] < keys[j]
+		})
+
+		values := map[string]string{}
+		for _, key := range keys {
+			field := importColumns[normalizeColumn(key)]
+			if _, taken := values[field]; field == "" || taken {
+				ignored[key] = true
+				continue
+			}
+			values[field] = jsonString(object[key])
+		}
+		file.rows = append(file.rows, importRow(line, values))
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, &importFileError{message: fmt.Sprintf("Line %d is longer than 1MB", line+1)}
+		}
+		return nil, err
+	}
+
+	for key := range ignored {
+		file.ignored = append(file.ignored, key)
+	}
+	sort.Strings(file.ignored)
+	return file, nil
+}
+
+// csvFileError reports malformed CSV as a problem with the file, and passes
+// read errors such as an exceeded size limit through
+func csvFileError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &importFileError{message: fmt.Sprintf("The file is not valid CSV: %v", parseErr)}
+	}
+	return err
+}
+
+func importRow(line int, values map[string]string) dto.ImportCarRow {
+	return dto.ImportCarRow{
+		ID: values["id"],
+		CreateCarRequest: dto.CreateCarRequest{
+			Name:          values["name"],
+			EngineVersion: normalizeEngineVersion(values["engine_version"]),
+		},
+		Row: line,
+	}
+}
+
+// isFieldName reports whether a key is a car field's own name rather than an alias
+func isFieldName(key string) bool {
+	normalized := normalizeColumn(key)
+	return importColumns[normalized] == normalized
+}
+
+// detectDelimiter picks the most frequent of comma, semicolon and tab in the
+// header line, defaulting to comma
+func detectDelimiter(header []byte) rune {
+	delimiter, most := ',', bytes.Count(header, []byte{','})
+	for _, candidate := range []rune{';', '\t'} {
+		if n := bytes.Count(header, []byte{byte(candidate)}); n > most {
+			delimiter, most = candidate, n
+		}
+	}
+	return delimiter
+}
+
+// normalizeColumn lowercases a column name and turns spaces, dashes and dots
+// into underscores, so "Engine Version" and "engine-version" both match.
+// Accented letters common in Portuguese headers are folded.
+func normalizeColumn(column string) string {
+	column = strings.ToLower(strings.TrimSpace(column))
+	return strings.NewReplacer(
+		" ", "_", "-", "_", ".", "_",
+		"ç", "c", "ã", "a", "á", "a", "â", "a", "é", "e", "ê", "e", "í", "i", "ó", "o", "õ", "o", "ú", "u",
+	).Replace(column)
+}
+
+// normalizeEngineVersion accepts the ways spreadsheets write displacements,
+// such as "2", "2,0" and "2.0L", and returns them as "2.0"
+func normalizeEngineVersion(value string) string {
+	value = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(value), "lL"))
+	value = strings.Replace(value, ",", ".", 1)
+	if _, err := strconv.Atoi(value); err == nil {
+		value += ".0"
+	}
+	return value
+}
+
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"project-simple/internal/requestctx"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusClientClosedRequest is the nginx convention for a request whose
+// client went away before the response was written
+const StatusClientClosedRequest = 499
+
+// respondError responds to a service error. Coded errors are reported from
+// the catalog; cancelled and timed out requests get their own status; anything
+// else is a 500 with message.
+func respondError(c *gin.Context, err error, message string) {
+	if code, ok := apperror.CodeOf(err); ok {
+		response.Fail(c, code, "", nil)
+		return
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		response.GatewayTimeout(c, "The request took too long to complete")
+	case errors.Is(err, context.Canceled) && errors.Is(context.Cause(c.Request.Context()), requestctx.ErrServerShutdown):
+		response.ServiceUnavailable(c, "The server is shutting down, please retry")
+	case errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil:
+		// Nobody is left to read a body; the status is for logs and metrics
+		c.AbortWithStatus(StatusClientClosedRequest)
+	case errors.Is(err, context.Canceled):
+		response.ServiceUnavailable(c, "The request was cancelled, please retry")
+	default:
+		response.InternalServerError(c, message)
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project-simple/internal/requestctx"
+	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(ctx context.Context) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequestWithContext(ctx, "GET", "/test", nil)
+		return c, w
+	}
+
+	t.Run("Should respond 504 when a query deadline is exceeded", func(t *testing.T) {
+		c, w := newContext(context.Background())
+
+		respondError(c, fmt.Errorf("timeout: %w", context.DeadlineExceeded), "Failed")
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+
+	t.Run("Should record 499 when the client went away", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c, _ := newContext(ctx)
+
+		respondError(c, context.Canceled, "Failed")
+
+		assert.Equal(t, StatusClientClosedRequest, c.Writer.Status())
+	})
+
+	t.Run("Should respond 503 when the server is shutting down", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(requestctx.ErrServerShutdown)
+		c, w := newContext(ctx)
+
+		respondError(c, context.Canceled, "Failed")
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("Should map coded errors from the catalog", func(t *testing.T) {
+		c, w := newContext(context.Background())
+
+		respondError(c, fmt.Errorf("revert: %w", service.ErrRevertToDeletedVersion), "Failed")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var body response.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, string(apperror.RevertToDeletedVersion), body.Code)
+		assert.Equal(t, "Cannot revert to a version in which the car was deleted", body.Message)
+	})
+
+	t.Run("Should respond 500 for other errors", func(t *testing.T) {
+		c, w := newContext(context.Background())
+
+		respondError(c, errors.New("boom"), "Failed")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"project-simple/internal/domain/event"
+	"project-simple/internal/stream"
+	"project-simple/pkg/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reconnectRetry is the reconnection delay advertised to clients, in ms
+const reconnectRetry = 3000
+
+type EventStreamHandler struct {
+	broker            *stream.Broker
+	heartbeatInterval time.Duration
+}
+
+func NewEventStreamHandler(broker *stream.Broker, heartbeatInterval time.Duration) *EventStreamHandler {
+	return &EventStreamHandler{
+		broker:            broker,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// StreamCarEvents godoc
+// @Summary Stream car changes
+// @Description Server-Sent Events stream of car create, update, delete and restore events. Each event carries its id; reconnect with the Last-Event-ID header (or last_event_id query parameter) to replay missed events. A "reset" event means the requested id is no longer buffered and the client should refetch state.
+// @Tags cars
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Router /api/v1/cars/events [get]
+func (h *EventStreamHandler) StreamCarEvents(c *gin.Context) {
+	// Streams outlive the server's write timeout, so lift it for this response
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		response.InternalServerError(c, "Streaming is not supported")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub := h.broker.Subscribe(lastEventID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", reconnectRetry)
+	if !sub.Resumed {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	for _, env := range sub.Replay {
+		if err := writeEvent(c.Writer, env); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case env, ok := <-sub.Events:
+			if !ok {
+				// Fell behind or shutting down; the client reconnects and resumes
+				return
+			}
+			if err := writeEvent(c.Writer, env); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, env event.Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", stream.EventID(env), env.Type, data)
+	return err
+}
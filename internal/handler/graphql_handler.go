@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"project-simple/internal/graphql"
+	"project-simple/internal/validation"
+	"project-simple/pkg/apperror"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// graphiqlCSP lets the GraphiQL page load its scripts and styles from the
+// CDN, in place of the API's default-src 'self' policy
+const graphiqlCSP = "default-src 'self'; script-src 'self' 'unsafe-inline' https://unpkg.com; " +
+	"style-src 'self' 'unsafe-inline' https://unpkg.com; font-src 'self' data: https://unpkg.com; img-src 'self' data:; connect-src 'self'"
+
+const graphiqlPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL - Car Management API</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+  <style>body { margin: 0; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+  <div id="graphiql">Loading...</div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: window.location.pathname });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(React.createElement(GraphiQL, { fetcher }));
+  </script>
+</body>
+</html>
+`
+
+type GraphQLHandler struct {
+	server   *graphql.Server
+	graphiql bool
+}
+
+func NewGraphQLHandler(server *graphql.Server, graphiql bool) *GraphQLHandler {
+	return &GraphQLHandler{
+		server:   server,
+		graphiql: graphiql,
+	}
+}
+
+// graphqlRequest is the body of a GraphQL request sent as JSON
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query godoc
+// @Summary Run a GraphQL request
+// @Description Run a GraphQL query or mutation over cars, sent as JSON ({"query", "operationName", "variables"}) or as an application/graphql body. GET runs queries only, with query, operationName and variables as parameters; in development a browser's GET without a query opens GraphiQL. Results follow the GraphQL spec: errors are listed in "errors" with their code, and for invalid input the fields at fault, under "extensions".
+// @Tags graphql
+// @Accept json,application/graphql
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphqlRequest
+	queriesOnly := c.Request.Method == http.MethodGet
+
+	if queriesOnly {
+		if h.graphiql && c.Query("query") == "" && strings.Contains(c.GetHeader("Accept"), "text/html") {
+			c.Header("Content-Security-Policy", graphiqlCSP)
+			c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(graphiqlPage))
+			return
+		}
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				graphqlFail(c, http.StatusBadRequest, apperror.InvalidRequest, "variables must be a JSON object")
+				return
+			}
+		}
+	} else if !h.readBody(c, &req) {
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		graphqlFail(c, http.StatusBadRequest, apperror.InvalidRequest, "A query is required")
+		return
+	}
+
+	trans, lang := validation.Negotiate(c.GetHeader("Accept-Language"))
+	result := h.server.Execute(c.Request.Context(), graphql.Params{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		Translator:    trans,
+		QueriesOnly:   queriesOnly,
+	})
+	if graphql.HasCode(result.Errors, apperror.ValidationFailed) && lang != "" {
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// readBody decodes a POSTed request, answering for itself when it cannot
+func (h *GraphQLHandler) readBody(c *gin.Context, req *graphqlRequest) bool {
+	var err error
+	switch c.ContentType() {
+	case "application/json", "":
+		err = json.NewDecoder(c.Request.Body).Decode(req)
+	case "application/graphql":
+		var body []byte
+		body, err = io.ReadAll(c.Request.Body)
+		req.Query = string(body)
+	default:
+		graphqlFail(c, http.StatusUnsupportedMediaType, apperror.UnsupportedMediaType, "Send application/json or application/graphql")
+		return false
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		graphqlFail(c, http.StatusRequestEntityTooLarge, apperror.RequestTooLarge, "Request body too large")
+		return false
+	case err != nil:
+		graphqlFail(c, http.StatusBadRequest, apperror.InvalidRequest, "Request body is not a valid GraphQL request")
+		return false
+	}
+	return true
+}
+
+// graphqlFail answers a request that could not be run, in the shape GraphQL
+// clients expect rather than the REST error envelope
+func graphqlFail(c *gin.Context, status int, code apperror.Code, message string) {
+	c.AbortWithStatusJSON(status, &gql.Result{
+		Errors: []gqlerrors.FormattedError{graphql.RequestError(code, message)},
+	})
+}
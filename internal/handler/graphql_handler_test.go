@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"project-simple/internal/graphql"
+	"project-simple/internal/repository/mocks"
+	"project-simple/internal/service"
+	"project-simple/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	repo := new(mocks.MockCarRepository)
+	carService := service.NewCarService(repo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+	server, err := graphql.NewServer(carService, graphql.Config{MaxDepth: 10, MaxComplexity: 1000})
+	require.NoError(t, err)
+	h := NewGraphQLHandler(server, true)
+
+	router := gin.New()
+	router.GET("/graphql", h.Query)
+	router.POST("/graphql", h.Query)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Should report invalid input with its fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+			`{"query":"mutation($input: CreateCarInput!) { createCar(input: $input) { id } }","variables":{"input":{"name":"X","engineVersion":"2.0"}}}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "de")
+
+		w := serve(req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "de", w.Header().Get("Content-Language"))
+		assert.Contains(t, w.Body.String(), `"code":"VALIDATION_FAILED"`)
+		assert.Contains(t, w.Body.String(), `"field":"name"`)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should refuse mutations over GET", func(t *testing.T) {
+		query := url.Values{"query": {`mutation { deleteCar(id: "550e8400-e29b-41d4-a716-446655440000") }`}}
+
+		w := serve(httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "must be sent with POST")
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject unsupported bodies", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("query={cars{totalCount}}"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		w := serve(req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"UNSUPPORTED_MEDIA_TYPE"`)
+	})
+
+	t.Run("Success - Serves GraphiQL to browsers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+		w := serve(req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "GraphiQL")
+		assert.Contains(t, w.Header().Get("Content-Security-Policy"), "https://unpkg.com")
+	})
+}
@@ -2,58 +2,108 @@ package handler
 
 import (
 	"net/http"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/health"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type HealthHandler struct {
-	db *gorm.DB
+	db       *gorm.DB
+	registry *health.Registry
 }
 
-func NewHealthHandler(db *gorm.DB) *HealthHandler {
+func NewHealthHandler(db *gorm.DB, registry *health.Registry) *HealthHandler {
 	return &HealthHandler{
-		db: db,
+		db:       db,
+		registry: registry,
 	}
 }
 
+// Livez godoc
+// @Summary Liveness probe
+// @Description Fails only when the process must be restarted. Dependencies are not checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.probe(c, health.Liveness)
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Fails while the instance must not receive traffic: a required dependency is down, migrations are behind, or shutdown has begun. Optional checks only warn.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.probe(c, health.Readiness)
+}
+
+// Startupz godoc
+// @Summary Startup probe
+// @Description Fails until the instance has started, including running migrations
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /startupz [get]
+func (h *HealthHandler) Startupz(c *gin.Context) {
+	h.probe(c, health.Startup)
+}
+
+func (h *HealthHandler) probe(c *gin.Context, probe health.Probe) {
+	report := h.registry.Run(c.Request.Context(), probe)
+
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
 // HealthCheck godoc
 // @Summary Health check endpoint
 // @Description Check if the API and database are running properly
 // @Tags health
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 503 {object} map[string]interface{}
+// @Success 200 {object} dto.HealthCheckResponse
+// @Failure 503 {object} dto.HealthCheckResponse
 // @Router /api/v1/health [get]
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	// Check database connection
 	sqlDB, err := h.db.DB()
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":   "unhealthy",
-			"service":  "car-api",
-			"database": "error",
-			"error":    "failed to get database instance",
+		c.JSON(http.StatusServiceUnavailable, dto.HealthCheckResponse{
+			Status:   "unhealthy",
+			Service:  "car-api",
+			Database: "error",
+			Error:    "failed to get database instance",
 		})
 		return
 	}
 
 	// Ping database
 	if err := sqlDB.Ping(); err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":   "unhealthy",
-			"service":  "car-api",
-			"database": "down",
-			"error":    "database ping failed",
+		c.JSON(http.StatusServiceUnavailable, dto.HealthCheckResponse{
+			Status:   "unhealthy",
+			Service:  "car-api",
+			Database: "down",
+			Error:    "database ping failed",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":   "healthy",
-		"service":  "car-api",
-		"database": "connected",
+	c.JSON(http.StatusOK, dto.HealthCheckResponse{
+		Status:   "healthy",
+		Service:  "car-api",
+		Database: "connected",
 	})
 }
 
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// jobsPath is where jobs are polled, cancelled and their results fetched
+const jobsPath = "/api/v1/jobs/"
+
+// jobPollInterval is the Retry-After suggested to clients polling a job that
+// has not finished
+const jobPollInterval = 2
+
+type JobHandler struct {
+	jobService service.JobService
+}
+
+func NewJobHandler(jobService service.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJob godoc
+// @Summary Get a job
+// @Description Get the status and progress of an asynchronous job. Poll until status is succeeded, failed or cancelled; links.result then points at the result of a succeeded job.
+// @Tags jobs
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Job ID (UUID)"
+// @Success 200 {object} response.Response{data=dto.JobResponse}
+// @Header 200 {integer} Retry-After "Seconds to wait before polling again, while the job has not finished"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve job")
+		return
+	}
+
+	withJobLinks(job)
+	if job.FinishedAt == "" {
+		c.Header("Retry-After", strconv.Itoa(jobPollInterval))
+	}
+	response.Success(c, "Job retrieved successfully", job)
+}
+
+// GetJobResult godoc
+// @Summary Get a job's result
+// @Description Get the result of a succeeded job, such as the report of an import
+// @Tags jobs
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Job ID (UUID)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/jobs/{id}/result [get]
+func (h *JobHandler) GetJobResult(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.jobService.GetJobResult(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve job result")
+		return
+	}
+
+	response.Success(c, "Job result retrieved successfully", result)
+}
+
+// CancelJob godoc
+// @Summary Cancel a job
+// @Description Cancel a queued job at once, or ask a running job to stop; it is cancelled within a few seconds. Work a running job already committed, such as import batches, is kept.
+// @Tags jobs
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Job ID (UUID)"
+// @Success 200 {object} response.Response{data=dto.JobResponse}
+// @Success 202 {object} response.Response{data=dto.JobResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/jobs/{id}/cancel [post]
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.CancelJob(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to cancel job")
+		return
+	}
+
+	withJobLinks(job)
+	if job.Status == entity.JobRunning {
+		response.Accepted(c, "Job cancellation requested", job)
+		return
+	}
+	response.Success(c, "Job cancelled", job)
+}
+
+func parseJobID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid job ID format", nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// withJobLinks fills in where to poll, cancel and fetch the result of job
+func withJobLinks(job *dto.JobResponse) {
+	self := jobsPath + job.ID.String()
+	job.Links = dto.JobLinks{Self: self}
+	switch job.Status {
+	case entity.JobSucceeded:
+		job.Links.Result = self + "/result"
+	case entity.JobQueued, entity.JobRunning:
+		if !job.CancelRequested {
+			job.Links.Cancel = self + "/cancel"
+		}
+	}
+}
+
+// respondJobAccepted answers a request whose work was queued as job, pointing
+// the client at the job to poll
+func respondJobAccepted(c *gin.Context, message string, job *dto.JobResponse) {
+	withJobLinks(job)
+	c.Header("Location", job.Links.Self)
+	c.Header("Retry-After", strconv.Itoa(jobPollInterval))
+	response.Accepted(c, message, job)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+type OpenAPIHandler struct {
+	doc *openapi3.T
+}
+
+func NewOpenAPIHandler(doc *openapi3.T) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		doc: doc,
+	}
+}
+
+// Document godoc
+// @Summary OpenAPI document
+// @Description Get the OpenAPI 3.1 document describing the REST API, for client generators and contract tests
+// @Tags docs
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /openapi.json [get]
+func (h *OpenAPIHandler) Document(c *gin.Context) {
+	c.JSON(http.StatusOK, h.doc)
+}
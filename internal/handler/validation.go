@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"project-simple/internal/validation"
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// formatValidationErrors translates validation errors into the language the
+// client asked for, or returns nil when err is not a validation error
+func formatValidationErrors(c *gin.Context, err error) []response.ValidationError {
+	trans, lang := validation.Negotiate(c.GetHeader("Accept-Language"))
+	validationErrors := validation.Translate(err, trans)
+	if validationErrors != nil && lang != "" {
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+	}
+	return validationErrors
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/service"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Subscribe a URL to car events. Deliveries are signed with HMAC-SHA256 using the returned secret, which is only shown once.
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param webhook body dto.CreateWebhookRequest true "Subscription"
+// @Success 201 {object} response.Response{data=dto.WebhookResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+
+	if err := response.Bind(c, &req); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, "Failed to create webhook")
+		return
+	}
+
+	response.Created(c, "Webhook created successfully", webhook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhook subscriptions
+// @Description Get every webhook subscription
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Success 200 {object} response.Response{data=[]dto.WebhookResponse}
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhooks")
+		return
+	}
+
+	response.Success(c, "Webhooks retrieved successfully", webhooks)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription by ID
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} response.Response{data=dto.WebhookResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid webhook ID format", nil)
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhook")
+		return
+	}
+
+	response.Success(c, "Webhook retrieved successfully", webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Description Change the URL, event types or active flag of a subscription
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Webhook ID (UUID)"
+// @Param webhook body dto.UpdateWebhookRequest true "Updated subscription"
+// @Success 200 {object} response.Response{data=dto.WebhookResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid webhook ID format", nil)
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := response.Bind(c, &req); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update webhook")
+		return
+	}
+
+	response.Success(c, "Webhook updated successfully", webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription. Pending deliveries are moved to the dead-letter state.
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid webhook ID format", nil)
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListDeliveries godoc
+// @Summary List deliveries of a webhook subscription
+// @Description Get a paginated list of deliveries for a subscription, newest first
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Webhook ID (UUID)"
+// @Param status query string false "Delivery status" Enums(pending, succeeded, dead)
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param page_size query int false "Items per page (default: 10, max: 100)" minimum(1) maximum(100)
+// @Success 200 {object} response.Response{data=dto.WebhookDeliveryListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid webhook ID format", nil)
+		return
+	}
+
+	var filter dto.WebhookDeliveryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		validationErrors := formatValidationErrors(c, err)
+		if validationErrors != nil {
+			response.UnprocessableEntity(c, "Validation failed", validationErrors)
+			return
+		}
+		response.BadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	result, err := h.webhookService.ListDeliveries(c.Request.Context(), id, &filter)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve deliveries")
+		return
+	}
+
+	response.Success(c, "Deliveries retrieved successfully", result)
+}
+
+// GetDelivery godoc
+// @Summary Get a webhook delivery
+// @Description Get a delivery with the log of every attempt made for it
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Webhook ID (UUID)"
+// @Param delivery_id path string true "Delivery ID (UUID)"
+// @Success 200 {object} response.Response{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, deliveryID, ok := h.parseDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve delivery")
+		return
+	}
+
+	response.Success(c, "Delivery retrieved successfully", delivery)
+}
+
+// ReplayDelivery godoc
+// @Summary Replay a webhook delivery
+// @Description Queue a delivery, including a dead-lettered one, for immediate redelivery
+// @Tags webhooks
+// @Accept json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Produce json,xml,text/csv,application/x-ndjson,application/msgpack
+// @Param id path string true "Webhook ID (UUID)"
+// @Param delivery_id path string true "Delivery ID (UUID)"
+// @Success 202 {object} response.Response{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 406 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	id, deliveryID, ok := h.parseDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondError(c, err, "Failed to replay delivery")
+		return
+	}
+
+	response.Accepted(c, "Delivery queued for replay", delivery)
+}
+
+func (h *WebhookHandler) parseDeliveryParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid webhook ID format", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		response.Fail(c, apperror.InvalidID, "Invalid delivery ID format", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, deliveryID, true
+}
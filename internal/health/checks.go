@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"project-simple/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Database checks that a connection to the database can be used
+func Database(db *gorm.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get database instance: %w", err)
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// Migrations checks that the schema has been migrated to at least head
+func Migrations(current func(ctx context.Context) (int, error), head int) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		version, err := current(ctx)
+		if err != nil {
+			return err
+		}
+		if version < head {
+			return fmt.Errorf("schema version %d is behind head %d", version, head)
+		}
+		return nil
+	})
+}
+
+// OutboxLag checks that the oldest unpublished event is younger than maxLag
+func OutboxLag(outboxRepo repository.OutboxRepository, maxLag time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		oldest, err := outboxRepo.OldestPendingAt(ctx)
+		if err != nil {
+			return err
+		}
+		if oldest == nil {
+			return nil
+		}
+		if lag := time.Since(*oldest); lag > maxLag {
+			return fmt.Errorf("oldest pending event is %s old, above %s", lag.Round(time.Second), maxLag)
+		}
+		return nil
+	})
+}
+
+// OutboxDeadLetters checks that the relay has not given up on any event.
+// Dead-lettered events stay until an operator publishes or deletes them.
+func OutboxDeadLetters(outboxRepo repository.OutboxRepository) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		count, err := outboxRepo.CountDeadLettered(ctx)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%d outbox events are dead-lettered", count)
+		}
+		return nil
+	})
+}
+
+// DiskSpace checks that the filesystem holding path has minFree bytes free
+func DiskSpace(path string, minFree uint64) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		free, err := freeBytes(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%d MB free on %s, below %d MB", free>>20, path, minFree>>20)
+		}
+		return nil
+	})
+}
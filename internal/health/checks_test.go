@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"project-simple/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMigrations(t *testing.T) {
+	version := func(v int, err error) func(context.Context) (int, error) {
+		return func(context.Context) (int, error) { return v, err }
+	}
+
+	t.Run("Success - At head", func(t *testing.T) {
+		assert.NoError(t, Migrations(version(3, nil), 3).Check(context.Background()))
+	})
+
+	t.Run("Success - Ahead of head after a newer instance migrated", func(t *testing.T) {
+		assert.NoError(t, Migrations(version(4, nil), 3).Check(context.Background()))
+	})
+
+	t.Run("Error - Behind head", func(t *testing.T) {
+		assert.Error(t, Migrations(version(2, nil), 3).Check(context.Background()))
+	})
+
+	t.Run("Error - Version unavailable", func(t *testing.T) {
+		assert.Error(t, Migrations(version(0, errors.New("down")), 3).Check(context.Background()))
+	})
+}
+
+func TestOutboxLag(t *testing.T) {
+	t.Run("Success - Drained outbox", func(t *testing.T) {
+		mockOutbox := new(mocks.MockOutboxRepository)
+		mockOutbox.On("OldestPendingAt", mock.Anything).Return(nil, nil)
+
+		assert.NoError(t, OutboxLag(mockOutbox, time.Minute).Check(context.Background()))
+	})
+
+	t.Run("Success - Recent pending event", func(t *testing.T) {
+		mockOutbox := new(mocks.MockOutboxRepository)
+		oldest := time.Now().Add(-time.Second)
+		mockOutbox.On("OldestPendingAt", mock.Anything).Return(&oldest, nil)
+
+		assert.NoError(t, OutboxLag(mockOutbox, time.Minute).Check(context.Background()))
+	})
+
+	t.Run("Error - Lag above threshold", func(t *testing.T) {
+		mockOutbox := new(mocks.MockOutboxRepository)
+		oldest := time.Now().Add(-time.Hour)
+		mockOutbox.On("OldestPendingAt", mock.Anything).Return(&oldest, nil)
+
+		assert.Error(t, OutboxLag(mockOutbox, time.Minute).Check(context.Background()))
+	})
+}
+
+func TestOutboxDeadLetters(t *testing.T) {
+	t.Run("Success - No dead letters", func(t *testing.T) {
+		mockOutbox := new(mocks.MockOutboxRepository)
+		mockOutbox.On("CountDeadLettered", mock.Anything).Return(int64(0), nil)
+
+		assert.NoError(t, OutboxDeadLetters(mockOutbox).Check(context.Background()))
+	})
+
+	t.Run("Error - Dead-lettered events", func(t *testing.T) {
+		mockOutbox := new(mocks.MockOutboxRepository)
+		mockOutbox.On("CountDeadLettered", mock.Anything).Return(int64(2), nil)
+
+		assert.EqualError(t, OutboxDeadLetters(mockOutbox).Check(context.Background()), "2 outbox events are dead-lettered")
+	})
+}
+
+func TestDiskSpace(t *testing.T) {
+	t.Run("Success - Enough free space", func(t *testing.T) {
+		assert.NoError(t, DiskSpace(t.TempDir(), 1).Check(context.Background()))
+	})
+
+	t.Run("Error - Not enough free space", func(t *testing.T) {
+		assert.Error(t, DiskSpace(t.TempDir(), math.MaxUint64).Check(context.Background()))
+	})
+
+	t.Run("Error - Missing path", func(t *testing.T) {
+		assert.Error(t, DiskSpace("/does/not/exist", 1).Check(context.Background()))
+	})
+}
@@ -0,0 +1,201 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Probe is a kind of health question an orchestrator asks
+type Probe string
+
+const (
+	// Liveness fails only when the process must be restarted
+	Liveness Probe = "liveness"
+	// Readiness fails while the instance must not receive traffic
+	Readiness Probe = "readiness"
+	// Startup fails until the instance has finished starting
+	Startup Probe = "startup"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// shutdownCheck is reported by the readiness probe once shutdown has begun
+const shutdownCheck = "shutdown"
+
+// Checker tests one dependency. It must honor ctx cancellation.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to Checker
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Registration describes a named check and the probes it takes part in
+type Registration struct {
+	Name    string
+	Checker Checker
+	Probes  []Probe
+	// Optional checks are reported but never fail a probe
+	Optional bool
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the outcome of a probe
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Healthy reports whether the probe passed, possibly with warnings
+func (r Report) Healthy() bool {
+	return r.Status != StatusFail
+}
+
+type entry struct {
+	Registration
+
+	mu      sync.Mutex
+	result  Result
+	expires time.Time
+}
+
+// Registry runs named checks with a timeout and caches their results, so
+// frequent probes from several sources do not hammer dependencies
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu      sync.RWMutex
+	entries []*entry
+
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Register adds a check. A check used by several probes is run once per
+// cache period and its result shared between them.
+func (r *Registry) Register(reg Registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, &entry{Registration: reg})
+}
+
+// SetShuttingDown makes the readiness probe fail from now on, so load
+// balancers stop sending traffic before the server stops accepting it
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Run evaluates every check of probe concurrently
+func (r *Registry) Run(ctx context.Context, probe Probe) Report {
+	r.mu.RLock()
+	var entries []*entry
+	for _, e := range r.entries {
+		for _, p := range e.Probes {
+			if p == probe {
+				entries = append(entries, e)
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Go(func() {
+			results[i] = r.evaluate(ctx, e)
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusPass, Checks: make(map[string]Result, len(entries)+1)}
+	for i, e := range entries {
+		result := results[i]
+		if result.Status == StatusFail && e.Optional {
+			result.Status = StatusWarn
+		}
+		report.Checks[e.Name] = result
+		report.Status = worse(report.Status, result.Status)
+	}
+
+	if probe == Readiness && r.shuttingDown.Load() {
+		report.Checks[shutdownCheck] = Result{
+			Status:    StatusFail,
+			Error:     "server is shutting down",
+			Duration:  "0s",
+			CheckedAt: time.Now(),
+		}
+		report.Status = StatusFail
+	}
+
+	return report
+}
+
+// evaluate returns the cached result of e, running the check if it expired.
+// Concurrent callers wait for a single run instead of starting their own.
+func (r *Registry) evaluate(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if time.Now().Before(e.expires) {
+		return e.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.Checker.Check(checkCtx)
+	result := Result{
+		Status:    StatusPass,
+		Duration:  time.Since(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			result.Error = "check timed out after " + r.timeout.String()
+		}
+	}
+
+	// A result cut short by the caller going away says nothing about the
+	// dependency, so it is not cached
+	if ctx.Err() == nil {
+		e.result = result
+		e.expires = time.Now().Add(r.cacheTTL)
+	}
+	return result
+}
+
+func worse(a, b string) string {
+	rank := map[string]int{StatusPass: 0, StatusWarn: 1, StatusFail: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
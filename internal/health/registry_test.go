@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func passing() Checker {
+	return CheckerFunc(func(ctx context.Context) error { return nil })
+}
+
+func failing() Checker {
+	return CheckerFunc(func(ctx context.Context) error { return errors.New("down") })
+}
+
+func TestRegistry_Run(t *testing.T) {
+	t.Run("Success - Passes when every check passes", func(t *testing.T) {
+		registry := NewRegistry(time.Second, 0)
+		registry.Register(Registration{Name: "database", Checker: passing(), Probes: []Probe{Readiness}})
+
+		report := registry.Run(context.Background(), Readiness)
+
+		assert.Equal(t, StatusPass, report.Status)
+		assert.True(t, report.Healthy())
+		assert.Equal(t, StatusPass, report.Checks["database"].Status)
+	})
+
+	t.Run("Success - Liveness ignores dependency checks", func(t *testing.T) {
+		registry := NewRegistry(time.Second, 0)
+		registry.Register(Registration{Name: "database", Checker: failing(), Probes: []Probe{Readiness, Startup}})
+
+		report := registry.Run(context.Background(), Liveness)
+
+		assert.Equal(t, StatusPass, report.Status)
+		assert.Empty(t, report.Checks)
+	})
+
+	t.Run("Error - Fails when a required check fails", func(t *testing.T) {
+		registry := NewRegistry(time.Second, 0)
+		registry.Register(Registration{Name: "database", Checker: failing(), Probes: []Probe{Readiness}})
+		registry.Register(Registration{Name: "disk", Checker: passing(), Probes: []Probe{Readiness}})
+
+		report := registry.Run(context.Background(), Readiness)
+
+		assert.Equal(t, StatusFail, report.Status)
+		assert.False(t, report.Healthy())
+		assert.Equal(t, "down", report.Checks["database"].Error)
+		assert.Equal(t, StatusPass, report.Checks["disk"].Status)
+	})
+
+	t.Run("Success - Optional failures only warn", func(t *testing.T) {
+		registry := NewRegistry(time.Second, 0)
+		registry.Register(Registration{Name: "outbox_lag", Checker: failing(), Probes: []Probe{Readiness}, Optional: true})
+
+		report := registry.Run(context.Background(), Readiness)
+
+		assert.Equal(t, StatusWarn, report.Status)
+		assert.True(t, report.Healthy())
+		assert.Equal(t, StatusWarn, report.Checks["outbox_lag"].Status)
+	})
+
+	t.Run("Error - Slow checks time out", func(t *testing.T) {
+		registry := NewRegistry(10*time.Millisecond, 0)
+		registry.Register(Registration{Name: "database", Probes: []Probe{Readiness}, Checker: CheckerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})})
+
+		report := registry.Run(context.Background(), Readiness)
+
+		assert.Equal(t, StatusFail, report.Status)
+		assert.Contains(t, report.Checks["database"].Error, "timed out")
+	})
+
+	t.Run("Success - Results are cached between probes", func(t *testing.T) {
+		var calls atomic.Int32
+		registry := NewRegistry(time.Second, time.Minute)
+		registry.Register(Registration{Name: "database", Probes: []Probe{Readiness, Startup}, Checker: CheckerFunc(func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		})})
+
+		registry.Run(context.Background(), Readiness)
+		registry.Run(context.Background(), Readiness)
+		registry.Run(context.Background(), Startup)
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Error - Readiness fails once shutting down", func(t *testing.T) {
+		registry := NewRegistry(time.Second, 0)
+		registry.Register(Registration{Name: "database", Checker: passing(), Probes: []Probe{Readiness}})
+		registry.SetShuttingDown()
+
+		readiness := registry.Run(context.Background(), Readiness)
+		liveness := registry.Run(context.Background(), Liveness)
+
+		assert.Equal(t, StatusFail, readiness.Status)
+		assert.Equal(t, StatusFail, readiness.Checks[shutdownCheck].Status)
+		assert.Equal(t, StatusPass, liveness.Status)
+	})
+}
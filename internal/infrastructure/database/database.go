@@ -1,39 +1,53 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"project-simple/internal/config"
 	"project-simple/internal/domain/entity"
+	"project-simple/internal/logging"
+	"project-simple/internal/querystats"
+	"project-simple/internal/tracing"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 type Database struct {
 	DB *gorm.DB
+	// QueryStats holds rolling duration statistics per query shape
+	QueryStats *querystats.Recorder
 }
 
 func NewDatabase(cfg *config.Config) (*Database, error) {
 	dsn := cfg.Database.GetDSN()
 
-	// Configure GORM logger
-	var gormLogger logger.Interface
-	if cfg.Server.Env == "production" {
-		gormLogger = logger.Default.LogMode(logger.Silent)
-	} else {
-		gormLogger = logger.Default.LogMode(logger.Info)
-	}
-
+	// Bridge GORM into slog; statements are logged at debug level
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+		Logger: logging.NewGormLogger(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Trace every query
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
+	// Time every statement and report slow ones
+	queryStats := querystats.NewRecorder(cfg.Database.QueryStatsWindow, cfg.Database.QueryStatsMaxShapes)
+	if err := db.Use(querystats.NewPlugin(queryStats, cfg.Database.SlowQueryThreshold)); err != nil {
+		return nil, fmt.Errorf("failed to register query stats plugin: %w", err)
+	}
+
+	// Bound every statement with a per-operation deadline
+	if err := db.Use(newQueryTimeoutPlugin(cfg.Database.ReadTimeout, cfg.Database.WriteTimeout)); err != nil {
+		return nil, fmt.Errorf("failed to register query timeout plugin: %w", err)
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -46,25 +60,131 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	sqlDB.SetConnMaxLifetime(time.Hour)        // Maximum connection lifetime
 	sqlDB.SetConnMaxIdleTime(10 * time.Minute) // Maximum idle time
 
-	log.Println("Database connection established successfully")
-	log.Println("Connection pool configured: MaxIdle=10, MaxOpen=100, MaxLifetime=1h")
+	slog.Info("Database connection established successfully")
+	slog.Info("Connection pool configured",
+		slog.Int("max_idle", 10),
+		slog.Int("max_open", 100),
+		slog.Duration("max_lifetime", time.Hour))
 
-	return &Database{DB: db}, nil
+	return &Database{DB: db, QueryStats: queryStats}, nil
 }
 
 func (d *Database) AutoMigrate() error {
-	log.Println("Running database migrations...")
+	slog.Info("Running database migrations")
 
-	if err := d.DB.AutoMigrate(
+	// Migrations may run far longer than any request's query deadline
+	db := d.DB.WithContext(WithoutQueryTimeout(context.Background()))
+
+	if err := db.AutoMigrate(
 		&entity.Car{},
+		&entity.CarVersion{},
+		&entity.AuditLog{},
+		&entity.OutboxMessage{},
+		&entity.WebhookSubscription{},
+		&entity.WebhookDelivery{},
+		&entity.WebhookDeliveryAttempt{},
+		&entity.Job{},
 	); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	log.Println("Database migrations completed successfully")
+	if err := db.Exec(carVersionBackfillSQL).Error; err != nil {
+		return fmt.Errorf("failed to backfill car versions: %w", err)
+	}
+
+	if err := db.Exec(auditLogImmutabilitySQL).Error; err != nil {
+		return fmt.Errorf("failed to protect audit log: %w", err)
+	}
+
+	if err := db.Exec(rateLimitTableSQL).Error; err != nil {
+		return fmt.Errorf("failed to create rate limit table: %w", err)
+	}
+
+	if err := db.Exec(schemaVersionTableSQL).Error; err != nil {
+		return fmt.Errorf("failed to create schema version table: %w", err)
+	}
+
+	if err := db.Exec(schemaVersionUpsertSQL, SchemaHead).Error; err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	slog.Info("Database migrations completed successfully", slog.Int("schema_version", SchemaHead))
 	return nil
 }
 
+// SchemaHead is the schema version this build migrates to. Bump it whenever
+// AutoMigrate changes the schema, so instances can tell whether the database
+// has caught up with them.
+const SchemaHead = 13
+
+// SchemaVersion returns the schema version recorded by the last migration,
+// or 0 if the database has never been migrated
+func (d *Database) SchemaVersion(ctx context.Context) (int, error) {
+	if !d.DB.WithContext(ctx).Migrator().HasTable("schema_version") {
+		return 0, nil
+	}
+
+	var version int
+	err := d.DB.WithContext(ctx).Raw("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version).Error
+	return version, err
+}
+
+const schemaVersionTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_version (
+	id          integer PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+	version     integer NOT NULL,
+	migrated_at timestamptz NOT NULL DEFAULT now()
+);
+`
+
+// schemaVersionUpsertSQL records the migrated version; a newer instance that
+// already migrated further is never rolled back
+const schemaVersionUpsertSQL = `
+INSERT INTO schema_version (id, version) VALUES (1, ?)
+ON CONFLICT (id) DO UPDATE
+SET version = GREATEST(schema_version.version, EXCLUDED.version), migrated_at = now();
+`
+
+// rateLimitTableSQL holds the buckets of the postgres rate limit store. It is
+// unlogged: losing buckets in a crash only resets the limits.
+const rateLimitTableSQL = `
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
+	key text PRIMARY KEY,
+	tat timestamptz NOT NULL
+);
+`
+
+// carVersionBackfillSQL gives cars created before history tracking existed
+// an initial version, so point-in-time reads work for them from now on
+const carVersionBackfillSQL = `
+INSERT INTO car_versions (id, car_id, version, operation, name, engine_version, deleted, car_created_at, car_updated_at, recorded_at)
+SELECT gen_random_uuid(), c.id, 1, 'create', c.name, c.engine_version, c.deleted_at IS NOT NULL, c.created_at, c.updated_at, COALESCE(c.deleted_at, c.updated_at)
+FROM cars c
+WHERE NOT EXISTS (SELECT 1 FROM car_versions v WHERE v.car_id = c.id);
+`
+
+// auditLogImmutabilitySQL makes audit_logs append-only for every role that
+// goes through the table, including the application's own user
+const auditLogImmutabilitySQL = `
+CREATE OR REPLACE FUNCTION audit_logs_reject_change() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_update ON audit_logs;
+CREATE TRIGGER trg_audit_logs_no_update
+	BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_reject_change();
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER trg_audit_logs_no_truncate
+	BEFORE TRUNCATE ON audit_logs
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_reject_change();
+
+REVOKE UPDATE, DELETE, TRUNCATE ON audit_logs FROM PUBLIC;
+`
+
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
 	if err != nil {
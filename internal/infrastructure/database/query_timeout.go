@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	timeoutCancelKey    = "query_timeout:cancel"
+	timeoutParentCtxKey = "query_timeout:parent_ctx"
+)
+
+type noTimeoutKey struct{}
+
+// WithoutQueryTimeout marks ctx so statements run with it are not given a
+// per-operation deadline, for work such as migrations that is expected to
+// take long. Cancellation of ctx itself still applies.
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTimeoutKey{}, true)
+}
+
+// queryTimeoutPlugin bounds every statement with a deadline chosen by the
+// kind of operation. A deadline already on the context wins if it is sooner.
+type queryTimeoutPlugin struct {
+	read  time.Duration
+	write time.Duration
+}
+
+func newQueryTimeoutPlugin(read, write time.Duration) gorm.Plugin {
+	return &queryTimeoutPlugin{read: read, write: write}
+}
+
+func (p *queryTimeoutPlugin) Name() string {
+	return "query_timeout"
+}
+
+// Initialize registers the callbacks. Row callbacks are left alone: their
+// rows are scanned after the callback chain ends, so the deadline could not
+// be released there without cutting the scan short.
+func (p *queryTimeoutPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("query_timeout:before_create", p.start(p.write)),
+		cb.Create().After("gorm:create").Register("query_timeout:after_create", p.end),
+		cb.Query().Before("gorm:query").Register("query_timeout:before_query", p.start(p.read)),
+		cb.Query().After("gorm:query").Register("query_timeout:after_query", p.end),
+		cb.Update().Before("gorm:update").Register("query_timeout:before_update", p.start(p.write)),
+		cb.Update().After("gorm:update").Register("query_timeout:after_update", p.end),
+		cb.Delete().Before("gorm:delete").Register("query_timeout:before_delete", p.start(p.write)),
+		cb.Delete().After("gorm:delete").Register("query_timeout:after_delete", p.end),
+		cb.Raw().Before("gorm:raw").Register("query_timeout:before_raw", p.start(p.write)),
+		cb.Raw().After("gorm:raw").Register("query_timeout:after_raw", p.end),
+	)
+}
+
+func (p *queryTimeoutPlugin) start(timeout time.Duration) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if timeout <= 0 {
+			return
+		}
+
+		parent := db.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		if skip, _ := parent.Value(noTimeoutKey{}).(bool); skip {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		db.Statement.Context = ctx
+		db.InstanceSet(timeoutCancelKey, cancel)
+		db.InstanceSet(timeoutParentCtxKey, parent)
+	}
+}
+
+func (p *queryTimeoutPlugin) end(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(timeoutCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+	if parent, ok := db.InstanceGet(timeoutParentCtxKey); ok {
+		db.Statement.Context = parent.(context.Context)
+	}
+}
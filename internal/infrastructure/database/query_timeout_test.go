@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB returns a DB that builds statements without a server
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(newQueryTimeoutPlugin(time.Second, time.Minute)))
+	return db
+}
+
+func TestQueryTimeoutPlugin(t *testing.T) {
+	t.Run("Should apply the read timeout to queries", func(t *testing.T) {
+		db := newDryRunDB(t)
+		var remaining time.Duration
+		require.NoError(t, db.Callback().Query().Before("gorm:query").After("query_timeout:before_query").
+			Register("test:capture", func(tx *gorm.DB) {
+				deadline, ok := tx.Statement.Context.Deadline()
+				require.True(t, ok)
+				remaining = time.Until(deadline)
+			}))
+
+		var cars []entity.Car
+		db.WithContext(context.Background()).Find(&cars)
+
+		assert.LessOrEqual(t, remaining, time.Second)
+		assert.Greater(t, remaining, time.Duration(0))
+	})
+
+	t.Run("Should apply the write timeout to writes", func(t *testing.T) {
+		db := newDryRunDB(t)
+		var remaining time.Duration
+		require.NoError(t, db.Callback().Create().Before("gorm:create").After("query_timeout:before_create").
+			Register("test:capture", func(tx *gorm.DB) {
+				deadline, ok := tx.Statement.Context.Deadline()
+				require.True(t, ok)
+				remaining = time.Until(deadline)
+			}))
+
+		db.WithContext(context.Background()).Create(&entity.Car{Name: "Civic", EngineVersion: "2.0"})
+
+		assert.Greater(t, remaining, time.Second)
+	})
+
+	t.Run("Should keep a sooner deadline from the caller", func(t *testing.T) {
+		db := newDryRunDB(t)
+		var remaining time.Duration
+		require.NoError(t, db.Callback().Query().Before("gorm:query").After("query_timeout:before_query").
+			Register("test:capture", func(tx *gorm.DB) {
+				deadline, _ := tx.Statement.Context.Deadline()
+				remaining = time.Until(deadline)
+			}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		var cars []entity.Car
+		db.WithContext(ctx).Find(&cars)
+
+		assert.LessOrEqual(t, remaining, 100*time.Millisecond)
+	})
+
+	t.Run("Should skip contexts marked WithoutQueryTimeout", func(t *testing.T) {
+		db := newDryRunDB(t)
+		hasDeadline := true
+		require.NoError(t, db.Callback().Query().Before("gorm:query").After("query_timeout:before_query").
+			Register("test:capture", func(tx *gorm.DB) {
+				_, hasDeadline = tx.Statement.Context.Deadline()
+			}))
+
+		var cars []entity.Car
+		db.WithContext(WithoutQueryTimeout(context.Background())).Find(&cars)
+
+		assert.False(t, hasDeadline)
+	})
+}
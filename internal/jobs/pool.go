@@ -0,0 +1,289 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/logging"
+	"project-simple/internal/repository"
+	"project-simple/internal/requestctx"
+	"project-simple/internal/webhook"
+	"project-simple/pkg/apperror"
+	"sync"
+	"time"
+)
+
+// releaseTimeout bounds saving a job's outcome, which happens even after the
+// job's own context was cancelled
+const releaseTimeout = 10 * time.Second
+
+var (
+	errCancelRequested = errors.New("job cancelled on request")
+	errLeaseLost       = errors.New("job lease lost")
+)
+
+// Runner performs one kind of job and returns its result, which is stored as
+// JSON. It must return once ctx is cancelled. Failures are retried with
+// backoff, except errors carrying a client error code from pkg/apperror,
+// which no retry can fix.
+type Runner func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error)
+
+// Progress reports that done of total units of work are complete. The latest
+// report is saved with each lease renewal.
+type Progress = func(done, total int)
+
+// Config controls concurrency, leases and retries of the worker pool
+type Config struct {
+	Concurrency  int
+	PollInterval time.Duration
+	// Lease is how long a claimed job stays with its worker without a
+	// heartbeat; heartbeats are sent every third of it
+	Lease       time.Duration
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// DrainTimeout is how long running jobs may continue after shutdown
+	// begins before they are stopped and handed back to the queue
+	DrainTimeout time.Duration
+}
+
+// Pool runs queued jobs. Pools on several instances share one queue: each
+// job is claimed by exactly one worker.
+type Pool struct {
+	jobRepo repository.JobRepository
+	cfg     Config
+	runners map[string]Runner
+	name    string
+}
+
+func NewPool(jobRepo repository.JobRepository, cfg Config) *Pool {
+	host, _ := os.Hostname()
+	return &Pool{
+		jobRepo: jobRepo,
+		cfg:     cfg,
+		runners: make(map[string]Runner),
+		name:    fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}
+}
+
+// Register sets the runner for a kind of job. It must be called before Run.
+func (p *Pool) Register(kind string, runner Runner) {
+	p.runners[kind] = runner
+}
+
+// Run starts the workers and blocks until ctx is cancelled and running jobs
+// have drained. Jobs still running after DrainTimeout are stopped and queued
+// again, without counting the interrupted attempt.
+func (p *Pool) Run(ctx context.Context) {
+	jobsCtx, stopJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopJobs()
+
+	var workers sync.WaitGroup
+	for i := range p.cfg.Concurrency {
+		worker := fmt.Sprintf("%s-%d", p.name, i)
+		workers.Go(func() { p.work(ctx, jobsCtx, worker) })
+	}
+
+	<-ctx.Done()
+	drained := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(p.cfg.DrainTimeout):
+		slog.Warn("Jobs still running after drain timeout, returning them to the queue",
+			slog.Duration("drain_timeout", p.cfg.DrainTimeout))
+		stopJobs()
+		<-drained
+	}
+}
+
+// work claims and runs jobs until ctx is cancelled. Jobs run with jobsCtx,
+// which outlives ctx while the pool drains.
+func (p *Pool) work(ctx, jobsCtx context.Context, worker string) {
+	for ctx.Err() == nil {
+		ran, err := p.runNext(ctx, jobsCtx, worker)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Job worker error", slog.String("worker", worker), slog.Any("error", err))
+		}
+		if ran {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(p.cfg.PollInterval):
+		}
+	}
+}
+
+// RunNext claims one due job and runs it to completion. It reports whether a
+// job was found.
+func (p *Pool) RunNext(ctx context.Context, worker string) (bool, error) {
+	return p.runNext(ctx, ctx, worker)
+}
+
+func (p *Pool) runNext(ctx, jobsCtx context.Context, worker string) (bool, error) {
+	job, err := p.jobRepo.Claim(ctx, worker, p.cfg.Lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	p.execute(jobsCtx, job)
+	return true, nil
+}
+
+// execute runs a claimed job and saves its outcome
+func (p *Pool) execute(ctx context.Context, job *entity.Job) {
+	logger := slog.With(slog.String("job_id", job.ID.String()), slog.String("job_kind", job.Kind),
+		slog.Int("attempt", job.Attempts), slog.String("request_id", job.RequestID))
+
+	runner, ok := p.runners[job.Kind]
+	switch {
+	case job.CancelRequested:
+		p.finish(ctx, logger, job, entity.JobCancelled, errCancelRequested.Error())
+		return
+	case !ok:
+		p.finish(ctx, logger, job, entity.JobFailed, fmt.Sprintf("no runner for job kind %q", job.Kind))
+		return
+	case job.Attempts > job.MaxAttempts:
+		// Only a job whose worker repeatedly died mid-run gets here
+		p.finish(ctx, logger, job, entity.JobFailed, fmt.Sprintf("gave up after %d attempts", job.MaxAttempts))
+		return
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	runCtx = requestctx.WithMetadata(runCtx, requestctx.Metadata{RequestID: job.RequestID, Actor: job.Actor})
+	runCtx = logging.WithLogger(runCtx, logger)
+
+	var mu sync.Mutex
+	done, total := job.ProgressDone, job.ProgressTotal
+	progress := func(d, t int) {
+		mu.Lock()
+		done, total = d, t
+		mu.Unlock()
+	}
+	snapshot := func() {
+		mu.Lock()
+		job.ProgressDone, job.ProgressTotal = done, total
+		mu.Unlock()
+	}
+
+	heartbeats := make(chan struct{})
+	go func() {
+		defer close(heartbeats)
+		p.heartbeat(runCtx, cancel, logger, job, snapshot)
+	}()
+
+	logger.Info("Job started")
+	result, runErr := runner(runCtx, job.Payload, progress)
+	cause := context.Cause(runCtx)
+	cancel(nil)
+	<-heartbeats
+	snapshot()
+
+	switch {
+	case errors.Is(cause, errLeaseLost):
+		logger.Warn("Job lease lost; its outcome is dropped", slog.Any("error", runErr))
+		return
+	case runErr == nil:
+		data, err := json.Marshal(result)
+		if err != nil {
+			p.finish(ctx, logger, job, entity.JobFailed, fmt.Sprintf("encoding result: %v", err))
+			return
+		}
+		job.Result = data
+		p.finish(ctx, logger, job, entity.JobSucceeded, "")
+	case errors.Is(cause, errCancelRequested):
+		p.finish(ctx, logger, job, entity.JobCancelled, errCancelRequested.Error())
+	case ctx.Err() != nil:
+		// Interrupted by shutdown: another worker starts it over, and the
+		// attempt does not count against the job
+		job.Status = entity.JobQueued
+		job.Attempts--
+		job.RunAt = time.Now()
+		job.LastError = "interrupted by shutdown"
+		p.release(ctx, logger, job)
+		logger.Warn("Job interrupted by shutdown and queued again")
+	case permanent(runErr) || job.Attempts >= job.MaxAttempts:
+		p.finish(ctx, logger, job, entity.JobFailed, runErr.Error())
+	default:
+		job.Status = entity.JobQueued
+		job.RunAt = time.Now().Add(webhook.Backoff(job.Attempts, p.cfg.BaseBackoff, p.cfg.MaxBackoff))
+		job.LastError = runErr.Error()
+		p.release(ctx, logger, job)
+		logger.Warn("Job failed, will retry", slog.Any("error", runErr), slog.Time("run_at", job.RunAt))
+	}
+}
+
+// heartbeat renews the job's lease until ctx ends, and cancels ctx when the
+// lease is lost or cancellation is requested
+func (p *Pool) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, logger *slog.Logger, job *entity.Job, snapshot func()) {
+	ticker := time.NewTicker(p.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		snapshot()
+		cancelRequested, err := p.jobRepo.Heartbeat(ctx, job, p.cfg.Lease)
+		switch {
+		case errors.Is(err, repository.ErrJobLeaseLost):
+			cancel(errLeaseLost)
+			return
+		case err != nil:
+			if ctx.Err() == nil {
+				logger.Warn("Job heartbeat failed", slog.Any("error", err))
+			}
+		case cancelRequested:
+			logger.Info("Job cancellation requested")
+			cancel(errCancelRequested)
+			return
+		}
+	}
+}
+
+// finish records a final status
+func (p *Pool) finish(ctx context.Context, logger *slog.Logger, job *entity.Job, status, reason string) {
+	now := time.Now()
+	job.Status = status
+	job.LastError = reason
+	job.FinishedAt = &now
+	p.release(ctx, logger, job)
+
+	switch status {
+	case entity.JobSucceeded:
+		logger.Info("Job succeeded")
+	case entity.JobCancelled:
+		logger.Info("Job cancelled")
+	default:
+		logger.Error("Job failed", slog.String("error", reason))
+	}
+}
+
+func (p *Pool) release(ctx context.Context, logger *slog.Logger, job *entity.Job) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	if err := p.jobRepo.Release(ctx, job); err != nil {
+		logger.Error("Failed to save job outcome", slog.Any("error", err))
+	}
+}
+
+// permanent reports whether err is a client error that retrying cannot fix
+func permanent(err error) bool {
+	code, ok := apperror.CodeOf(err)
+	return ok && apperror.Lookup(code).Status < http.StatusInternalServerError
+}
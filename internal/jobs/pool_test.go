@@ -0,0 +1,190 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository/mocks"
+	"project-simple/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPool(repo *mocks.MockJobRepository, lease time.Duration) *Pool {
+	return NewPool(repo, Config{
+		Concurrency:  1,
+		PollInterval: time.Second,
+		Lease:        lease,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   time.Hour,
+		DrainTimeout: time.Second,
+	})
+}
+
+func claimedJob(kind string, attempts int) *entity.Job {
+	return &entity.Job{
+		ID:          uuid.New(),
+		Kind:        kind,
+		Status:      entity.JobRunning,
+		Payload:     json.RawMessage(`{"n":3}`),
+		Attempts:    attempts,
+		MaxAttempts: 3,
+		LockedBy:    "worker",
+	}
+}
+
+// runOne claims job, runs it with runner and returns the job as released
+func runOne(t *testing.T, job *entity.Job, runner Runner) *entity.Job {
+	repo := new(mocks.MockJobRepository)
+	repo.On("Claim", mock.Anything, "worker", time.Minute).Return(job, nil)
+	repo.On("Release", mock.Anything, job).Return(nil)
+
+	pool := newTestPool(repo, time.Minute)
+	pool.Register("test", runner)
+
+	ran, err := pool.RunNext(context.Background(), "worker")
+
+	require.NoError(t, err)
+	require.True(t, ran)
+	repo.AssertExpectations(t)
+	return job
+}
+
+func TestPool_RunNext(t *testing.T) {
+	t.Run("Success - Stores the result and progress", func(t *testing.T) {
+		job := runOne(t, claimedJob("test", 1), func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			assert.JSONEq(t, `{"n":3}`, string(payload))
+			progress(3, 3)
+			return map[string]int{"created": 3}, nil
+		})
+
+		assert.Equal(t, entity.JobSucceeded, job.Status)
+		assert.JSONEq(t, `{"created":3}`, string(job.Result))
+		assert.Equal(t, 3, job.ProgressDone)
+		assert.NotNil(t, job.FinishedAt)
+	})
+
+	t.Run("Success - Retries a failure with backoff", func(t *testing.T) {
+		before := time.Now()
+		job := runOne(t, claimedJob("test", 1), func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			return nil, errors.New("connection reset")
+		})
+
+		assert.Equal(t, entity.JobQueued, job.Status)
+		assert.Equal(t, "connection reset", job.LastError)
+		assert.True(t, job.RunAt.After(before.Add(30*time.Second)))
+		assert.Nil(t, job.FinishedAt)
+	})
+
+	t.Run("Should fail once attempts are exhausted", func(t *testing.T) {
+		job := runOne(t, claimedJob("test", 3), func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			return nil, errors.New("connection reset")
+		})
+
+		assert.Equal(t, entity.JobFailed, job.Status)
+		assert.NotNil(t, job.FinishedAt)
+	})
+
+	t.Run("Should not retry client errors", func(t *testing.T) {
+		job := runOne(t, claimedJob("test", 1), func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			return nil, apperror.New(apperror.InvalidRequest, "bad payload")
+		})
+
+		assert.Equal(t, entity.JobFailed, job.Status)
+		assert.Equal(t, "bad payload", job.LastError)
+	})
+
+	t.Run("Should cancel a job flagged before it ran", func(t *testing.T) {
+		job := claimedJob("test", 1)
+		job.CancelRequested = true
+
+		runOne(t, job, func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			t.Fatal("runner must not run")
+			return nil, nil
+		})
+
+		assert.Equal(t, entity.JobCancelled, job.Status)
+	})
+
+	t.Run("Should fail a job of unknown kind", func(t *testing.T) {
+		job := runOne(t, claimedJob("unknown", 1), nil)
+
+		assert.Equal(t, entity.JobFailed, job.Status)
+		assert.Contains(t, job.LastError, "no runner")
+	})
+
+	t.Run("Should stop a running job when cancellation is requested", func(t *testing.T) {
+		job := claimedJob("test", 1)
+		repo := new(mocks.MockJobRepository)
+		repo.On("Claim", mock.Anything, "worker", 30*time.Millisecond).Return(job, nil)
+		repo.On("Heartbeat", mock.Anything, job, 30*time.Millisecond).Return(true, nil)
+		repo.On("Release", mock.Anything, job).Return(nil)
+
+		pool := newTestPool(repo, 30*time.Millisecond)
+		pool.Register("test", func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		ran, err := pool.RunNext(context.Background(), "worker")
+
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.Equal(t, entity.JobCancelled, job.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Should report when no job is due", func(t *testing.T) {
+		repo := new(mocks.MockJobRepository)
+		repo.On("Claim", mock.Anything, "worker", time.Minute).Return(nil, nil)
+
+		ran, err := newTestPool(repo, time.Minute).RunNext(context.Background(), "worker")
+
+		assert.NoError(t, err)
+		assert.False(t, ran)
+	})
+}
+
+func TestPool_Run(t *testing.T) {
+	t.Run("Success - Queues jobs still running after the drain timeout again", func(t *testing.T) {
+		job := claimedJob("test", 1)
+		repo := new(mocks.MockJobRepository)
+		repo.On("Claim", mock.Anything, mock.Anything, time.Minute).Return(job, nil).Once()
+		repo.On("Claim", mock.Anything, mock.Anything, time.Minute).Return(nil, nil)
+		repo.On("Release", mock.Anything, job).Return(nil)
+
+		started := make(chan struct{})
+		pool := newTestPool(repo, time.Minute)
+		pool.cfg.DrainTimeout = 50 * time.Millisecond
+		pool.Register("test", func(ctx context.Context, payload json.RawMessage, progress Progress) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		go func() {
+			pool.Run(ctx)
+			close(stopped)
+		}()
+		<-started
+		cancel()
+
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("pool did not stop")
+		}
+		assert.Equal(t, entity.JobQueued, job.Status)
+		assert.Equal(t, 0, job.Attempts)
+		assert.Equal(t, "interrupted by shutdown", job.LastError)
+	})
+}
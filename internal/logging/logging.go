@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Formats accepted in LogConfig.Format
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Level is the minimum level of the default logger. It can be changed while
+// the server runs.
+var Level = new(slog.LevelVar)
+
+// New builds a logger writing to w in the given format. Every record passes
+// through the redaction layer before it is written.
+func New(w io.Writer, format string, level slog.Leveler) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}
+
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// Setup installs the default logger. Output of the standard log package is
+// routed through it as well.
+func Setup(w io.Writer, format, level string) error {
+	if err := SetLevel(level); err != nil {
+		return err
+	}
+
+	logger, err := New(w, format, Level)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(logger)
+	return nil
+}
+
+// SetLevel changes the level of the default logger
+func SetLevel(level string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	Level.Set(l)
+	return nil
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or the default
+// logger if there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
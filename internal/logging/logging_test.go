@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	return record
+}
+
+func TestNew(t *testing.T) {
+	t.Run("Success - JSON handler redacts secrets", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, FormatJSON, slog.LevelInfo)
+		require.NoError(t, err)
+
+		logger.Info("login",
+			slog.String("user", "alice"),
+			slog.String("password", "hunter2"),
+			slog.Group("headers", slog.String("Authorization", "Bearer abc"), slog.String("Accept", "*/*")),
+			slog.String("webhook_secret", "whsec_123"),
+		)
+
+		record := decode(t, &buf)
+		assert.Equal(t, "alice", record["user"])
+		assert.Equal(t, Redacted, record["password"])
+		assert.Equal(t, Redacted, record["webhook_secret"])
+		headers := record["headers"].(map[string]any)
+		assert.Equal(t, Redacted, headers["Authorization"])
+		assert.Equal(t, "*/*", headers["Accept"])
+	})
+
+	t.Run("Success - Text handler", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, FormatText, slog.LevelInfo)
+		require.NoError(t, err)
+
+		logger.Info("hello", slog.String("X-Api-Key", "k"))
+
+		assert.Contains(t, buf.String(), "msg=hello")
+		assert.Contains(t, buf.String(), "X-Api-Key="+Redacted)
+	})
+
+	t.Run("Error - Unknown format", func(t *testing.T) {
+		_, err := New(&bytes.Buffer{}, "xml", slog.LevelInfo)
+		assert.Error(t, err)
+	})
+}
+
+func TestSetLevel(t *testing.T) {
+	t.Cleanup(func() { Level.Set(slog.LevelInfo) })
+
+	require.NoError(t, SetLevel("debug"))
+	assert.Equal(t, slog.LevelDebug, Level.Level())
+
+	require.NoError(t, SetLevel("WARN"))
+	assert.Equal(t, slog.LevelWarn, Level.Level())
+
+	assert.Error(t, SetLevel("verbose"))
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("Returns the request logger", func(t *testing.T) {
+		logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+		ctx := WithLogger(context.Background(), logger)
+		assert.Same(t, logger, FromContext(ctx))
+	})
+
+	t.Run("Falls back to the default logger", func(t *testing.T) {
+		assert.Same(t, slog.Default(), FromContext(context.Background()))
+	})
+}
+
+func TestGormLogger(t *testing.T) {
+	newLogger := func(level slog.Level) (*bytes.Buffer, context.Context) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, FormatJSON, level)
+		require.NoError(t, err)
+		return &buf, WithLogger(context.Background(), logger.With(slog.String("request_id", "req-1")))
+	}
+	sql := func() (string, int64) { return `SELECT * FROM "cars" WHERE id = $1`, 1 }
+
+	t.Run("Logs statements at debug through the request logger", func(t *testing.T) {
+		buf, ctx := newLogger(slog.LevelDebug)
+
+		NewGormLogger().Trace(ctx, time.Now(), sql, nil)
+
+		record := decode(t, buf)
+		assert.Equal(t, "DEBUG", record["level"])
+		assert.Equal(t, "req-1", record["request_id"])
+		assert.Equal(t, `SELECT * FROM "cars" WHERE id = $1`, record["sql"])
+	})
+
+	t.Run("Skips statements above debug level", func(t *testing.T) {
+		buf, ctx := newLogger(slog.LevelInfo)
+
+		NewGormLogger().Trace(ctx, time.Now(), sql, nil)
+
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("Logs failures as errors", func(t *testing.T) {
+		buf, ctx := newLogger(slog.LevelInfo)
+
+		NewGormLogger().Trace(ctx, time.Now(), sql, errors.New("boom"))
+
+		assert.Equal(t, "ERROR", decode(t, buf)["level"])
+	})
+
+	t.Run("Never interpolates bound parameters", func(t *testing.T) {
+		filter := NewGormLogger().(interface {
+			ParamsFilter(context.Context, string, ...interface{}) (string, []interface{})
+		})
+
+		_, params := filter.ParamsFilter(context.Background(), "UPDATE x SET secret = $1", "whsec_123")
+
+		assert.Nil(t, params)
+	})
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces the value of every attribute that may hold a secret
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched case-insensitively against attribute keys, with
+// dashes treated as underscores
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"api_key",
+	"dsn",
+}
+
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+// IsSensitive reports whether a field or header name may hold a secret
+func IsSensitive(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"project-simple/internal/repository"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scrapeTimeout bounds the queries run while collecting business metrics
+const scrapeTimeout = 5 * time.Second
+
+var carsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "cars"),
+	"Cars that are not deleted, by engine version.",
+	[]string{"engine_version"}, nil,
+)
+
+// carCollector reads business gauges from the database at scrape time, so
+// they are correct on every replica without any bookkeeping on writes
+type carCollector struct {
+	carRepo repository.CarRepository
+}
+
+func NewCarCollector(carRepo repository.CarRepository) prometheus.Collector {
+	return &carCollector{carRepo: carRepo}
+}
+
+func (c *carCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- carsDesc
+}
+
+func (c *carCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	counts, err := c.carRepo.CountByEngineVersion(ctx)
+	if err != nil {
+		slog.Error("Failed to collect car metrics", slog.Any("error", err))
+		ch <- prometheus.NewInvalidMetric(carsDesc, err)
+		return
+	}
+
+	for engineVersion, count := range counts {
+		ch <- prometheus.MustNewConstMetric(carsDesc, prometheus.GaugeValue, float64(count), engineVersion)
+	}
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"project-simple/internal/repository"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "carapi"
+
+// Registry holds every metric the service exposes. A dedicated registry keeps
+// collectors registered by libraries out of the exposition.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts handled requests by route template, method and status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration observes request latency by route template, method and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency, by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// RateLimitRejections counts requests refused by the rate limiter
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejections_total",
+		Help:      "Requests rejected by the rate limiter, by route template and policy.",
+	}, []string{"route", "policy"})
+
+	// RateLimitErrors counts requests let through because the rate limit
+	// store failed
+	RateLimitErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "store_errors_total",
+		Help:      "Requests allowed without a rate limit decision because the store failed.",
+	})
+
+	// OutboxDeadLetters counts events the outbox relay gave up on
+	OutboxDeadLetters = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "dead_letters_total",
+		Help:      "Outbox events dead-lettered after their last failed attempt.",
+	})
+
+	// CacheRequests counts cache lookups by cache name and result (hit or miss)
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Cache lookups, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RateLimitRejections,
+		RateLimitErrors,
+		CacheRequests,
+		OutboxDeadLetters,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDB exposes the connection pool statistics of db
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// RegisterCars exposes the business gauges backed by carRepo
+func RegisterCars(carRepo repository.CarRepository) {
+	Registry.MustRegister(NewCarCollector(carRepo))
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth requires "Authorization: Bearer <token>" when token is set. An
+// empty token disables the check; the admin server must then listen on a
+// loopback address only, which config.AdminConfig.Validate enforces.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			response.Fail(c, apperror.Unauthorized, "A valid admin token is required", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(token string) *gin.Engine {
+		router := gin.New()
+		router.Use(AdminAuth(token))
+		router.GET("/debug", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	serve := func(router *gin.Engine, authorization string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/debug", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Should allow a valid token", func(t *testing.T) {
+		w := serve(newRouter("s3cret"), "Bearer s3cret")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Should reject a wrong token", func(t *testing.T) {
+		w := serve(newRouter("s3cret"), "Bearer nope")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("Should reject a missing token", func(t *testing.T) {
+		w := serve(newRouter("s3cret"), "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should allow everything when no token is configured", func(t *testing.T) {
+		w := serve(newRouter(""), "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
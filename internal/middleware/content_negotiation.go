@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentNegotiation picks the response format from the Accept header before
+// the handler runs, so a request nobody can answer is refused with 406
+// instead of after its side effects.
+func ContentNegotiation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := response.Negotiate(c); !ok {
+			response.Fail(c, apperror.NotAcceptable,
+				"Supported media types: "+strings.Join(response.SupportedMediaTypes(), ", "), nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
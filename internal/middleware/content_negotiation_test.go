@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handled := false
+	router := gin.New()
+	router.Use(ContentNegotiation())
+	router.POST("/cars", func(c *gin.Context) {
+		handled = true
+		response.Created(c, "Car created successfully", gin.H{"name": "Civic"})
+	})
+
+	serve := func(accept string) *httptest.ResponseRecorder {
+		handled = false
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/cars", nil)
+		req.Header.Set("Accept", accept)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Should answer in the accepted format", func(t *testing.T) {
+		w := serve("text/csv")
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "name\nCivic\n", w.Body.String())
+		assert.True(t, handled)
+	})
+
+	t.Run("Should answer JSON to clients accepting only problem details", func(t *testing.T) {
+		w := serve("application/problem+json")
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.True(t, handled)
+	})
+
+	t.Run("Should refuse unsupported types before the handler runs", func(t *testing.T) {
+		w := serve("application/pdf")
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		assert.False(t, handled)
+		var body response.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "NOT_ACCEPTABLE", body.Code)
+		assert.Contains(t, body.Message, "text/csv")
+	})
+}
@@ -1,9 +1,10 @@
 package middleware
 
 import (
-	"log"
-	"net/http"
+	"log/slog"
+	"project-simple/internal/logging"
 	"project-simple/pkg/response"
+	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,7 +17,8 @@ func ErrorHandler() gin.HandlerFunc {
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last()
 
-			log.Printf("Error: %v", err.Err)
+			logging.FromContext(c.Request.Context()).ErrorContext(c.Request.Context(), "request error",
+				slog.Any("error", err.Err))
 
 			// If response was already written, don't write again
 			if c.Writer.Written() {
@@ -33,12 +35,11 @@ func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("Panic recovered: %v", err)
+				logging.FromContext(c.Request.Context()).ErrorContext(c.Request.Context(), "panic recovered",
+					slog.Any("panic", err),
+					slog.String("stack", string(debug.Stack())))
 
-				c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-					Error:   "Internal Server Error",
-					Message: "An unexpected error occurred",
-				})
+				response.InternalServerError(c, "An unexpected error occurred")
 
 				c.Abort()
 			}
@@ -1,33 +1,54 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
+	"project-simple/internal/logging"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Logger attaches a request-scoped logger to the request context and logs
+// every request once it completes. It must run after RequestContext and
+// Tracing so the logger carries the request ID, user and trace ID.
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
 
+		attrs := []any{
+			slog.String("request_id", c.GetString("request_id")),
+			slog.String("method", c.Request.Method),
+			slog.String("route", routeLabel(c)),
+		}
+		if actor := c.GetString("actor"); actor != "" {
+			attrs = append(attrs, slog.String("user", actor))
+		}
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+		}
+
+		logger := slog.Default().With(attrs...)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+
 		// Process request
 		c.Next()
 
-		// Calculate latency
-		latency := time.Since(startTime)
-
-		// Get status code
 		statusCode := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case statusCode >= 500:
+			level = slog.LevelError
+		case statusCode >= 400:
+			level = slog.LevelWarn
+		}
 
-		// Log request details
-		log.Printf("[%s] %s %s | Status: %d | Latency: %v | IP: %s",
-			c.Request.Method,
-			c.Request.URL.Path,
-			c.Request.Proto,
-			statusCode,
-			latency,
-			c.ClientIP(),
+		logger.LogAttrs(c.Request.Context(), level, "request completed",
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", statusCode),
+			slog.Duration("latency", time.Since(startTime)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
 		)
 	}
 }
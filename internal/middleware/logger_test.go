@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project-simple/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, slog.LevelInfo)
+	require.NoError(t, err)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(RequestContext())
+	router.Use(Logger())
+	router.GET("/cars/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("handler")
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("Should attach request attributes to the request logger", func(t *testing.T) {
+		buf.Reset()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/cars/123", nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		req.Header.Set(ActorHeader, "alice")
+		router.ServeHTTP(w, req)
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		for _, line := range lines {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(line, &record))
+			assert.Equal(t, "req-1", record["request_id"])
+			assert.Equal(t, "/cars/:id", record["route"])
+			assert.Equal(t, "alice", record["user"])
+		}
+
+		var completed map[string]any
+		require.NoError(t, json.Unmarshal(lines[1], &completed))
+		assert.Equal(t, "request completed", completed["msg"])
+		assert.Equal(t, float64(200), completed["status"])
+	})
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"project-simple/internal/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so arbitrary paths
+// cannot blow up label cardinality
+const unmatchedRoute = "unmatched"
+
+// Metrics records request counts and latencies labelled by route template
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		route := routeLabel(c)
+		metrics.HTTPRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).
+			Observe(time.Since(startTime).Seconds())
+	}
+}
+
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return unmatchedRoute
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"project-simple/internal/metrics"
+	"project-simple/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/cars/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("Should label requests by route template", func(t *testing.T) {
+		counter := metrics.HTTPRequests.WithLabelValues("/cars/:id", "GET", "200")
+		before := testutil.ToFloat64(counter)
+
+		for _, id := range []string{"a", "b"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/cars/"+id, nil)
+			router.ServeHTTP(w, req)
+		}
+
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+
+	t.Run("Should label unknown paths as unmatched", func(t *testing.T) {
+		counter := metrics.HTTPRequests.WithLabelValues(unmatchedRoute, "GET", "404")
+		before := testutil.ToFloat64(counter)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/does-not-exist", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+
+	t.Run("Should count rate limit rejections", func(t *testing.T) {
+		limited := gin.New()
+		limited.Use(RateLimit(ratelimit.NewMemoryStore(), RateLimitPolicy{
+			Name:  "default",
+			Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+		}))
+		limited.GET("/limited", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		counter := metrics.RateLimitRejections.WithLabelValues("/limited", "default")
+		before := testutil.ToFloat64(counter)
+
+		for range 3 {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/limited", nil)
+			limited.ServeHTTP(w, req)
+		}
+
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+}
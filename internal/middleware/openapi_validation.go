@@ -0,0 +1,254 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"project-simple/internal/logging"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIValidationConfig chooses what OpenAPIValidation checks
+type OpenAPIValidationConfig struct {
+	// Requests rejects requests whose parameters or JSON body do not match
+	// the document, before the handler runs
+	Requests bool
+	// Responses checks the status, media type and JSON body of responses
+	Responses bool
+	// OnResponseMismatch is told about every response that does not match
+	// the document; by default the mismatch is logged
+	OnResponseMismatch func(c *gin.Context, err error)
+}
+
+// OpenAPIValidation checks requests and responses against the operation of
+// doc matching the route. Routes doc does not describe are let through.
+// Bodies in formats other than JSON are not checked, only their media type.
+// Response checking buffers JSON bodies, so it is meant for tests.
+func OpenAPIValidation(doc *openapi3.T, cfg OpenAPIValidationConfig) gin.HandlerFunc {
+	if cfg.OnResponseMismatch == nil {
+		cfg.OnResponseMismatch = func(c *gin.Context, err error) {
+			logging.FromContext(c.Request.Context()).ErrorContext(c.Request.Context(), "Response does not match the OpenAPI document",
+				slog.Int("status", c.Writer.Status()), slog.Any("error", err))
+		}
+	}
+	routes := openAPIRoutes(doc)
+
+	return func(c *gin.Context) {
+		route, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok || !cfg.Requests && !cfg.Responses {
+			c.Next()
+			return
+		}
+
+		pathParams := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			pathParams[param.Key] = param.Value
+		}
+		options := &openapi3filter.Options{
+			ExcludeRequestBody:  !isJSON(c.ContentType()),
+			SkipSettingDefaults: true,
+			MultiError:          true,
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+		}
+		options.WithCustomSchemaErrorFunc(schemaErrorMessage)
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+
+		if cfg.Requests {
+			if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+				failRequestValidation(c, err)
+				c.Abort()
+				return
+			}
+		}
+
+		if !cfg.Responses {
+			c.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		// Nobody is left to read the response of a request whose client went away
+		if c.Request.Context().Err() != nil {
+			return
+		}
+		if err := validateResponse(input, route, recorder); err != nil {
+			cfg.OnResponseMismatch(c, fmt.Errorf("%s %s: %w", c.Request.Method, route.Path, err))
+		}
+	}
+}
+
+// openAPIRoutes indexes the operations of doc by method and gin route
+// pattern, e.g. "GET /api/v1/cars/:id"
+func openAPIRoutes(doc *openapi3.T) map[string]*routers.Route {
+	routes := map[string]*routers.Route{}
+	for path, item := range doc.Paths.Map() {
+		pattern := strings.NewReplacer("{", ":", "}", "").Replace(path)
+		for method, operation := range item.Operations() {
+			routes[method+" "+pattern] = &routers.Route{
+				Spec:      doc,
+				Path:      path,
+				PathItem:  item,
+				Method:    method,
+				Operation: operation,
+			}
+		}
+	}
+	return routes
+}
+
+// failRequestValidation answers a request the document rejects the way the
+// handlers would: field errors are a validation failure, anything else a
+// bad request
+func failRequestValidation(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.Fail(c, apperror.RequestTooLarge, "Request body exceeds maximum allowed size", nil)
+		return
+	}
+
+	fields, ok := fieldErrors(err, "")
+	if ok && len(fields) > 0 {
+		response.UnprocessableEntity(c, "Validation failed", fields)
+		return
+	}
+
+	message := "Invalid request"
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		switch {
+		case requestErr.Reason != "":
+			message += ": " + requestErr.Reason
+		case requestErr.Err != nil:
+			message += ": " + requestErr.Err.Error()
+		}
+	}
+	response.BadRequest(c, message, nil)
+}
+
+// fieldErrors lists the fields err rejects, named as in the request: the
+// parameter, or the dotted path into the body. ok is false when some error
+// is not about a field, such as a body that is not JSON.
+func fieldErrors(err error, field string) ([]response.ValidationError, bool) {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		var fields []response.ValidationError
+		for _, e := range err {
+			more, ok := fieldErrors(e, field)
+			if !ok {
+				return nil, false
+			}
+			fields = append(fields, more...)
+		}
+		return fields, true
+	case *openapi3filter.RequestError:
+		if err.Parameter != nil {
+			field = err.Parameter.Name
+		}
+		if err.Err == nil {
+			return nil, false
+		}
+		return fieldErrors(err.Err, field)
+	case *openapi3.SchemaError:
+		path := err.JSONPointer()
+		if field != "" {
+			path = append([]string{field}, path...)
+		}
+		return []response.ValidationError{{Field: strings.Join(path, "."), Message: err.Reason}}, true
+	case *openapi3filter.ParseError:
+		if field != "" {
+			return []response.ValidationError{{Field: field, Message: err.Error()}}, true
+		}
+	}
+	return nil, false
+}
+
+// schemaErrorMessage keeps errors to the failing location and reason,
+// without the schema and value kin-openapi adds by default
+func schemaErrorMessage(err *openapi3.SchemaError) string {
+	return "/" + strings.Join(err.JSONPointer(), "/") + ": " + err.Reason
+}
+
+// validateResponse checks a response against the operation. Bodies other
+// than JSON are only checked for a declared media type.
+func validateResponse(input *openapi3filter.RequestValidationInput, route *routers.Route, recorder *responseRecorder) error {
+	status := recorder.Status()
+	contentType := recorder.Header().Get("Content-Type")
+
+	declared := route.Operation.Responses.Status(status)
+	if declared == nil {
+		declared = route.Operation.Responses.Default()
+	}
+	if declared != nil && len(declared.Value.Content) > 0 && status != http.StatusNoContent {
+		if contentType == "" {
+			return fmt.Errorf("status %d: response has no Content-Type", status)
+		}
+		if declared.Value.Content.Get(contentType) == nil {
+			return fmt.Errorf("status %d: media type %q is not declared", status, contentType)
+		}
+	}
+
+	options := *input.Options
+	options.IncludeResponseStatus = true
+	options.ExcludeResponseBody = !isJSON(contentType)
+	return openapi3filter.ValidateResponse(input.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 recorder.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options:                &options,
+	})
+}
+
+// isJSON reports whether contentType is JSON, including suffixed types such
+// as application/problem+json
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// responseRecorder keeps a copy of JSON bodies as they are written. Other
+// bodies, such as exports and event streams, pass through untouched.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	if isJSON(w.Header().Get("Content-Type")) {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	if isJSON(w.Header().Get("Content-Type")) {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap lets http.ResponseController reach the connection, as exports do
+// to extend their write deadline
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/openapi"
+	"project-simple/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var (
+		handled    bool
+		mismatches []error
+	)
+	router := gin.New()
+	router.Use(OpenAPIValidation(openapi.Spec(), OpenAPIValidationConfig{
+		Requests:  true,
+		Responses: true,
+		OnResponseMismatch: func(c *gin.Context, err error) {
+			mismatches = append(mismatches, err)
+		},
+	}))
+	router.POST("/api/v1/cars", func(c *gin.Context) {
+		handled = true
+		response.Created(c, "Car created successfully", dto.CarResponse{ID: uuid.New(), Name: "Civic", EngineVersion: "2.0"})
+	})
+	router.GET("/api/v1/cars", func(c *gin.Context) {
+		handled = true
+		response.Success(c, "Cars retrieved successfully", dto.PaginatedResponse{})
+	})
+	router.GET("/api/v1/cars/:id", func(c *gin.Context) {
+		handled = true
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/internal", func(c *gin.Context) {
+		handled = true
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		handled = false
+		mismatches = nil
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	fields := func(w *httptest.ResponseRecorder) []string {
+		var body struct {
+			Details []response.ValidationError `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		var names []string
+		for _, detail := range body.Details {
+			names = append(names, detail.Field)
+		}
+		return names
+	}
+
+	t.Run("Success - Lets requests and responses matching the document through", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/cars", `{"name":"Civic","engine_version":"2.0"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.True(t, handled)
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("Should reject a body the document does not allow, naming its fields", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/cars", `{"name":"C","engine_version":"9.9"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.False(t, handled)
+		assert.ElementsMatch(t, []string{"name", "engine_version"}, fields(w))
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("Should reject a body that is not JSON", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/cars", `{"name":`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.False(t, handled)
+	})
+
+	t.Run("Should reject invalid parameters, naming them", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/v1/cars?page_size=500&sort_dir=up", "")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.False(t, handled)
+		assert.ElementsMatch(t, []string{"page_size", "sort_dir"}, fields(w))
+
+		w = serve(http.MethodGet, "/api/v1/cars/not-a-uuid", "")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, []string{"id"}, fields(w))
+	})
+
+	t.Run("Should report responses whose shape the document does not declare", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/v1/cars/"+uuid.NewString(), "")
+
+		assert.Equal(t, http.StatusOK, w.Code, "the response still reaches the client")
+		require.Len(t, mismatches, 1)
+		assert.Contains(t, mismatches[0].Error(), "GET /api/v1/cars/{id}")
+	})
+
+	t.Run("Should report nil lists of responses as matching", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/v1/cars", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("Should let routes the document does not describe through", func(t *testing.T) {
+		w := serve(http.MethodGet, "/internal", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, handled)
+		assert.Empty(t, mismatches)
+	})
+}
@@ -1,102 +1,98 @@
 package middleware
 
 import (
-	"net/http"
-	"sync"
+	"fmt"
+	"log/slog"
+	"math"
+	"project-simple/internal/logging"
+	"project-simple/internal/metrics"
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/requestctx"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type visitor struct {
-	lastSeen time.Time
-	count    int
+// RateLimitPolicy limits the requests it matches. Each client gets its own
+// bucket per policy.
+type RateLimitPolicy struct {
+	// Name identifies the policy's buckets and labels its rejections
+	Name  string
+	Limit ratelimit.Limit
+	// Methods restricts the policy to these HTTP methods; empty matches all
+	Methods []string
+	// RoutePrefix restricts the policy to route templates starting with it
+	RoutePrefix string
+	// PerPrincipal counts authenticated requests per X-Actor instead of per
+	// client IP. Other requests are counted per IP whatever their X-Actor,
+	// which anyone could otherwise rotate to get a fresh bucket.
+	PerPrincipal bool
 }
 
-type rateLimiter struct {
-	visitors map[string]*visitor
-	mu       sync.RWMutex
-	rate     int
-	window   time.Duration
-}
-
-func newRateLimiter(rate int, window time.Duration) *rateLimiter {
-	rl := &rateLimiter{
-		visitors: make(map[string]*visitor),
-		rate:     rate,
-		window:   window,
+func (p RateLimitPolicy) matches(c *gin.Context) bool {
+	if len(p.Methods) > 0 && !slices.Contains(p.Methods, c.Request.Method) {
+		return false
 	}
-
-	// Cleanup old visitors every minute
-	go func() {
-		ticker := time.NewTicker(time.Minute)
-		defer ticker.Stop()
-
-		for range ticker.C {
-			rl.cleanup()
-		}
-	}()
-
-	return rl
+	return strings.HasPrefix(c.FullPath(), p.RoutePrefix)
 }
 
-func (rl *rateLimiter) cleanup() {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	for ip, v := range rl.visitors {
-		if time.Since(v.lastSeen) > rl.window {
-			delete(rl.visitors, ip)
-		}
+func (p RateLimitPolicy) key(c *gin.Context) string {
+	if md := requestctx.FromContext(c.Request.Context()); p.PerPrincipal && md.Authenticated && md.Actor != "" {
+		return p.Name + ":actor:" + md.Actor
 	}
+	return p.Name + ":ip:" + c.ClientIP()
 }
 
-func (rl *rateLimiter) allow(ip string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	now := time.Now()
-	v, exists := rl.visitors[ip]
-
-	if !exists {
-		rl.visitors[ip] = &visitor{
-			lastSeen: now,
-			count:    1,
+// RateLimit applies every matching policy in order and rejects the request
+// with 429 as soon as one is exhausted. Responses carry the RateLimit headers
+// of the most restrictive policy. When the store fails the request is let
+// through: an outage of the store must not take the API down.
+// It must run after RequestContext, which identifies the principal.
+func RateLimit(store ratelimit.Store, policies ...RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			applied ratelimit.Result
+			policy  RateLimitPolicy
+			found   bool
+		)
+
+		for _, p := range policies {
+			if !p.matches(c) {
+				continue
+			}
+
+			res, err := store.Allow(c.Request.Context(), p.key(c), p.Limit)
+			if err != nil {
+				metrics.RateLimitErrors.Inc()
+				logging.FromContext(c.Request.Context()).Warn("Rate limit store failed, allowing request",
+					slog.String("policy", p.Name), slog.Any("error", err))
+				continue
+			}
+
+			if !found || !res.Allowed || res.Remaining < applied.Remaining {
+				applied, policy, found = res, p, true
+			}
+			if !res.Allowed {
+				break
+			}
 		}
-		return true
-	}
 
-	// Reset counter if window has passed
-	if now.Sub(v.lastSeen) > rl.window {
-		v.count = 1
-		v.lastSeen = now
-		return true
-	}
-
-	// Check if rate limit exceeded
-	if v.count >= rl.rate {
-		return false
-	}
-
-	v.count++
-	v.lastSeen = now
-	return true
-}
-
-// RateLimit middleware limits requests per IP
-// rate: maximum number of requests
-// window: time window for rate limiting
-func RateLimit(rate int, window time.Duration) gin.HandlerFunc {
-	limiter := newRateLimiter(rate, window)
+		if !found {
+			c.Next()
+			return
+		}
 
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
+		setRateLimitHeaders(c, policy, applied)
 
-		if !limiter.allow(ip) {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "Rate Limit Exceeded",
-				"message": "Too many requests. Please try again later.",
-			})
+		if !applied.Allowed {
+			metrics.RateLimitRejections.WithLabelValues(routeLabel(c), policy.Name).Inc()
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(applied.RetryAfter)))
+			response.Fail(c, apperror.RateLimited, "Too many requests. Please try again later.", nil)
 			c.Abort()
 			return
 		}
@@ -104,3 +100,16 @@ func RateLimit(rate int, window time.Duration) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// setRateLimitHeaders sets the headers of the IETF RateLimit header fields
+// draft; times are in whole seconds, rounded up
+func setRateLimitHeaders(c *gin.Context, policy RateLimitPolicy, res ratelimit.Result) {
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit.Requests, ceilSeconds(policy.Limit.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store down")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(store ratelimit.Store) *gin.Engine {
+		router := gin.New()
+		router.Use(RequestContext())
+		router.Use(RateLimit(store,
+			RateLimitPolicy{
+				Name:         "writes",
+				Limit:        ratelimit.Limit{Requests: 1, Period: time.Minute},
+				Methods:      []string{http.MethodPost},
+				RoutePrefix:  "/api/",
+				PerPrincipal: true,
+			},
+			RateLimitPolicy{
+				Name:  "default",
+				Limit: ratelimit.Limit{Requests: 3, Period: time.Minute},
+			},
+		))
+		router.GET("/api/cars", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.POST("/api/cars", func(c *gin.Context) { c.Status(http.StatusCreated) })
+		return router
+	}
+
+	serve := func(router *gin.Engine, method, actor string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/api/cars", nil)
+		if actor != "" {
+			req.Header.Set(ActorHeader, actor)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Should set RateLimit headers", func(t *testing.T) {
+		w := serve(newRouter(ratelimit.NewMemoryStore()), http.MethodGet, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "20", w.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "3;w=60", w.Header().Get("RateLimit-Policy"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Should reject with Retry-After once exhausted", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore())
+		for range 3 {
+			serve(router, http.MethodGet, "")
+		}
+
+		w := serve(router, http.MethodGet, "")
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "20", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Should apply the stricter write policy", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore())
+
+		w := serve(router, http.MethodPost, "alice")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+		w = serve(router, http.MethodPost, "alice")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+		// Reads only count against the IP
+		w = serve(router, http.MethodGet, "alice")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Should not give a fresh bucket to an unauthenticated X-Actor", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore())
+
+		w := serve(router, http.MethodPost, "alice")
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		for _, actor := range []string{"bob", "carol", ""} {
+			w = serve(router, http.MethodPost, actor)
+			assert.Equal(t, http.StatusTooManyRequests, w.Code, actor)
+		}
+	})
+
+	t.Run("Should count authenticated principals separately", func(t *testing.T) {
+		router := gin.New()
+		router.Use(RequestContext())
+		router.Use(func(c *gin.Context) {
+			md := requestctx.FromContext(c.Request.Context())
+			md.Authenticated = true
+			c.Request = c.Request.WithContext(requestctx.WithMetadata(c.Request.Context(), md))
+		})
+		router.Use(RateLimit(ratelimit.NewMemoryStore(), RateLimitPolicy{
+			Name:         "writes",
+			Limit:        ratelimit.Limit{Requests: 1, Period: time.Minute},
+			PerPrincipal: true,
+		}))
+		router.POST("/api/cars", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+		assert.Equal(t, http.StatusCreated, serve(router, http.MethodPost, "alice").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(router, http.MethodPost, "alice").Code)
+		assert.Equal(t, http.StatusCreated, serve(router, http.MethodPost, "bob").Code)
+	})
+
+	t.Run("Should let requests through when the store fails", func(t *testing.T) {
+		w := serve(newRouter(failingStore{}), http.MethodGet, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
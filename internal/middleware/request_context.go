@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"project-simple/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader identifies who performs the request; it is recorded in the audit log
+const ActorHeader = "X-Actor"
+
+// RequestContext stores request metadata on the request context so it reaches
+// the service layer. It must run after RequestID.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := requestctx.Actor(c.GetHeader(ActorHeader))
+		c.Set("actor", actor)
+
+		ctx := requestctx.WithMetadata(c.Request.Context(), requestctx.Metadata{
+			RequestID: c.GetString("request_id"),
+			Actor:     actor,
+			ClientIP:  c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"project-simple/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Should store request metadata on the request context", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		req.Header.Set(ActorHeader, "jane.doe")
+		req.RemoteAddr = "10.0.0.1:1234"
+		c.Request = req
+
+		RequestID()(c)
+		RequestContext()(c)
+
+		md := requestctx.FromContext(c.Request.Context())
+		assert.Equal(t, "req-123", md.RequestID)
+		assert.Equal(t, "jane.doe", md.Actor)
+		assert.Equal(t, "10.0.0.1", md.ClientIP)
+	})
+
+	t.Run("Should leave actor empty when header is missing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		c.Request = req
+
+		RequestContext()(c)
+
+		md := requestctx.FromContext(c.Request.Context())
+		assert.Empty(t, md.Actor)
+	})
+
+	t.Run("Should cut actors to the width of the audit log", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set(ActorHeader, "\xffjos\u00e9"+strings.Repeat("x", 300))
+		c.Request = req
+
+		RequestContext()(c)
+
+		md := requestctx.FromContext(c.Request.Context())
+		assert.Equal(t, requestctx.MaxActorLength, len([]rune(md.Actor)))
+		assert.True(t, strings.HasPrefix(md.Actor, "jos\u00e9x"))
+		assert.Equal(t, md.Actor, c.GetString("actor"))
+	})
+}
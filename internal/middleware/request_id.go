@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"project-simple/internal/requestctx"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -11,8 +13,8 @@ func RequestID() gin.HandlerFunc {
 		// Try to get request ID from header
 		requestID := c.GetHeader("X-Request-ID")
 
-		// Generate new ID if not provided
-		if requestID == "" {
+		// Generate new ID if not provided, or if it would not fit the audit log
+		if !requestctx.ValidRequestID(requestID) {
 			requestID = uuid.New().String()
 		}
 
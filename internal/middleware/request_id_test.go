@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -68,4 +69,22 @@ func TestRequestID(t *testing.T) {
 
 		assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
 	})
+
+	t.Run("Should replace request IDs that would not fit the audit log", func(t *testing.T) {
+		for _, id := range []string{strings.Repeat("a", 65), "req 123", "req-\u00e9"} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Request-ID", id)
+			c.Request = req
+
+			RequestID()(c)
+
+			requestID := w.Header().Get("X-Request-ID")
+			_, err := uuid.Parse(requestID)
+			assert.NoError(t, err, "request ID %q should be replaced", id)
+			assert.Equal(t, requestID, c.GetString("request_id"))
+		}
+	})
 }
@@ -2,23 +2,38 @@ package middleware
 
 import (
 	"net/http"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
-// RequestSizeLimit limits the maximum size of request bodies
-func RequestSizeLimit(maxBytes int64) gin.HandlerFunc {
+// RouteSizeLimit raises or lowers the body limit of a single route
+type RouteSizeLimit struct {
+	Method string
+	// Path is the route pattern as registered, e.g. /api/v1/cars/import
+	Path     string
+	MaxBytes int64
+}
+
+// RequestSizeLimit limits the maximum size of request bodies to maxBytes,
+// or to the limit of the first override matching the route
+func RequestSizeLimit(maxBytes int64, overrides ...RouteSizeLimit) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		limit := maxBytes
+		for _, override := range overrides {
+			if override.Method == c.Request.Method && override.Path == c.FullPath() {
+				limit = override.MaxBytes
+				break
+			}
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
 
 		c.Next()
 
-		// Check if size limit was exceeded
-		if c.Writer.Status() == http.StatusRequestEntityTooLarge {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error":   "Request Too Large",
-				"message": "Request body exceeds maximum allowed size",
-			})
+		// Check if size limit was exceeded, unless the handler already said so
+		if c.Writer.Status() == http.StatusRequestEntityTooLarge && !c.Writer.Written() {
+			response.Fail(c, apperror.RequestTooLarge, "Request body exceeds maximum allowed size", nil)
 			c.Abort()
 		}
 	}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestSizeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestSizeLimit(8, RouteSizeLimit{Method: http.MethodPost, Path: "/cars/import", MaxBytes: 64}))
+	read := func(c *gin.Context) {
+		var tooLarge *http.MaxBytesError
+		if _, err := io.ReadAll(c.Request.Body); errors.As(err, &tooLarge) {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+	router.POST("/cars", read)
+	router.POST("/cars/import", read)
+	router.POST("/graphql", func(c *gin.Context) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"errors": []gin.H{{"message": "Request body too large"}}})
+	})
+
+	serve := func(path string, size int) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(strings.Repeat("x", size)))
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Should apply the default limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve("/cars", 8))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, serve("/cars", 9))
+	})
+
+	t.Run("Should apply a route's own limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve("/cars/import", 64))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, serve("/cars/import", 65))
+	})
+
+	t.Run("Should leave the answer of a handler that reported the limit itself", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader("{}"))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.JSONEq(t, `{"errors":[{"message":"Request body too large"}]}`, w.Body.String())
+	})
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project-simple/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := tracing.NewProvider(exporter, "car-api-test")
+	require.NoError(t, err)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var handlerSpan trace.SpanContext
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(Tracing())
+	router.GET("/cars/:id", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("Should continue the trace from traceparent", func(t *testing.T) {
+		exporter.Reset()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/cars/123", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set("X-Request-ID", "req-1")
+		router.ServeHTTP(w, req)
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		span := spans[0]
+
+		assert.Equal(t, "GET /cars/:id", span.Name)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+		assert.Equal(t, span.SpanContext.SpanID(), handlerSpan.SpanID())
+		assert.Contains(t, span.Attributes, RequestIDAttribute.String("req-1"))
+		assert.Contains(t, span.Attributes, attribute.Int("http.response.status_code", 200))
+	})
+
+	t.Run("Should start a new trace without traceparent", func(t *testing.T) {
+		exporter.Reset()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/cars/123", nil)
+		router.ServeHTTP(w, req)
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.True(t, spans[0].SpanContext.TraceID().IsValid())
+		assert.False(t, spans[0].Parent.IsValid())
+	})
+}
@@ -0,0 +1,219 @@
+// Package openapi describes the REST API as an OpenAPI 3.1 document. The
+// operations are listed by hand, next to the router; their parameters and
+// bodies are derived from the DTOs the handlers bind and render, so a field
+// added to a DTO reaches the document without further edits.
+package openapi
+
+import (
+	"net/http"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+)
+
+// Version is the OpenAPI version the document follows
+const Version = "3.1.0"
+
+func init() {
+	// Validators check UUIDs as the handlers parse them
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewCallbackValidator(func(value string) error {
+		_, err := uuid.Parse(value)
+		return err
+	}))
+}
+
+// Spec returns the document describing every route of router.SetupRouter
+// except the Swagger UI
+func Spec() *openapi3.T {
+	b := &builder{schemas: newSchemas()}
+	errorResponse := b.errorResponse()
+
+	doc := &openapi3.T{
+		OpenAPI: Version,
+		Info: &openapi3.Info{
+			Title:       "Car Management API",
+			Description: "A RESTful API for managing cars with CRUD operations and pagination",
+			Version:     "1.0",
+		},
+		Paths: openapi3.NewPaths(),
+	}
+
+	for _, op := range b.operations() {
+		path, params := b.path(op.path)
+
+		operation := openapi3.NewOperation()
+		operation.OperationID = op.id
+		operation.Summary = op.summary
+		operation.Tags = []string{op.tag}
+		operation.Parameters = append(params, op.params...)
+		if op.query != nil {
+			operation.Parameters = append(operation.Parameters, b.schemas.parameters(op.query)...)
+		}
+		operation.RequestBody = op.body
+
+		operation.Responses = openapi3.NewResponsesWithCapacity(len(op.responses) + 1)
+		for status, resp := range op.responses {
+			operation.Responses.Set(strconv.Itoa(status), &openapi3.ResponseRef{Value: resp})
+		}
+		operation.Responses.Set("default", &openapi3.ResponseRef{Ref: "#/components/responses/Error", Value: errorResponse})
+
+		item := doc.Paths.Value(path)
+		if item == nil {
+			item = &openapi3.PathItem{}
+			doc.Paths.Set(path, item)
+		}
+		item.SetOperation(op.method, operation)
+	}
+
+	doc.Components = &openapi3.Components{
+		Schemas:   b.schemas.components,
+		Responses: openapi3.ResponseBodies{"Error": &openapi3.ResponseRef{Value: errorResponse}},
+	}
+	return doc
+}
+
+// operation is one route of the router
+type operation struct {
+	method string
+	// path is the route pattern as registered with gin, e.g. /api/v1/cars/:id
+	path    string
+	id      string
+	summary string
+	tag     string
+	// query is the DTO bound from the query string, whose form fields are
+	// the query parameters
+	query interface{}
+	// params are the parameters no DTO describes, such as headers
+	params    openapi3.Parameters
+	body      *openapi3.RequestBodyRef
+	responses map[int]*openapi3.Response
+}
+
+type builder struct {
+	schemas *schemas
+}
+
+// path converts a gin route pattern to an OpenAPI path and its parameters.
+// Versions are numbers; every other path parameter is a UUID.
+func (b *builder) path(pattern string) (string, openapi3.Parameters) {
+	var params openapi3.Parameters
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		name, ok := strings.CutPrefix(segment, ":")
+		if !ok {
+			continue
+		}
+		segments[i] = "{" + name + "}"
+
+		schema := openapi3.NewUUIDSchema()
+		if name == "version" {
+			schema = openapi3.NewIntegerSchema().WithMin(1)
+		}
+		params = append(params, &openapi3.ParameterRef{Value: openapi3.NewPathParameter(name).WithSchema(schema)})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// envelope is a response carrying data in the message/data envelope, in
+// every format clients can negotiate. CSV and NDJSON carry only the records
+// of data.
+func (b *builder) envelope(description string, data interface{}) *openapi3.Response {
+	schema := openapi3.NewObjectSchema().WithProperty("message", openapi3.NewStringSchema())
+	schema.Required = []string{"message"}
+	if data == nil {
+		schema.Properties["data"] = anything()
+	} else {
+		ref := b.schemas.of(data)
+		if reflect.TypeOf(data).Kind() == reflect.Slice {
+			// Empty lists may be nil
+			ref = nullable(ref)
+		}
+		schema.Properties["data"] = ref
+		schema.Required = append(schema.Required, "data")
+	}
+
+	content := openapi3.Content{}
+	for _, format := range response.Formats {
+		for _, mediaType := range format.MediaTypes {
+			if format.Name == response.FormatCSV.Name || format.Name == response.FormatNDJSON.Name {
+				content[mediaType] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
+			} else {
+				content[mediaType] = openapi3.NewMediaType().WithSchema(schema)
+			}
+		}
+	}
+	return openapi3.NewResponse().WithDescription(description).WithContent(content)
+}
+
+// body is a required request body of v's type, accepted in every format
+// response.Bind reads
+func (b *builder) body(v interface{}) *openapi3.RequestBodyRef {
+	ref := b.schemas.of(v)
+	content := openapi3.Content{}
+	for _, format := range response.Formats {
+		for _, mediaType := range format.MediaTypes {
+			content[mediaType] = &openapi3.MediaType{Schema: ref}
+		}
+	}
+	return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithContent(content)}
+}
+
+// errorResponse is the response of every error, in the legacy format or as
+// problem details. Retry-After comes with 429 and 503.
+func (b *builder) errorResponse() *openapi3.Response {
+	var codes []interface{}
+	for _, def := range apperror.All() {
+		codes = append(codes, string(def.Code))
+	}
+
+	legacy := b.schemas.of(response.ErrorResponse{})
+	legacy.Value.Properties["code"].Value.Enum = codes
+	legacy.Value.Properties["details"].Value.Description = "Field errors when code is VALIDATION_FAILED, otherwise any detail"
+
+	problem := b.schemas.of(response.Problem{})
+	problem.Value.Properties["code"].Value.Enum = codes
+	problem.Value.Properties["type"].Value.Format = "uri"
+
+	resp := openapi3.NewResponse().WithDescription("Error").WithContent(openapi3.Content{
+		"application/json":          openapi3.NewMediaType().WithSchemaRef(legacy),
+		response.ProblemContentType: openapi3.NewMediaType().WithSchemaRef(problem),
+	})
+	resp.Headers = openapi3.Headers{"Retry-After": header("Seconds to wait before retrying", openapi3.NewIntegerSchema())}
+	return resp
+}
+
+func jsonResponse(description string, schema *openapi3.SchemaRef) *openapi3.Response {
+	return openapi3.NewResponse().WithDescription(description).
+		WithContent(openapi3.Content{"application/json": openapi3.NewMediaType().WithSchemaRef(schema)})
+}
+
+// media is a response in one of mediaTypes, whose body is not JSON
+func media(description string, mediaTypes ...string) *openapi3.Response {
+	content := openapi3.Content{}
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
+	}
+	return openapi3.NewResponse().WithDescription(description).WithContent(content)
+}
+
+func noContent() *openapi3.Response {
+	return openapi3.NewResponse().WithDescription(http.StatusText(http.StatusNoContent))
+}
+
+func header(description string, schema *openapi3.Schema) *openapi3.HeaderRef {
+	return &openapi3.HeaderRef{Value: &openapi3.Header{Parameter: openapi3.Parameter{
+		Description: description,
+		Schema:      schema.NewRef(),
+	}}}
+}
+
+// withHeaders adds headers to resp
+func withHeaders(resp *openapi3.Response, headers openapi3.Headers) *openapi3.Response {
+	resp.Headers = headers
+	return resp
+}
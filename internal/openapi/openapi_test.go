@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec(t *testing.T) {
+	doc := Spec()
+
+	t.Run("Success - Encodes a document whose references all resolve", func(t *testing.T) {
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+
+		loaded, err := openapi3.NewLoader().LoadFromData(data)
+		require.NoError(t, err)
+		assert.Equal(t, Version, loaded.OpenAPI)
+		assert.Equal(t, doc.Paths.Len(), loaded.Paths.Len())
+	})
+
+	t.Run("Should give every operation its own ID", func(t *testing.T) {
+		ids := map[string]string{}
+		for path, item := range doc.Paths.Map() {
+			for method, op := range item.Operations() {
+				require.NotEmpty(t, op.OperationID, "%s %s", method, path)
+				previous, taken := ids[op.OperationID]
+				assert.False(t, taken, "%s %s reuses the ID of %s", method, path, previous)
+				ids[op.OperationID] = method + " " + path
+			}
+		}
+	})
+
+	t.Run("Should derive constraints from binding tags", func(t *testing.T) {
+		car := doc.Components.Schemas["CreateCarRequest"].Value
+
+		assert.ElementsMatch(t, []string{"name", "engine_version"}, car.Required)
+		assert.Equal(t, uint64(2), car.Properties["name"].Value.MinLength)
+		assert.Equal(t, uint64(100), *car.Properties["name"].Value.MaxLength)
+		assert.Contains(t, car.Properties["engine_version"].Value.Enum, "2.0")
+		assert.Equal(t, "Honda Civic", car.Properties["name"].Value.Example)
+
+		update := doc.Components.Schemas["UpdateCarRequest"].Value
+		assert.Empty(t, update.Required)
+	})
+
+	t.Run("Should describe path and query parameters", func(t *testing.T) {
+		op := doc.Paths.Find("/api/v1/cars/{id}/versions/{version}").Get
+
+		id := op.Parameters.GetByInAndName(openapi3.ParameterInPath, "id")
+		require.NotNil(t, id)
+		assert.Equal(t, "uuid", id.Schema.Value.Format)
+		version := op.Parameters.GetByInAndName(openapi3.ParameterInPath, "version")
+		require.NotNil(t, version)
+		assert.True(t, version.Schema.Value.Type.Is(openapi3.TypeInteger))
+
+		list := doc.Paths.Find("/api/v1/cars").Get
+		pageSize := list.Parameters.GetByInAndName(openapi3.ParameterInQuery, "page_size")
+		require.NotNil(t, pageSize)
+		assert.Equal(t, float64(100), *pageSize.Schema.Value.Max)
+		assert.NotNil(t, list.Parameters.GetByInAndName(openapi3.ParameterInQuery, "name"), "fields of embedded filters are parameters")
+		assert.Nil(t, list.Parameters.GetByInAndName(openapi3.ParameterInQuery, "Offset"))
+	})
+
+	t.Run("Should let nil slices of responses be null", func(t *testing.T) {
+		page := doc.Components.Schemas["PaginatedResponse"].Value
+
+		err := page.Properties["data"].Value.VisitJSON(nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should answer errors in both formats", func(t *testing.T) {
+		errorResponse := doc.Components.Responses["Error"].Value
+
+		assert.NotNil(t, errorResponse.Content.Get("application/json"))
+		assert.NotNil(t, errorResponse.Content.Get("application/problem+json"))
+		for _, item := range doc.Paths.Map() {
+			for _, op := range item.Operations() {
+				assert.Equal(t, "#/components/responses/Error", op.Responses.Default().Ref)
+			}
+		}
+	})
+}
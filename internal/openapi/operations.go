@@ -0,0 +1,295 @@
+package openapi
+
+import (
+	"net/http"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/health"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// operations lists the routes of router.SetupRouter, in the order they are
+// registered there. Errors are described once, as every operation's default
+// response.
+func (b *builder) operations() []operation {
+	report := b.schemas.of(health.Report{})
+	probe := func(path, id, summary string) operation {
+		return operation{
+			method: http.MethodGet, path: path, id: id, summary: summary, tag: "health",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK:                 jsonResponse("The probe passed", report),
+				http.StatusServiceUnavailable: jsonResponse("The probe failed", report),
+			},
+		}
+	}
+	healthCheck := b.schemas.of(dto.HealthCheckResponse{})
+
+	graphqlResult := b.graphqlResult()
+	graphqlResponses := map[int]*openapi3.Response{
+		http.StatusOK:                    jsonResponse("The result, with the errors of fields that failed", graphqlResult),
+		http.StatusBadRequest:            jsonResponse("The request is not a GraphQL request", graphqlResult),
+		http.StatusRequestEntityTooLarge: jsonResponse("The request body is too large", graphqlResult),
+		http.StatusUnsupportedMediaType:  jsonResponse("The request body is neither JSON nor GraphQL", graphqlResult),
+	}
+	graphiql := jsonResponse("The result, or GraphiQL for browsers when enabled and no query is given", graphqlResult)
+	graphiql.Content["text/html"] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
+
+	return []operation{
+		probe("/livez", "livez", "Liveness probe"),
+		probe("/readyz", "readyz", "Readiness probe"),
+		probe("/startupz", "startupz", "Startup probe"),
+
+		{
+			method: http.MethodGet, path: "/openapi.json", id: "getOpenAPIDocument", summary: "This document", tag: "docs",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: jsonResponse("The OpenAPI document", openapi3.NewObjectSchema().NewRef()),
+			},
+		},
+
+		{
+			method: http.MethodGet, path: "/graphql", id: "queryGraphQL", summary: "Run a GraphQL query", tag: "graphql",
+			params: openapi3.Parameters{
+				{Value: openapi3.NewQueryParameter("query").WithSchema(openapi3.NewStringSchema())},
+				{Value: openapi3.NewQueryParameter("operationName").WithSchema(openapi3.NewStringSchema())},
+				{Value: openapi3.NewQueryParameter("variables").WithDescription("Variables as a JSON object").
+					WithSchema(openapi3.NewStringSchema())},
+			},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK:         graphiql,
+				http.StatusBadRequest: graphqlResponses[http.StatusBadRequest],
+			},
+		},
+		{
+			method: http.MethodPost, path: "/graphql", id: "runGraphQL", summary: "Run a GraphQL request", tag: "graphql",
+			body:      graphqlRequest(),
+			responses: graphqlResponses,
+		},
+
+		{
+			method: http.MethodGet, path: "/api/v1/health", id: "healthCheck", summary: "Health check endpoint", tag: "health",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK:                 jsonResponse("The API and its database are up", healthCheck),
+				http.StatusServiceUnavailable: jsonResponse("The database is unreachable", healthCheck),
+			},
+		},
+
+		{
+			method: http.MethodGet, path: "/api/v1/cars/events", id: "streamCarEvents", summary: "Stream car changes", tag: "cars",
+			params: openapi3.Parameters{
+				{Value: openapi3.NewHeaderParameter("Last-Event-ID").WithDescription("ID of the last event received").
+					WithSchema(openapi3.NewStringSchema())},
+				{Value: openapi3.NewQueryParameter("last_event_id").WithDescription("ID of the last event received, for clients that cannot set headers").
+					WithSchema(openapi3.NewStringSchema())},
+			},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: media("Server-sent events, one per car change", "text/event-stream"),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/cars/export", id: "exportCars", summary: "Export all cars", tag: "cars",
+			query: dto.CarExportRequest{},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: media("The cars, one record each", "text/csv", "application/x-ndjson", "application/gzip"),
+			},
+		},
+
+		{
+			method: http.MethodPost, path: "/api/v1/cars", id: "createCar", summary: "Create a new car", tag: "cars",
+			body: b.body(dto.CreateCarRequest{}),
+			responses: map[int]*openapi3.Response{
+				http.StatusCreated: b.envelope("The car created", dto.CarResponse{}),
+			},
+		},
+		{
+			method: http.MethodPost, path: "/api/v1/cars/import", id: "importCars", summary: "Import cars from a file", tag: "cars",
+			query: dto.ImportCarsQuery{},
+			body:  importBody(),
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The report of the import", dto.ImportCarsResponse{}),
+				http.StatusAccepted: withHeaders(b.envelope("The import was queued as a job", dto.JobResponse{}), openapi3.Headers{
+					"Location":    header("URL of the job to poll", openapi3.NewStringSchema()),
+					"Retry-After": header("Seconds to wait before polling", openapi3.NewIntegerSchema()),
+				}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/cars", id: "listCars", summary: "Get all cars with pagination", tag: "cars",
+			query: dto.PaginationRequest{},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("A page of cars", dto.PaginatedResponse{}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/cars/:id", id: "getCar", summary: "Get a car by ID", tag: "cars",
+			query: dto.AsOfRequest{},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The car", dto.CarResponse{}),
+			},
+		},
+		{
+			method: http.MethodPut, path: "/api/v1/cars/:id", id: "updateCar", summary: "Update a car", tag: "cars",
+			body: b.body(dto.UpdateCarRequest{}),
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The car updated", dto.CarResponse{}),
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/api/v1/cars/:id", id: "deleteCar", summary: "Delete a car", tag: "cars",
+			responses: map[int]*openapi3.Response{
+				http.StatusNoContent: noContent(),
+			},
+		},
+		{
+			method: http.MethodPost, path: "/api/v1/cars/:id/restore", id: "restoreCar", summary: "Restore a deleted car", tag: "cars",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The car restored", dto.CarResponse{}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/cars/:id/versions", id: "listCarVersions", summary: "List a car's versions", tag: "cars",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The versions, oldest first", []dto.CarVersionResponse{}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/cars/:id/versions/:version", id: "getCarVersion", summary: "Get a specific version of a car", tag: "cars",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The version", dto.CarVersionResponse{}),
+			},
+		},
+		{
+			method: http.MethodPost, path: "/api/v1/cars/:id/versions/:version/revert", id: "revertCar", summary: "Revert a car to an earlier version", tag: "cars",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The car reverted", dto.CarResponse{}),
+			},
+		},
+
+		{
+			method: http.MethodGet, path: "/api/v1/audit", id: "listAuditLogs", summary: "List audit log entries", tag: "audit",
+			query: dto.AuditLogFilter{},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("A page of audit log entries", dto.AuditLogListResponse{}),
+			},
+		},
+
+		{
+			method: http.MethodPost, path: "/api/v1/webhooks", id: "createWebhook", summary: "Create a webhook subscription", tag: "webhooks",
+			body: b.body(dto.CreateWebhookRequest{}),
+			responses: map[int]*openapi3.Response{
+				http.StatusCreated: b.envelope("The subscription, with its secret", dto.WebhookResponse{}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/webhooks", id: "listWebhooks", summary: "List webhook subscriptions", tag: "webhooks",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The subscriptions", []dto.WebhookResponse{}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/webhooks/:id", id: "getWebhook", summary: "Get a webhook subscription", tag: "webhooks",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The subscription", dto.WebhookResponse{}),
+			},
+		},
+		{
+			method: http.MethodPut, path: "/api/v1/webhooks/:id", id: "updateWebhook", summary: "Update a webhook subscription", tag: "webhooks",
+			body: b.body(dto.UpdateWebhookRequest{}),
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The subscription updated", dto.WebhookResponse{}),
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/api/v1/webhooks/:id", id: "deleteWebhook", summary: "Delete a webhook subscription", tag: "webhooks",
+			responses: map[int]*openapi3.Response{
+				http.StatusNoContent: noContent(),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/webhooks/:id/deliveries", id: "listWebhookDeliveries", summary: "List deliveries of a webhook subscription", tag: "webhooks",
+			query: dto.WebhookDeliveryFilter{},
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("A page of deliveries", dto.WebhookDeliveryListResponse{}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/webhooks/:id/deliveries/:delivery_id", id: "getWebhookDelivery", summary: "Get a webhook delivery", tag: "webhooks",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The delivery, with its attempts", dto.WebhookDeliveryResponse{}),
+			},
+		},
+		{
+			method: http.MethodPost, path: "/api/v1/webhooks/:id/deliveries/:delivery_id/replay", id: "replayWebhookDelivery", summary: "Replay a webhook delivery", tag: "webhooks",
+			responses: map[int]*openapi3.Response{
+				http.StatusAccepted: b.envelope("The delivery, queued again", dto.WebhookDeliveryResponse{}),
+			},
+		},
+
+		{
+			method: http.MethodGet, path: "/api/v1/jobs/:id", id: "getJob", summary: "Get a job", tag: "jobs",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: withHeaders(b.envelope("The job", dto.JobResponse{}), openapi3.Headers{
+					"Retry-After": header("Seconds to wait before polling again, while the job has not finished", openapi3.NewIntegerSchema()),
+				}),
+			},
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/jobs/:id/result", id: "getJobResult", summary: "Get a job's result", tag: "jobs",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK: b.envelope("The result, shaped by the job's kind", nil),
+			},
+		},
+		{
+			method: http.MethodPost, path: "/api/v1/jobs/:id/cancel", id: "cancelJob", summary: "Cancel a job", tag: "jobs",
+			responses: map[int]*openapi3.Response{
+				http.StatusOK:       b.envelope("The job was cancelled before it started", dto.JobResponse{}),
+				http.StatusAccepted: b.envelope("The running job was asked to stop", dto.JobResponse{}),
+			},
+		},
+	}
+}
+
+// importBody is the file of an import: uploaded as a form, or sent as the
+// body itself
+func importBody() *openapi3.RequestBodyRef {
+	file := openapi3.NewStringSchema()
+	form := openapi3.NewObjectSchema().WithProperty("file", file)
+
+	return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithContent(openapi3.Content{
+		"multipart/form-data":  openapi3.NewMediaType().WithSchema(form),
+		"text/csv":             openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema()),
+		"application/x-ndjson": openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema()),
+	})}
+}
+
+// graphqlRequest is a GraphQL request sent as JSON, or as the query alone
+func graphqlRequest() *openapi3.RequestBodyRef {
+	request := openapi3.NewObjectSchema().
+		WithProperty("query", openapi3.NewStringSchema()).
+		WithProperty("operationName", openapi3.NewStringSchema())
+	request.Properties["variables"] = nullable(openapi3.NewObjectSchema().NewRef())
+
+	return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithContent(openapi3.Content{
+		"application/json":    openapi3.NewMediaType().WithSchema(request),
+		"application/graphql": openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema()),
+	})}
+}
+
+// graphqlResult is the body of every GraphQL response, as the GraphQL over
+// HTTP specification defines it rather than in the REST envelope
+func (b *builder) graphqlResult() *openapi3.SchemaRef {
+	location := openapi3.NewObjectSchema().
+		WithProperty("line", openapi3.NewIntegerSchema()).
+		WithProperty("column", openapi3.NewIntegerSchema())
+	graphqlError := openapi3.NewObjectSchema().
+		WithProperty("message", openapi3.NewStringSchema()).
+		WithProperty("locations", openapi3.NewArraySchema().WithItems(location)).
+		WithProperty("path", openapi3.NewArraySchema().WithItems(&openapi3.Schema{})).
+		WithProperty("extensions", openapi3.NewObjectSchema())
+	graphqlError.Required = []string{"message"}
+
+	result := openapi3.NewObjectSchema().
+		WithPropertyRef("data", nullable(openapi3.NewObjectSchema().NewRef())).
+		WithPropertyRef("errors", arrayOf(b.schemas.define("GraphQLError", graphqlError))).
+		WithProperty("extensions", openapi3.NewObjectSchema())
+	return b.schemas.define("GraphQLResult", result)
+}
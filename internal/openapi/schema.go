@@ -0,0 +1,357 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	uuidType       = reflect.TypeFor[uuid.UUID]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemas derives JSON Schemas from Go types the way encoding/json encodes
+// them, and constraints from their binding tags the way the handlers
+// validate them. Named structs become components, referenced where used.
+type schemas struct {
+	components openapi3.Schemas
+	// types is the type behind each component name, to catch two types
+	// claiming the same one
+	types map[string]reflect.Type
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: openapi3.Schemas{}, types: map[string]reflect.Type{}}
+}
+
+// of returns the schema of v's type
+func (s *schemas) of(v interface{}) *openapi3.SchemaRef {
+	return s.ref(reflect.TypeOf(v))
+}
+
+func (s *schemas) ref(t reflect.Type) *openapi3.SchemaRef {
+	switch t {
+	case timeType:
+		return openapi3.NewDateTimeSchema().NewRef()
+	case uuidType:
+		return openapi3.NewUUIDSchema().NewRef()
+	case rawMessageType:
+		return anything()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.ref(t.Elem()))
+	case reflect.String:
+		return openapi3.NewStringSchema().NewRef()
+	case reflect.Bool:
+		return openapi3.NewBoolSchema().NewRef()
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return openapi3.NewInt32Schema().NewRef()
+	case reflect.Int, reflect.Int64:
+		return openapi3.NewInt64Schema().NewRef()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openapi3.NewIntegerSchema().WithMin(0).NewRef()
+	case reflect.Float32, reflect.Float64:
+		return openapi3.NewFloat64Schema().NewRef()
+	case reflect.Slice, reflect.Array:
+		return arrayOf(s.ref(t.Elem()))
+	case reflect.Map:
+		schema := openapi3.NewObjectSchema()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: s.ref(t.Elem())}
+		return schema.NewRef()
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t).NewRef()
+		}
+		return s.component(t)
+	default:
+		return anything()
+	}
+}
+
+// component returns a reference to the component of the named struct t,
+// adding it on first use. DTOs keep their names; types of other packages
+// are prefixed with it, as health.Report becomes HealthReport.
+func (s *schemas) component(t reflect.Type) *openapi3.SchemaRef {
+	name := t.Name()
+	if pkg := path.Base(t.PkgPath()); pkg != "dto" && pkg != "response" {
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	if existing, ok := s.types[name]; ok && existing != t {
+		panic(fmt.Sprintf("openapi: %s and %s are both named %s", existing, t, name))
+	}
+	if _, ok := s.types[name]; !ok {
+		s.types[name] = t
+		s.components[name] = s.object(t).NewRef()
+	}
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, s.components[name].Value)
+}
+
+// define adds a component no Go type describes
+func (s *schemas) define(name string, schema *openapi3.Schema) *openapi3.SchemaRef {
+	if _, ok := s.components[name]; ok {
+		panic("openapi: " + name + " is defined twice")
+	}
+	s.components[name] = schema.NewRef()
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, schema)
+}
+
+// object describes a struct. Request bodies, told apart by their binding
+// tags, require the fields bound as required. Responses require every field
+// encoding/json always writes, and let nil slices and maps be null.
+func (s *schemas) object(t reflect.Type) *openapi3.Schema {
+	fields := structFields(t, "json")
+	request := false
+	for _, f := range fields {
+		request = request || f.binding != ""
+	}
+
+	schema := openapi3.NewObjectSchema()
+	schema.Properties = openapi3.Schemas{}
+	for _, f := range fields {
+		prop := s.field(f)
+		switch f.typ.Kind() {
+		case reflect.Pointer:
+			prop = nullable(prop)
+		case reflect.Slice, reflect.Map:
+			if !request && !f.omitempty {
+				prop = nullable(prop)
+			}
+		}
+		schema.Properties[f.name] = prop
+
+		if request && f.required() || !request && !f.omitempty {
+			schema.Required = append(schema.Required, f.name)
+		}
+	}
+	return schema
+}
+
+// parameters describes the fields of a query DTO, bound by their form tags
+func (s *schemas) parameters(v interface{}) openapi3.Parameters {
+	var params openapi3.Parameters
+	for _, f := range structFields(reflect.TypeOf(v), "form") {
+		param := openapi3.NewQueryParameter(f.name).WithSchema(s.field(f).Value)
+		param.Required = f.required()
+		params = append(params, &openapi3.ParameterRef{Value: param})
+	}
+	return params
+}
+
+// field returns the schema of f's value with the constraints of its tags.
+// Pointers are left for the caller to make nullable or not.
+func (s *schemas) field(f field) *openapi3.SchemaRef {
+	t := f.typ
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	ref := s.ref(t)
+	if ref.Ref == "" {
+		constrain(ref.Value, f.binding)
+		ref.Value.Example = example(ref.Value, f.example)
+	}
+	return ref
+}
+
+// constrain applies validator rules to schema. Rules after dive apply to the
+// items of an array.
+func constrain(schema *openapi3.Schema, binding string) {
+	rules, itemRules, dive := strings.Cut(binding, ",dive")
+	if dive && schema.Items != nil && schema.Items.Ref == "" {
+		constrain(schema.Items.Value, strings.TrimPrefix(itemRules, ","))
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "min", "max":
+			n, err := strconv.ParseUint(param, 10, 64)
+			if err != nil {
+				continue
+			}
+			bound(schema, name == "min", n)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, scalar(schema, value))
+			}
+		case "uuid":
+			schema.Format = "uuid"
+		case "url":
+			schema.Format = "uri"
+		case "startswith":
+			schema.Pattern = "^" + regexp.QuoteMeta(param)
+		case "email":
+			schema.Format = "email"
+		}
+	}
+}
+
+// bound sets the lower or upper limit validator's min and max mean for the
+// schema's type: a length, a value or an item count
+func bound(schema *openapi3.Schema, lower bool, n uint64) {
+	switch {
+	case schema.Type.Includes(openapi3.TypeString):
+		if lower {
+			schema.MinLength = n
+		} else {
+			schema.MaxLength = &n
+		}
+	case schema.Type.Includes(openapi3.TypeArray):
+		if lower {
+			schema.MinItems = n
+		} else {
+			schema.MaxItems = &n
+		}
+	default:
+		limit := float64(n)
+		if lower {
+			schema.Min = &limit
+		} else {
+			schema.Max = &limit
+		}
+	}
+}
+
+// example converts a swag example tag to a value of the schema's type, or
+// nil when it does not parse
+func example(schema *openapi3.Schema, tag string) interface{} {
+	if tag == "" {
+		return nil
+	}
+	if schema.Type.Includes(openapi3.TypeArray) && schema.Items.Ref == "" {
+		var items []interface{}
+		for _, item := range strings.Split(tag, ",") {
+			value := example(schema.Items.Value, item)
+			if value == nil {
+				return nil
+			}
+			items = append(items, value)
+		}
+		return items
+	}
+	value := scalar(schema, tag)
+	if _, unparsed := value.(string); unparsed && !schema.Type.Includes(openapi3.TypeString) {
+		return nil
+	}
+	return value
+}
+
+// scalar parses text as the schema's type, keeping it as text when it does
+// not parse
+func scalar(schema *openapi3.Schema, text string) interface{} {
+	switch {
+	case schema.Type.Includes(openapi3.TypeInteger):
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+	case schema.Type.Includes(openapi3.TypeNumber):
+		if n, err := strconv.ParseFloat(text, 64); err == nil {
+			return n
+		}
+	case schema.Type.Includes(openapi3.TypeBoolean):
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	}
+	return text
+}
+
+func arrayOf(items *openapi3.SchemaRef) *openapi3.SchemaRef {
+	schema := openapi3.NewArraySchema()
+	schema.Items = items
+	return schema.NewRef()
+}
+
+// anything is the schema every value matches, for interface{} and raw JSON.
+// The types are spelled out because kin-openapi rejects null against the
+// empty schema.
+func anything() *openapi3.SchemaRef {
+	types := openapi3.Types{openapi3.TypeArray, openapi3.TypeBoolean, openapi3.TypeNumber, openapi3.TypeObject, openapi3.TypeString, openapi3.TypeNull}
+	return openapi3.NewSchemaRef("", &openapi3.Schema{Type: &types})
+}
+
+// nullable lets ref also be null. In OpenAPI 3.1 that is a type of its own,
+// added to a type, or to a reference as an alternative.
+func nullable(ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if ref.Ref != "" {
+		null := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeNull}}
+		return openapi3.NewSchemaRef("", &openapi3.Schema{AnyOf: openapi3.SchemaRefs{ref, null.NewRef()}})
+	}
+	if ref.Value.Type != nil && !ref.Value.Type.Includes(openapi3.TypeNull) {
+		types := append(ref.Value.Type.Slice(), openapi3.TypeNull)
+		ref.Value.Type = (*openapi3.Types)(&types)
+	}
+	return ref
+}
+
+// field is a struct field as encoded under one tag
+type field struct {
+	name      string
+	typ       reflect.Type
+	omitempty bool
+	binding   string
+	example   string
+}
+
+func (f field) required() bool {
+	rules, _, _ := strings.Cut(f.binding, ",dive")
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields lists the fields of t named by tag, in order. Like
+// encoding/json it promotes the fields of embedded structs that carry no
+// name; unlike it, form fields without a tag are left out.
+func structFields(t reflect.Type, tag string) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts, _ := strings.Cut(sf.Tag.Get(tag), ",")
+		if name == "-" || !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, structFields(embedded, tag)...)
+				continue
+			}
+		}
+		if name == "" {
+			if tag != "json" || !sf.IsExported() {
+				continue
+			}
+			name = sf.Name
+		}
+
+		fields = append(fields, field{
+			name:      name,
+			typ:       sf.Type,
+			omitempty: strings.Contains(","+opts+",", ",omitempty,"),
+			binding:   sf.Tag.Get("binding"),
+			example:   sf.Tag.Get("example"),
+		})
+	}
+	return fields
+}
@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/metrics"
+	"project-simple/internal/repository"
+	"project-simple/internal/webhook"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RelayConfig controls throughput and retries of the relay
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// MaxAttempts is how often an event is tried before it is dead-lettered
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Relay polls the outbox and hands pending events to a sink. Events of the
+// same aggregate are delivered in order: when one fails, the later events of
+// that aggregate wait until it is retried with backoff. After MaxAttempts the
+// event is dead-lettered, and the events behind it go ahead.
+type Relay struct {
+	outboxRepo repository.OutboxRepository
+	transactor repository.Transactor
+	sink       Sink
+	cfg        RelayConfig
+}
+
+func NewRelay(outboxRepo repository.OutboxRepository, transactor repository.Transactor, sink Sink, cfg RelayConfig) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		transactor: transactor,
+		sink:       sink,
+		cfg:        cfg,
+	}
+}
+
+// Run polls until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Dispatch(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Outbox relay error", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Dispatch delivers one batch of pending events and returns how many were
+// published. Only one relay across all replicas dispatches at a time.
+func (r *Relay) Dispatch(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := r.outboxRepo.TryLockRelay(ctx)
+		if err != nil || !locked {
+			return err
+		}
+
+		msgs, err := r.outboxRepo.FindPending(ctx, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		// FindPending leaves out aggregates waiting for a retry; an event
+		// failing in this batch holds back the rest of its aggregate
+		blocked := make(map[uuid.UUID]bool)
+		for _, msg := range msgs {
+			if blocked[msg.AggregateID] {
+				continue
+			}
+
+			// Publish in a savepoint: a sink writing to the database may fail
+			// a statement, which must not abort the relay's transaction
+			err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				return r.sink.Publish(ctx, msg.Envelope())
+			})
+			if err != nil {
+				if err := r.fail(ctx, &msg, err); err != nil {
+					return err
+				}
+				blocked[msg.AggregateID] = msg.DeadLetteredAt == nil
+				continue
+			}
+
+			if err := r.outboxRepo.MarkPublished(ctx, msg.ID); err != nil {
+				return err
+			}
+			published++
+		}
+
+		return nil
+	})
+
+	return published, err
+}
+
+// fail schedules the retry of msg after a failed attempt, or dead-letters it
+// once it has had MaxAttempts
+func (r *Relay) fail(ctx context.Context, msg *entity.OutboxMessage, cause error) error {
+	msg.Attempts++
+	if msg.Attempts < r.cfg.MaxAttempts {
+		next := time.Now().Add(webhook.Backoff(msg.Attempts, r.cfg.BaseBackoff, r.cfg.MaxBackoff))
+		msg.NextAttemptAt = &next
+		return r.outboxRepo.MarkFailed(ctx, msg.ID, cause.Error(), next)
+	}
+
+	if err := r.outboxRepo.MarkDeadLettered(ctx, msg.ID, cause.Error()); err != nil {
+		return err
+	}
+	now := time.Now()
+	msg.DeadLetteredAt = &now
+	metrics.OutboxDeadLetters.Inc()
+	slog.ErrorContext(ctx, "Outbox event dead-lettered",
+		slog.String("event_id", msg.ID.String()),
+		slog.String("event_type", msg.EventType),
+		slog.String("aggregate_id", msg.AggregateID.String()),
+		slog.Int("attempts", msg.Attempts),
+		slog.Any("error", cause))
+	return nil
+}
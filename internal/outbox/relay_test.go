@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/domain/event"
+	"project-simple/internal/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recordingSink struct {
+	delivered []event.Envelope
+	failFor   map[uuid.UUID]bool
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Publish(ctx context.Context, env event.Envelope) error {
+	if s.failFor[env.ID] {
+		return errors.New("sink unavailable")
+	}
+	s.delivered = append(s.delivered, env)
+	return nil
+}
+
+var testConfig = RelayConfig{
+	BatchSize:    10,
+	PollInterval: time.Second,
+	MaxAttempts:  3,
+	BaseBackoff:  time.Second,
+	MaxBackoff:   time.Minute,
+}
+
+// savepointTransactor models a Postgres transaction: once a statement fails
+// the transaction is aborted, unless the failure happened inside a nested
+// transaction, which is rolled back to its savepoint
+type savepointTransactor struct {
+	depth   int
+	aborted bool
+}
+
+func (tx *savepointTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx.depth++
+	defer func() { tx.depth-- }()
+
+	err := fn(ctx)
+	if err != nil && tx.depth > 1 {
+		tx.aborted = false
+	}
+	return err
+}
+
+// dbFailingSink fails a database statement, as a sink writing to Postgres
+// may
+type dbFailingSink struct {
+	tx *savepointTransactor
+}
+
+func (s *dbFailingSink) Name() string {
+	return "db-failing"
+}
+
+func (s *dbFailingSink) Publish(ctx context.Context, env event.Envelope) error {
+	s.tx.aborted = true
+	return errors.New("duplicate key value")
+}
+
+func TestRelay_Dispatch(t *testing.T) {
+	t.Run("Success - Publishes pending events in sequence order", func(t *testing.T) {
+		mockRepo := new(mocks.MockOutboxRepository)
+		sink := &recordingSink{}
+		relay := NewRelay(mockRepo, &mocks.MockTransactor{}, sink, testConfig)
+
+		carID := uuid.New()
+		msgs := []entity.OutboxMessage{
+			{ID: uuid.New(), Sequence: 1, EventType: event.TypeCarCreated, AggregateID: carID},
+			{ID: uuid.New(), Sequence: 2, EventType: event.TypeCarUpdated, AggregateID: carID},
+		}
+
+		mockRepo.On("TryLockRelay", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, 10).Return(msgs, nil)
+		mockRepo.On("MarkPublished", mock.Anything, mock.Anything).Return(nil)
+
+		published, err := relay.Dispatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, published)
+		assert.Len(t, sink.delivered, 2)
+		assert.Equal(t, int64(1), sink.delivered[0].Sequence)
+		assert.Equal(t, int64(2), sink.delivered[1].Sequence)
+		mockRepo.AssertNumberOfCalls(t, "MarkPublished", 2)
+	})
+
+	t.Run("Failure blocks later events of the same aggregate only", func(t *testing.T) {
+		mockRepo := new(mocks.MockOutboxRepository)
+		carA := uuid.New()
+		carB := uuid.New()
+		failing := uuid.New()
+		msgs := []entity.OutboxMessage{
+			{ID: failing, Sequence: 1, AggregateID: carA},
+			{ID: uuid.New(), Sequence: 2, AggregateID: carB},
+			{ID: uuid.New(), Sequence: 3, AggregateID: carA},
+		}
+		sink := &recordingSink{failFor: map[uuid.UUID]bool{failing: true}}
+		relay := NewRelay(mockRepo, &mocks.MockTransactor{}, sink, testConfig)
+
+		mockRepo.On("TryLockRelay", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, 10).Return(msgs, nil)
+		mockRepo.On("MarkFailed", mock.Anything, failing, "sink unavailable", mock.MatchedBy(func(next time.Time) bool {
+			return next.After(time.Now())
+		})).Return(nil)
+		mockRepo.On("MarkPublished", mock.Anything, msgs[1].ID).Return(nil)
+
+		published, err := relay.Dispatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Len(t, sink.delivered, 1)
+		assert.Equal(t, carB, sink.delivered[0].AggregateID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Should dead-letter an event after max attempts and release its aggregate", func(t *testing.T) {
+		mockRepo := new(mocks.MockOutboxRepository)
+		carID := uuid.New()
+		poison := uuid.New()
+		msgs := []entity.OutboxMessage{
+			{ID: poison, Sequence: 1, AggregateID: carID, Attempts: testConfig.MaxAttempts - 1},
+			{ID: uuid.New(), Sequence: 2, AggregateID: carID},
+		}
+		sink := &recordingSink{failFor: map[uuid.UUID]bool{poison: true}}
+		relay := NewRelay(mockRepo, &mocks.MockTransactor{}, sink, testConfig)
+
+		mockRepo.On("TryLockRelay", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, 10).Return(msgs, nil)
+		mockRepo.On("MarkDeadLettered", mock.Anything, poison, "sink unavailable").Return(nil)
+		mockRepo.On("MarkPublished", mock.Anything, msgs[1].ID).Return(nil)
+
+		published, err := relay.Dispatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, int64(2), sink.delivered[0].Sequence)
+		mockRepo.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Should record the failure of a sink that broke a database statement", func(t *testing.T) {
+		mockRepo := new(mocks.MockOutboxRepository)
+		tx := &savepointTransactor{}
+		failing := uuid.New()
+		msgs := []entity.OutboxMessage{{ID: failing, Sequence: 1, AggregateID: uuid.New()}}
+		relay := NewRelay(mockRepo, tx, &dbFailingSink{tx: tx}, testConfig)
+
+		abortedWhenRecorded := true
+		mockRepo.On("TryLockRelay", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, 10).Return(msgs, nil)
+		mockRepo.On("MarkFailed", mock.Anything, failing, "duplicate key value", mock.Anything).
+			Run(func(mock.Arguments) { abortedWhenRecorded = tx.aborted }).
+			Return(nil)
+
+		published, err := relay.Dispatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, published)
+		assert.False(t, abortedWhenRecorded, "the failed statement is rolled back to its savepoint first")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Skips the batch when another relay holds the lock", func(t *testing.T) {
+		mockRepo := new(mocks.MockOutboxRepository)
+		relay := NewRelay(mockRepo, &mocks.MockTransactor{}, &recordingSink{}, testConfig)
+
+		mockRepo.On("TryLockRelay", mock.Anything).Return(false, nil)
+
+		published, err := relay.Dispatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, published)
+		mockRepo.AssertNotCalled(t, "FindPending", mock.Anything, mock.Anything)
+	})
+}
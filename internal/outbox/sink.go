@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"project-simple/internal/domain/event"
+)
+
+// Sink delivers events to a downstream system. Delivery is at least once, so
+// sinks must tolerate receiving the same event ID more than once.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, env event.Envelope) error
+}
+
+// LogSink writes every event to the application log
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Publish(ctx context.Context, env event.Envelope) error {
+	slog.InfoContext(ctx, "Event published",
+		slog.String("type", env.Type),
+		slog.String("aggregate_type", env.AggregateType),
+		slog.String("aggregate_id", env.AggregateID.String()),
+		slog.Int64("sequence", env.Sequence),
+		slog.String("event_id", env.ID.String()))
+	return nil
+}
+
+// MultiSink fans an event out to several sinks. An event only counts as
+// delivered once every sink has accepted it.
+type MultiSink []Sink
+
+func (m MultiSink) Name() string {
+	return "multi"
+}
+
+func (m MultiSink) Publish(ctx context.Context, env event.Envelope) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Publish(ctx, env); err != nil {
+			errs = append(errs, errors.New(sink.Name()+": "+err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
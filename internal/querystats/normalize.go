@@ -0,0 +1,26 @@
+package querystats
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberLiteral  = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	placeholder    = regexp.MustCompile(`\$\d+`)
+	placeholderSet = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// Normalize reduces a statement to its shape: literals and placeholders
+// become ?, lists of values collapse to (?) and whitespace is folded, so
+// statements differing only in their arguments are grouped together
+func Normalize(sql string) string {
+	shape := stringLiteral.ReplaceAllString(sql, "?")
+	shape = placeholder.ReplaceAllString(shape, "?")
+	shape = numberLiteral.ReplaceAllString(shape, "?")
+	shape = placeholderSet.ReplaceAllString(shape, "(?)")
+	shape = whitespace.ReplaceAllString(shape, " ")
+	return strings.TrimSpace(shape)
+}
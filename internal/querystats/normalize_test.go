@@ -0,0 +1,42 @@
+package querystats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "Placeholders",
+			sql:  `SELECT * FROM "cars" WHERE id = $1 AND "cars"."deleted_at" IS NULL LIMIT $2`,
+			want: `SELECT * FROM "cars" WHERE id = ? AND "cars"."deleted_at" IS NULL LIMIT ?`,
+		},
+		{
+			name: "Literals",
+			sql:  `SELECT * FROM cars WHERE name = 'O''Brien' LIMIT 10 OFFSET 20`,
+			want: `SELECT * FROM cars WHERE name = ? LIMIT ? OFFSET ?`,
+		},
+		{
+			name: "Value lists collapse",
+			sql:  `SELECT * FROM cars WHERE id IN ($1,$2, $3)`,
+			want: `SELECT * FROM cars WHERE id IN (?)`,
+		},
+		{
+			name: "Whitespace folds and identifiers keep digits",
+			sql:  "SELECT v1.version\n\t FROM car_versions v1",
+			want: "SELECT v1.version FROM car_versions v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.sql))
+		})
+	}
+}
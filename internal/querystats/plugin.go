@@ -0,0 +1,76 @@
+package querystats
+
+import (
+	"errors"
+	"log/slog"
+	"project-simple/internal/logging"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startedAtKey = "querystats:started_at"
+
+// plugin times every statement, feeds the recorder and logs statements slower
+// than the threshold through the logger of the request that ran them
+type plugin struct {
+	recorder  *Recorder
+	threshold time.Duration
+}
+
+func NewPlugin(recorder *Recorder, threshold time.Duration) gorm.Plugin {
+	return &plugin{recorder: recorder, threshold: threshold}
+}
+
+func (p *plugin) Name() string {
+	return "querystats"
+}
+
+func (p *plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("querystats:before_create", p.start),
+		cb.Create().After("gorm:create").Register("querystats:after_create", p.end),
+		cb.Query().Before("gorm:query").Register("querystats:before_query", p.start),
+		cb.Query().After("gorm:query").Register("querystats:after_query", p.end),
+		cb.Update().Before("gorm:update").Register("querystats:before_update", p.start),
+		cb.Update().After("gorm:update").Register("querystats:after_update", p.end),
+		cb.Delete().Before("gorm:delete").Register("querystats:before_delete", p.start),
+		cb.Delete().After("gorm:delete").Register("querystats:after_delete", p.end),
+		cb.Row().Before("gorm:row").Register("querystats:before_row", p.start),
+		cb.Row().After("gorm:row").Register("querystats:after_row", p.end),
+		cb.Raw().Before("gorm:raw").Register("querystats:before_raw", p.start),
+		cb.Raw().After("gorm:raw").Register("querystats:after_raw", p.end),
+	)
+}
+
+func (p *plugin) start(db *gorm.DB) {
+	db.InstanceSet(startedAtKey, time.Now())
+}
+
+func (p *plugin) end(db *gorm.DB) {
+	value, ok := db.InstanceGet(startedAtKey)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(value.(time.Time))
+
+	sql := db.Statement.SQL.String()
+	if sql == "" {
+		// Nothing was sent, e.g. a dry run or a callback that failed early
+		return
+	}
+	shape := Normalize(sql)
+	p.recorder.Record(shape, elapsed)
+
+	if p.threshold > 0 && elapsed > p.threshold {
+		ctx := db.Statement.Context
+		logging.FromContext(ctx).WarnContext(ctx, "slow query",
+			slog.String("shape", shape),
+			slog.Duration("elapsed", elapsed),
+			slog.Duration("threshold", p.threshold),
+			slog.Int64("rows", db.RowsAffected),
+		)
+	}
+}
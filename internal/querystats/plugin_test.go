@@ -0,0 +1,59 @@
+package querystats
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newDryRunDB(t *testing.T, recorder *Recorder, threshold time.Duration) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewPlugin(recorder, threshold)))
+	return db
+}
+
+func TestPlugin(t *testing.T) {
+	t.Run("Records statements by shape", func(t *testing.T) {
+		recorder := NewRecorder(10, 10)
+		db := newDryRunDB(t, recorder, time.Hour)
+
+		var car entity.Car
+		db.First(&car, "id = ?", uuid.New())
+		db.First(&car, "id = ?", uuid.New())
+
+		stats := recorder.Top(10, SortCount)
+		require.Len(t, stats, 1)
+		assert.Equal(t, int64(2), stats[0].Count)
+		assert.Contains(t, stats[0].Shape, `FROM "cars" WHERE id = ?`)
+	})
+
+	t.Run("Logs slow statements with the request logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.FormatJSON, slog.LevelInfo)
+		require.NoError(t, err)
+		ctx := logging.WithLogger(context.Background(), logger.With(slog.String("request_id", "req-1")))
+
+		db := newDryRunDB(t, NewRecorder(10, 10), time.Nanosecond)
+
+		var cars []entity.Car
+		db.WithContext(ctx).Find(&cars)
+
+		assert.Contains(t, buf.String(), `"msg":"slow query"`)
+		assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+	})
+}
@@ -0,0 +1,161 @@
+package querystats
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OtherShape collects statements once the number of tracked shapes reaches
+// its limit, so unbounded dynamic SQL cannot exhaust memory
+const OtherShape = "(other)"
+
+// Sort orders accepted by Top
+const (
+	SortP95   = "p95"
+	SortP99   = "p99"
+	SortMax   = "max"
+	SortTotal = "total"
+	SortCount = "count"
+)
+
+// ShapeStats summarises the statements of one shape. Percentiles cover the
+// most recent samples only, so they follow the current behaviour of the
+// database rather than its whole history.
+type ShapeStats struct {
+	Shape   string    `json:"shape"`
+	Count   int64     `json:"count"`
+	TotalMs float64   `json:"total_ms"`
+	MeanMs  float64   `json:"mean_ms"`
+	P50Ms   float64   `json:"p50_ms"`
+	P95Ms   float64   `json:"p95_ms"`
+	P99Ms   float64   `json:"p99_ms"`
+	MaxMs   float64   `json:"max_ms"`
+	LastAt  time.Time `json:"last_at"`
+	Samples int       `json:"samples"`
+}
+
+type shape struct {
+	count   int64
+	total   time.Duration
+	max     time.Duration
+	lastAt  time.Time
+	samples []time.Duration
+	next    int
+}
+
+// Recorder keeps rolling duration statistics per query shape
+type Recorder struct {
+	window    int
+	maxShapes int
+
+	mu     sync.Mutex
+	shapes map[string]*shape
+}
+
+// NewRecorder keeps the last window durations of at most maxShapes shapes
+func NewRecorder(window, maxShapes int) *Recorder {
+	return &Recorder{
+		window:    window,
+		maxShapes: maxShapes,
+		shapes:    make(map[string]*shape),
+	}
+}
+
+// Record adds the duration of one statement of the given shape
+func (r *Recorder) Record(shapeKey string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.shapes[shapeKey]
+	if !ok {
+		if len(r.shapes) >= r.maxShapes {
+			shapeKey = OtherShape
+			s = r.shapes[OtherShape]
+		}
+		if s == nil {
+			s = &shape{samples: make([]time.Duration, 0, r.window)}
+			r.shapes[shapeKey] = s
+		}
+	}
+
+	s.count++
+	s.total += d
+	s.max = max(s.max, d)
+	s.lastAt = time.Now()
+
+	if len(s.samples) < r.window {
+		s.samples = append(s.samples, d)
+	} else {
+		s.samples[s.next] = d
+		s.next = (s.next + 1) % r.window
+	}
+}
+
+// Top returns the n slowest shapes ordered by sortBy, which defaults to p95
+func (r *Recorder) Top(n int, sortBy string) []ShapeStats {
+	r.mu.Lock()
+	stats := make([]ShapeStats, 0, len(r.shapes))
+	for key, s := range r.shapes {
+		stats = append(stats, s.summary(key))
+	}
+	r.mu.Unlock()
+
+	less := map[string]func(a, b ShapeStats) bool{
+		SortP95:   func(a, b ShapeStats) bool { return a.P95Ms > b.P95Ms },
+		SortP99:   func(a, b ShapeStats) bool { return a.P99Ms > b.P99Ms },
+		SortMax:   func(a, b ShapeStats) bool { return a.MaxMs > b.MaxMs },
+		SortTotal: func(a, b ShapeStats) bool { return a.TotalMs > b.TotalMs },
+		SortCount: func(a, b ShapeStats) bool { return a.Count > b.Count },
+	}[sortBy]
+	if less == nil {
+		less = func(a, b ShapeStats) bool { return a.P95Ms > b.P95Ms }
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return less(stats[i], stats[j]) })
+
+	if n > 0 && len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+// Reset discards every statistic
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shapes = make(map[string]*shape)
+}
+
+func (s *shape) summary(key string) ShapeStats {
+	sorted := slices.Clone(s.samples)
+	slices.Sort(sorted)
+
+	return ShapeStats{
+		Shape:   key,
+		Count:   s.count,
+		TotalMs: ms(s.total),
+		MeanMs:  ms(s.total / time.Duration(s.count)),
+		P50Ms:   ms(percentile(sorted, 0.50)),
+		P95Ms:   ms(percentile(sorted, 0.95)),
+		P99Ms:   ms(percentile(sorted, 0.99)),
+		MaxMs:   ms(s.max),
+		LastAt:  s.lastAt,
+		Samples: len(sorted),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
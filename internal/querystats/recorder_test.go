@@ -0,0 +1,83 @@
+package querystats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("Computes percentiles per shape", func(t *testing.T) {
+		recorder := NewRecorder(100, 10)
+		for i := 1; i <= 100; i++ {
+			recorder.Record("SELECT ?", time.Duration(i)*time.Millisecond)
+		}
+
+		stats := recorder.Top(10, SortP95)
+		require.Len(t, stats, 1)
+		assert.Equal(t, int64(100), stats[0].Count)
+		assert.Equal(t, 50.0, stats[0].P50Ms)
+		assert.Equal(t, 95.0, stats[0].P95Ms)
+		assert.Equal(t, 99.0, stats[0].P99Ms)
+		assert.Equal(t, 100.0, stats[0].MaxMs)
+		assert.Equal(t, 50.5, stats[0].MeanMs)
+	})
+
+	t.Run("Percentiles roll over the window", func(t *testing.T) {
+		recorder := NewRecorder(10, 10)
+		for range 10 {
+			recorder.Record("SELECT ?", time.Second)
+		}
+		for range 10 {
+			recorder.Record("SELECT ?", time.Millisecond)
+		}
+
+		stats := recorder.Top(1, SortP95)[0]
+		assert.Equal(t, 1.0, stats.P99Ms)
+		assert.Equal(t, 1000.0, stats.MaxMs)
+		assert.Equal(t, int64(20), stats.Count)
+		assert.Equal(t, 10, stats.Samples)
+	})
+
+	t.Run("Orders and limits the slowest shapes", func(t *testing.T) {
+		recorder := NewRecorder(10, 10)
+		recorder.Record("fast", time.Millisecond)
+		recorder.Record("slow", time.Second)
+		recorder.Record("medium", 100*time.Millisecond)
+		for range 5 {
+			recorder.Record("frequent", 2*time.Millisecond)
+		}
+
+		top := recorder.Top(2, SortP95)
+		require.Len(t, top, 2)
+		assert.Equal(t, "slow", top[0].Shape)
+		assert.Equal(t, "medium", top[1].Shape)
+
+		assert.Equal(t, "frequent", recorder.Top(1, SortCount)[0].Shape)
+	})
+
+	t.Run("Caps the number of shapes", func(t *testing.T) {
+		recorder := NewRecorder(10, 2)
+		recorder.Record("a", time.Millisecond)
+		recorder.Record("b", time.Millisecond)
+		recorder.Record("c", time.Millisecond)
+		recorder.Record("d", time.Millisecond)
+		recorder.Record("a", time.Millisecond)
+
+		shapes := map[string]int64{}
+		for _, s := range recorder.Top(0, SortCount) {
+			shapes[s.Shape] = s.Count
+		}
+		assert.Equal(t, map[string]int64{"a": 2, "b": 1, OtherShape: 2}, shapes)
+	})
+
+	t.Run("Reset discards statistics", func(t *testing.T) {
+		recorder := NewRecorder(10, 10)
+		recorder.Record("a", time.Millisecond)
+		recorder.Reset()
+
+		assert.Empty(t, recorder.Top(10, SortP95))
+	})
+}
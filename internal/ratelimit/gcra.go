@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit allows Requests per Period, in bursts of up to Burst requests.
+// A zero Burst allows the full Requests at once.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// interval is the time one request costs: the bucket refills one request
+// every interval
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// tolerance is how far ahead of now the bucket may be scheduled: the cost of
+// a full burst
+func (l Limit) tolerance() time.Duration {
+	burst := l.Burst
+	if burst <= 0 {
+		burst = l.Requests
+	}
+	return l.interval() * time.Duration(burst)
+}
+
+func (l Limit) burst() int {
+	if l.Burst <= 0 {
+		return l.Requests
+	}
+	return l.Burst
+}
+
+// Result is the outcome of one request against a limit
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request would be allowed; zero
+	// when Allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the state of every bucket. Allow must decide and record
+// atomically, so concurrent requests on any instance never exceed the limit.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra applies the generic cell rate algorithm. tat is the theoretical arrival
+// time stored for the bucket: the time at which it would be full again. It
+// returns the decision and the tat to store.
+func gcra(tat, now time.Time, limit Limit) (Result, time.Time) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	next := tat.Add(limit.interval())
+	allowAt := next.Add(-limit.tolerance())
+	if now.Before(allowAt) {
+		return result(false, tat, now, limit, allowAt.Sub(now)), tat
+	}
+	return result(true, next, now, limit, 0), next
+}
+
+// result describes the bucket scheduled up to tat, as seen at now
+func result(allowed bool, tat, now time.Time, limit Limit, retryAfter time.Duration) Result {
+	ahead := tat.Sub(now)
+	if ahead < 0 {
+		ahead = 0
+	}
+
+	remaining := int((limit.tolerance() - ahead) / limit.interval())
+	remaining = max(0, min(remaining, limit.burst()))
+
+	return Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  remaining,
+		Reset:      ahead,
+		RetryAfter: retryAfter,
+	}
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cleanupInterval is how often stores drop buckets that are full again
+const cleanupInterval = time.Minute
+
+// MemoryStore keeps buckets in process memory. Limits are per instance, so
+// they multiply with the replica count.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]time.Time
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, tat := gcra(s.buckets[key], s.now(), limit)
+	s.buckets[key] = tat
+	return res, nil
+}
+
+// Run drops full buckets every cleanupInterval until ctx is cancelled
+func (s *MemoryStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.cleanup()
+		}
+	}
+}
+
+func (s *MemoryStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, tat := range s.buckets {
+		if !tat.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
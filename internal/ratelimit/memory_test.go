@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 10, Period: 10 * time.Second}
+
+	newStore := func() (*MemoryStore, *time.Time) {
+		now := time.Now()
+		store := NewMemoryStore()
+		store.now = func() time.Time { return now }
+		return store, &now
+	}
+
+	t.Run("Should allow a full burst and then reject", func(t *testing.T) {
+		store, _ := newStore()
+
+		for i := range 10 {
+			res, err := store.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 10, res.Limit)
+			assert.Equal(t, 9-i, res.Remaining)
+		}
+
+		res, _ := store.Allow(ctx, "k", limit)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+		assert.Equal(t, time.Second, res.RetryAfter)
+		assert.Equal(t, 10*time.Second, res.Reset)
+	})
+
+	t.Run("Should refill one request per interval", func(t *testing.T) {
+		store, now := newStore()
+		for range 10 {
+			_, _ = store.Allow(ctx, "k", limit)
+		}
+
+		*now = now.Add(time.Second)
+		res, _ := store.Allow(ctx, "k", limit)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+
+		res, _ = store.Allow(ctx, "k", limit)
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("Should not let bursts through at window edges", func(t *testing.T) {
+		store, now := newStore()
+		*now = now.Add(9 * time.Second)
+		for range 10 {
+			_, _ = store.Allow(ctx, "k", limit)
+		}
+
+		// A fixed window would reset here and allow another 10
+		*now = now.Add(time.Second)
+		allowed := 0
+		for range 10 {
+			if res, _ := store.Allow(ctx, "k", limit); res.Allowed {
+				allowed++
+			}
+		}
+		assert.Equal(t, 1, allowed)
+	})
+
+	t.Run("Should honour a smaller burst", func(t *testing.T) {
+		store, _ := newStore()
+		limit := Limit{Requests: 10, Period: 10 * time.Second, Burst: 2}
+
+		res, _ := store.Allow(ctx, "k", limit)
+		assert.Equal(t, 1, res.Remaining)
+		_, _ = store.Allow(ctx, "k", limit)
+		res, _ = store.Allow(ctx, "k", limit)
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("Should keep separate buckets per key", func(t *testing.T) {
+		store, _ := newStore()
+		for range 10 {
+			_, _ = store.Allow(ctx, "a", limit)
+		}
+
+		res, _ := store.Allow(ctx, "b", limit)
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("Should drop full buckets on cleanup", func(t *testing.T) {
+		store, now := newStore()
+		_, _ = store.Allow(ctx, "k", limit)
+
+		store.cleanup()
+		assert.Len(t, store.buckets, 1)
+
+		*now = now.Add(time.Second)
+		store.cleanup()
+		assert.Empty(t, store.buckets)
+	})
+
+	t.Run("Should stop the cleanup loop when the context is cancelled", func(t *testing.T) {
+		store := NewMemoryStore()
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			store.Run(runCtx)
+			close(done)
+		}()
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("cleanup loop did not stop")
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PostgresStore keeps buckets in the rate_limits table, shared by every
+// instance. Times come from the database clock, so clock skew between
+// instances does not matter.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	args := map[string]any{
+		"key":       key,
+		"interval":  limit.interval().Microseconds(),
+		"tolerance": limit.tolerance().Microseconds(),
+	}
+
+	// Schedules the request in one statement when it conforms; returns no row
+	// when it does not
+	var tat, now time.Time
+	err := s.db.WithContext(ctx).Raw(takeSQL, args).Row().Scan(&tat, &now)
+	if err == nil {
+		return result(true, tat, now, limit, 0), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return Result{}, err
+	}
+
+	if err := s.db.WithContext(ctx).Raw(peekSQL, args).Row().Scan(&tat, &now); err != nil {
+		return Result{}, err
+	}
+	retryAfter := max(0, tat.Add(limit.interval()-limit.tolerance()).Sub(now))
+	return result(false, tat, now, limit, retryAfter), nil
+}
+
+// Run deletes full buckets every cleanupInterval until ctx is cancelled
+func (s *PostgresStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.db.WithContext(ctx).Exec("DELETE FROM rate_limits WHERE tat < now()").Error; err != nil && ctx.Err() == nil {
+				slog.Warn("Failed to clean up rate limit buckets", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// takeSQL moves the bucket's theoretical arrival time one interval ahead if
+// the request conforms: if the new time is no further ahead than the tolerance
+const takeSQL = `
+INSERT INTO rate_limits (key, tat) VALUES (@key, now() + @interval * interval '1 microsecond')
+ON CONFLICT (key) DO UPDATE
+SET tat = GREATEST(rate_limits.tat, now()) + @interval * interval '1 microsecond'
+WHERE GREATEST(rate_limits.tat, now()) + (@interval - @tolerance) * interval '1 microsecond' <= now()
+RETURNING tat, now()
+`
+
+const peekSQL = `SELECT tat, now() FROM rate_limits WHERE key = @key`
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps buckets in Redis, shared by every instance. Each bucket is
+// a key holding its theoretical arrival time in microseconds, expiring when
+// the bucket is full again, so no cleanup is needed. Decisions run in a
+// script on the Redis clock.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// gcraScript returns {allowed, tat, now}, times in microseconds
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local next = tat + interval
+if next - tolerance > now then
+	return {0, tat, now}
+end
+
+redis.call('SET', KEYS[1], next, 'PX', math.ceil((next - now) / 1000))
+return {1, next, now}
+`)
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	interval := limit.interval().Microseconds()
+	tolerance := limit.tolerance().Microseconds()
+
+	values, err := gcraScript.Run(ctx, s.client, []string{"carapi:ratelimit:" + key}, interval, tolerance).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed := values[0] == 1
+	tat := time.UnixMicro(values[1])
+	now := time.UnixMicro(values[2])
+	if allowed {
+		return result(true, tat, now, limit, 0), nil
+	}
+	retryAfter := max(0, tat.Add(limit.interval()-limit.tolerance()).Sub(now))
+	return result(false, tat, now, limit, retryAfter), nil
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	newStore := func(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return NewRedisStore(client), server
+	}
+
+	t.Run("Should allow a full burst and then reject", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		for i := range 3 {
+			res, err := store.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 2-i, res.Remaining)
+		}
+
+		res, err := store.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.InDelta(t, 20*time.Second, res.RetryAfter, float64(time.Second))
+	})
+
+	t.Run("Should share buckets between instances", func(t *testing.T) {
+		first, server := newStore(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		second := NewRedisStore(client)
+
+		for range 3 {
+			_, _ = first.Allow(ctx, "k", limit)
+		}
+
+		res, err := second.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("Should expire buckets once they are full again", func(t *testing.T) {
+		store, server := newStore(t)
+		_, _ = store.Allow(ctx, "k", limit)
+
+		ttl := server.TTL("carapi:ratelimit:k")
+		assert.Greater(t, ttl, time.Duration(0))
+		assert.LessOrEqual(t, ttl, 20*time.Second)
+	})
+
+	t.Run("Error - Store down", func(t *testing.T) {
+		store, server := newStore(t)
+		server.Close()
+
+		_, err := store.Allow(ctx, "k", limit)
+		assert.Error(t, err)
+	})
+}
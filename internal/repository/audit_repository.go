@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditRepository is append-only: entries can be written and queried but
+// never updated or deleted
+type AuditRepository interface {
+	Create(ctx context.Context, entry *entity.AuditLog) error
+	FindAll(ctx context.Context, filter *dto.AuditLogFilter) ([]entity.AuditLog, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *entity.AuditLog) error {
+	return conn(ctx, r.db).Create(entry).Error
+}
+
+func (r *auditRepository) FindAll(ctx context.Context, filter *dto.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	var entries []entity.AuditLog
+	var total int64
+
+	query := conn(ctx, r.db).Model(&entity.AuditLog{})
+
+	if filter.EntityID != "" {
+		entityID, err := uuid.Parse(filter.EntityID)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("entity_id = ?", entityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at <= ?", filter.To)
+	}
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Limit(filter.PageSize).
+		Offset(filter.GetOffset()).
+		Find(&entries).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -1,23 +1,33 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"project-simple/internal/domain/dto"
 	"project-simple/internal/domain/entity"
+	"project-simple/internal/infrastructure/database"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type CarRepository interface {
-	Create(car *entity.Car) error
-	FindByID(id uuid.UUID) (*entity.Car, error)
-	FindAll(pagination *dto.PaginationRequest) ([]entity.Car, int64, error)
-	Update(car *entity.Car) error
-	Delete(id uuid.UUID) error
-	ExistsByID(id uuid.UUID) (bool, error)
+	Create(ctx context.Context, car *entity.Car) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Car, error)
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Car, error)
+	FindAll(ctx context.Context, pagination *dto.PaginationRequest) ([]entity.Car, int64, error)
+	Each(ctx context.Context, filter *dto.CarExportRequest, fn func(*entity.Car) error) error
+	Update(ctx context.Context, car *entity.Car) error
+	Revert(ctx context.Context, car *entity.Car, fromVersion int) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	ExistsByID(ctx context.Context, id uuid.UUID) (bool, error)
+	CountByEngineVersion(ctx context.Context) (map[string]int64, error)
 }
 
+// carRepository records a CarVersion in the same transaction as every write,
+// so the history in car_versions never diverges from the cars table
 type carRepository struct {
 	db *gorm.DB
 }
@@ -26,13 +36,18 @@ func NewCarRepository(db *gorm.DB) CarRepository {
 	return &carRepository{db: db}
 }
 
-func (r *carRepository) Create(car *entity.Car) error {
-	return r.db.Create(car).Error
+func (r *carRepository) Create(ctx context.Context, car *entity.Car) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(car).Error; err != nil {
+			return err
+		}
+		return recordCarVersion(tx, car, entity.CarVersionOpCreate, nil)
+	})
 }
 
-func (r *carRepository) FindByID(id uuid.UUID) (*entity.Car, error) {
+func (r *carRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Car, error) {
 	var car entity.Car
-	err := r.db.Where("id = ?", id).First(&car).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&car).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrCarNotFound
@@ -42,17 +57,34 @@ func (r *carRepository) FindByID(id uuid.UUID) (*entity.Car, error) {
 	return &car, nil
 }
 
-func (r *carRepository) FindAll(pagination *dto.PaginationRequest) ([]entity.Car, int64, error) {
+func (r *carRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Car, error) {
+	var car entity.Car
+	err := conn(ctx, r.db).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&car).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCarNotFound
+		}
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (r *carRepository) FindAll(ctx context.Context, pagination *dto.PaginationRequest) ([]entity.Car, int64, error) {
 	var cars []entity.Car
 	var total int64
 
+	// A new session lets the count and the page share the filter
+	db := filterCars(conn(ctx, r.db).Model(&entity.Car{}), pagination.CarFilter).Session(&gorm.Session{})
+
 	// Count total records
-	if err := r.db.Model(&entity.Car{}).Count(&total).Error; err != nil {
+	if err := db.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated records with sorting
-	err := r.db.
+	err := db.
 		Order(pagination.GetOrderBy()).
 		Limit(pagination.PageSize).
 		Offset(pagination.GetOffset()).
@@ -65,45 +97,159 @@ func (r *carRepository) FindAll(pagination *dto.PaginationRequest) ([]entity.Car
 	return cars, total, nil
 }
 
-func (r *carRepository) Update(car *entity.Car) error {
-	result := r.db.Model(&entity.Car{}).
-		Where("id = ?", car.ID).
-		Updates(map[string]interface{}{
-			"name":           car.Name,
-			"engine_version": car.EngineVersion,
-		})
+// Each calls fn for every car matching filter, oldest first. Rows are read
+// from the result cursor as fn consumes them, so memory stays constant
+// however many cars there are. The query outlives any statement deadline;
+// only ctx bounds it.
+func (r *carRepository) Each(ctx context.Context, filter *dto.CarExportRequest, fn func(*entity.Car) error) error {
+	db := conn(database.WithoutQueryTimeout(ctx), r.db)
 
-	if result.Error != nil {
-		return result.Error
+	query := filterCars(db.Model(&entity.Car{}), filter.CarFilter).Order("created_at, id")
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
 	}
 
-	if result.RowsAffected == 0 {
-		return ErrCarNotFound
+	rows, err := query.Rows()
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var car entity.Car
+		if err := db.ScanRows(rows, &car); err != nil {
+			return err
+		}
+		if err := fn(&car); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
-func (r *carRepository) Delete(id uuid.UUID) error {
-	result := r.db.Where("id = ?", id).Delete(&entity.Car{})
-
-	if result.Error != nil {
-		return result.Error
+// filterCars restricts query to the cars matching filter
+func filterCars(query *gorm.DB, filter dto.CarFilter) *gorm.DB {
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ? ESCAPE '\\'", "%"+likeEscaper.Replace(filter.Name)+"%")
 	}
-
-	if result.RowsAffected == 0 {
-		return ErrCarNotFound
+	if filter.EngineVersion != "" {
+		query = query.Where("engine_version = ?", filter.EngineVersion)
 	}
+	return query
+}
+
+// likeEscaper escapes LIKE wildcards so filters match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *carRepository) Update(ctx context.Context, car *entity.Car) error {
+	return r.update(ctx, car, entity.CarVersionOpUpdate, nil)
+}
 
-	return nil
+func (r *carRepository) Revert(ctx context.Context, car *entity.Car, fromVersion int) error {
+	return r.update(ctx, car, entity.CarVersionOpRevert, &fromVersion)
 }
 
-func (r *carRepository) ExistsByID(id uuid.UUID) (bool, error) {
+func (r *carRepository) update(ctx context.Context, car *entity.Car, operation string, revertedFrom *int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Car{}).
+			Where("id = ?", car.ID).
+			Updates(map[string]interface{}{
+				"name":           car.Name,
+				"engine_version": car.EngineVersion,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrCarNotFound
+		}
+
+		// Re-read the row to snapshot the timestamps set by the database
+		var current entity.Car
+		if err := tx.Where("id = ?", car.ID).First(&current).Error; err != nil {
+			return err
+		}
+
+		return recordCarVersion(tx, &current, operation, revertedFrom)
+	})
+}
+
+func (r *carRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&entity.Car{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrCarNotFound
+		}
+
+		var deleted entity.Car
+		if err := tx.Unscoped().Where("id = ?", id).First(&deleted).Error; err != nil {
+			return err
+		}
+
+		return recordCarVersion(tx, &deleted, entity.CarVersionOpDelete, nil)
+	})
+}
+
+func (r *carRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&entity.Car{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrCarNotFound
+		}
+
+		var restored entity.Car
+		if err := tx.Where("id = ?", id).First(&restored).Error; err != nil {
+			return err
+		}
+
+		return recordCarVersion(tx, &restored, entity.CarVersionOpRestore, nil)
+	})
+}
+
+func (r *carRepository) ExistsByID(ctx context.Context, id uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&entity.Car{}).Where("id = ?", id).Count(&count).Error
+	err := conn(ctx, r.db).Model(&entity.Car{}).Where("id = ?", id).Count(&count).Error
 	return count > 0, err
 }
 
+// CountByEngineVersion returns the number of live cars per engine version
+func (r *carRepository) CountByEngineVersion(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		EngineVersion string
+		Count         int64
+	}
+	err := conn(ctx, r.db).Model(&entity.Car{}).
+		Select("engine_version, COUNT(*) AS count").
+		Group("engine_version").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.EngineVersion] = row.Count
+	}
+	return counts, nil
+}
+
 var (
 	ErrCarNotFound = errors.New("car not found")
 )
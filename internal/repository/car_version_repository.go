@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CarVersionRepository reads the history written by CarRepository. Versions
+// are only ever recorded as a side effect of a car mutation.
+type CarVersionRepository interface {
+	FindByCarID(ctx context.Context, carID uuid.UUID) ([]entity.CarVersion, error)
+	FindVersion(ctx context.Context, carID uuid.UUID, version int) (*entity.CarVersion, error)
+	FindAsOf(ctx context.Context, carID uuid.UUID, asOf time.Time) (*entity.CarVersion, error)
+}
+
+type carVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewCarVersionRepository(db *gorm.DB) CarVersionRepository {
+	return &carVersionRepository{db: db}
+}
+
+func (r *carVersionRepository) FindByCarID(ctx context.Context, carID uuid.UUID) ([]entity.CarVersion, error) {
+	var versions []entity.CarVersion
+	err := conn(ctx, r.db).
+		Where("car_id = ?", carID).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+func (r *carVersionRepository) FindVersion(ctx context.Context, carID uuid.UUID, version int) (*entity.CarVersion, error) {
+	var v entity.CarVersion
+	err := conn(ctx, r.db).
+		Where("car_id = ? AND version = ?", carID, version).
+		First(&v).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCarVersionNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *carVersionRepository) FindAsOf(ctx context.Context, carID uuid.UUID, asOf time.Time) (*entity.CarVersion, error) {
+	var v entity.CarVersion
+	err := conn(ctx, r.db).
+		Where("car_id = ? AND recorded_at <= ?", carID, asOf).
+		Order("version DESC").
+		First(&v).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCarVersionNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// recordCarVersion appends a snapshot of car to its history. It must run in
+// the same transaction as the change, after the car row has been written,
+// so the row lock serialises version numbers per car.
+func recordCarVersion(tx *gorm.DB, car *entity.Car, operation string, revertedFrom *int) error {
+	var next int
+	if err := tx.Model(&entity.CarVersion{}).
+		Where("car_id = ?", car.ID).
+		Select("COALESCE(MAX(version), 0) + 1").
+		Scan(&next).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&entity.CarVersion{
+		CarID:         car.ID,
+		Version:       next,
+		Operation:     operation,
+		RevertedFrom:  revertedFrom,
+		Name:          car.Name,
+		EngineVersion: car.EngineVersion,
+		Deleted:       car.DeletedAt.Valid,
+		CarCreatedAt:  car.CreatedAt,
+		CarUpdatedAt:  car.UpdatedAt,
+		RecordedAt:    time.Now(),
+	}).Error
+}
+
+var (
+	ErrCarVersionNotFound = errors.New("car version not found")
+)
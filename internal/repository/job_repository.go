@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type JobRepository interface {
+	Create(ctx context.Context, job *entity.Job) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Job, error)
+	Claim(ctx context.Context, worker string, lease time.Duration) (*entity.Job, error)
+	Heartbeat(ctx context.Context, job *entity.Job, lease time.Duration) (bool, error)
+	Release(ctx context.Context, job *entity.Job) error
+	RequestCancel(ctx context.Context, id uuid.UUID) (*entity.Job, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *entity.Job) error {
+	return conn(ctx, r.db).Create(job).Error
+}
+
+func (r *jobRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	var job entity.Job
+	err := conn(ctx, r.db).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// claimJobSQL takes the next due job, or a running job whose worker stopped
+// renewing its lease, in one statement. Rows locked by another worker are
+// skipped, so workers never wait on each other.
+const claimJobSQL = `
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_by = @worker, locked_until = @until,
+	started_at = COALESCE(started_at, @now), updated_at = @now
+WHERE id = (
+	SELECT id FROM jobs
+	WHERE (status = 'queued' AND run_at <= @now) OR (status = 'running' AND locked_until < @now)
+	ORDER BY run_at
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *
+`
+
+// Claim leases the next due job to worker, or returns nil when there is none
+func (r *jobRepository) Claim(ctx context.Context, worker string, lease time.Duration) (*entity.Job, error) {
+	now := time.Now()
+	var jobs []entity.Job
+	err := conn(ctx, r.db).Raw(claimJobSQL, map[string]interface{}{
+		"worker": worker,
+		"until":  now.Add(lease),
+		"now":    now,
+	}).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// Heartbeat renews the lease of a claimed job and saves its progress. It
+// reports whether cancellation was requested, and returns ErrJobLeaseLost
+// when another worker has taken the job over.
+func (r *jobRepository) Heartbeat(ctx context.Context, job *entity.Job, lease time.Duration) (bool, error) {
+	var cancelRequested []bool
+	err := conn(ctx, r.db).Raw(`
+UPDATE jobs SET locked_until = ?, progress_done = ?, progress_total = ?, updated_at = ?
+WHERE id = ? AND status = 'running' AND locked_by = ?
+RETURNING cancel_requested`,
+		time.Now().Add(lease), job.ProgressDone, job.ProgressTotal, time.Now(), job.ID, job.LockedBy,
+	).Scan(&cancelRequested).Error
+	if err != nil {
+		return false, err
+	}
+	if len(cancelRequested) == 0 {
+		return false, ErrJobLeaseLost
+	}
+	return cancelRequested[0], nil
+}
+
+// Release saves the outcome of a claimed job and gives up its lease
+func (r *jobRepository) Release(ctx context.Context, job *entity.Job) error {
+	updates := map[string]interface{}{
+		"status":         job.Status,
+		"attempts":       job.Attempts,
+		"run_at":         job.RunAt,
+		"progress_done":  job.ProgressDone,
+		"progress_total": job.ProgressTotal,
+		"last_error":     job.LastError,
+		"finished_at":    job.FinishedAt,
+		"locked_by":      "",
+		"locked_until":   nil,
+	}
+	if job.Result != nil {
+		updates["result"] = job.Result
+	}
+
+	result := conn(ctx, r.db).Model(&entity.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", job.ID, entity.JobRunning, job.LockedBy).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLeaseLost
+	}
+	return nil
+}
+
+// RequestCancel cancels a queued job at once and flags a running one for its
+// worker to stop. A finished job is returned unchanged.
+func (r *jobRepository) RequestCancel(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	now := time.Now()
+	var jobs []entity.Job
+	err := conn(ctx, r.db).Raw(`
+UPDATE jobs SET
+	cancel_requested = true,
+	status = CASE WHEN status = 'queued' THEN 'cancelled' ELSE status END,
+	finished_at = CASE WHEN status = 'queued' THEN ? ELSE finished_at END,
+	updated_at = ?
+WHERE id = ? AND status IN ('queued', 'running')
+RETURNING *`, now, now, id).Scan(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return r.FindByID(ctx, id)
+	}
+	return &jobs[0], nil
+}
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobLeaseLost means a worker's lease on a job expired and the job was
+	// claimed again or cancelled; the worker must drop its outcome
+	ErrJobLeaseLost = errors.New("job lease lost")
+)
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *entity.AuditLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) FindAll(ctx context.Context, filter *dto.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]entity.AuditLog), args.Get(1).(int64), args.Error(2)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"project-simple/internal/domain/dto"
 	"project-simple/internal/domain/entity"
 
@@ -12,35 +13,72 @@ type MockCarRepository struct {
 	mock.Mock
 }
 
-func (m *MockCarRepository) Create(car *entity.Car) error {
-	args := m.Called(car)
+func (m *MockCarRepository) Create(ctx context.Context, car *entity.Car) error {
+	args := m.Called(ctx, car)
 	return args.Error(0)
 }
 
-func (m *MockCarRepository) FindByID(id uuid.UUID) (*entity.Car, error) {
-	args := m.Called(id)
+func (m *MockCarRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Car, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Car), args.Error(1)
 }
 
-func (m *MockCarRepository) FindAll(pagination *dto.PaginationRequest) ([]entity.Car, int64, error) {
-	args := m.Called(pagination)
+func (m *MockCarRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*entity.Car, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Car), args.Error(1)
+}
+
+func (m *MockCarRepository) FindAll(ctx context.Context, pagination *dto.PaginationRequest) ([]entity.Car, int64, error) {
+	args := m.Called(ctx, pagination)
 	return args.Get(0).([]entity.Car), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockCarRepository) Update(car *entity.Car) error {
-	args := m.Called(car)
+// Each passes the cars given to Return to fn, then returns the error
+func (m *MockCarRepository) Each(ctx context.Context, filter *dto.CarExportRequest, fn func(*entity.Car) error) error {
+	args := m.Called(ctx, filter)
+	for _, car := range args.Get(0).([]entity.Car) {
+		if err := fn(&car); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockCarRepository) Update(ctx context.Context, car *entity.Car) error {
+	args := m.Called(ctx, car)
+	return args.Error(0)
+}
+
+func (m *MockCarRepository) Revert(ctx context.Context, car *entity.Car, fromVersion int) error {
+	args := m.Called(ctx, car, fromVersion)
+	return args.Error(0)
+}
+
+func (m *MockCarRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockCarRepository) Delete(id uuid.UUID) error {
-	args := m.Called(id)
+func (m *MockCarRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockCarRepository) ExistsByID(id uuid.UUID) (bool, error) {
-	args := m.Called(id)
+func (m *MockCarRepository) ExistsByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
 	return args.Bool(0), args.Error(1)
 }
+
+func (m *MockCarRepository) CountByEngineVersion(ctx context.Context) (map[string]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
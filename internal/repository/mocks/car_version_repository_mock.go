@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCarVersionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCarVersionRepository) FindByCarID(ctx context.Context, carID uuid.UUID) ([]entity.CarVersion, error) {
+	args := m.Called(ctx, carID)
+	return args.Get(0).([]entity.CarVersion), args.Error(1)
+}
+
+func (m *MockCarVersionRepository) FindVersion(ctx context.Context, carID uuid.UUID, version int) (*entity.CarVersion, error) {
+	args := m.Called(ctx, carID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CarVersion), args.Error(1)
+}
+
+func (m *MockCarVersionRepository) FindAsOf(ctx context.Context, carID uuid.UUID, asOf time.Time) (*entity.CarVersion, error) {
+	args := m.Called(ctx, carID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CarVersion), args.Error(1)
+}
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRepository) Create(ctx context.Context, job *entity.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Claim(ctx context.Context, worker string, lease time.Duration) (*entity.Job, error) {
+	args := m.Called(ctx, worker, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Heartbeat(ctx context.Context, job *entity.Job, lease time.Duration) (bool, error) {
+	args := m.Called(ctx, job, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockJobRepository) Release(ctx context.Context, job *entity.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) RequestCancel(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Job), args.Error(1)
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"context"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Create(ctx context.Context, msg *entity.OutboxMessage) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOutboxRepository) FindPending(ctx context.Context, limit int) ([]entity.OutboxMessage, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]entity.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkDeadLettered(ctx context.Context, id uuid.UUID, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Notify(ctx context.Context, channel, payload string) error {
+	args := m.Called(ctx, channel, payload)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) OldestPendingAt(ctx context.Context) (*time.Time, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockOutboxRepository) CountDeadLettered(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,12 @@
+package mocks
+
+import (
+	"context"
+)
+
+// MockTransactor runs the function directly without opening a transaction
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
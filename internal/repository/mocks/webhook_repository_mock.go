@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"context"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindSubscriptionByID(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindAllSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindSubscriptionsForEvent(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	args := m.Called(ctx, eventType)
+	return args.Get(0).([]entity.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]entity.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, leaseUntil)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) SaveDeliveryAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+	args := m.Called(ctx, delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, filter *dto.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	args := m.Called(ctx, subscriptionID, filter)
+	return args.Get(0).([]entity.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWebhookRepository) FindDeliveryByID(ctx context.Context, subscriptionID, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]entity.WebhookDeliveryAttempt, error) {
+	args := m.Called(ctx, deliveryID)
+	return args.Get(0).([]entity.WebhookDeliveryAttempt), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
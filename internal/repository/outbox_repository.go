@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// outboxRelayLockKey identifies the advisory lock that keeps a single relay
+// draining the outbox at a time, which preserves per-aggregate ordering
+const outboxRelayLockKey = 7_301_028
+
+type OutboxRepository interface {
+	Create(ctx context.Context, msg *entity.OutboxMessage) error
+	TryLockRelay(ctx context.Context) (bool, error)
+	FindPending(ctx context.Context, limit int) ([]entity.OutboxMessage, error)
+	MarkPublished(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error
+	MarkDeadLettered(ctx context.Context, id uuid.UUID, reason string) error
+	Notify(ctx context.Context, channel, payload string) error
+	OldestPendingAt(ctx context.Context) (*time.Time, error)
+	CountDeadLettered(ctx context.Context) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, msg *entity.OutboxMessage) error {
+	return conn(ctx, r.db).Create(msg).Error
+}
+
+// TryLockRelay takes a transaction-scoped advisory lock. It must be called
+// inside a transaction; the lock is released when the transaction ends.
+func (r *outboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	var locked bool
+	err := conn(ctx, r.db).Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLockKey).Scan(&locked).Error
+	return locked, err
+}
+
+// FindPending returns the events that can be delivered now, in sequence
+// order. Events waiting for a retry are left out, and so are the later events
+// of their aggregate, so a failing aggregate cannot fill the batch and hold
+// up the others.
+func (r *outboxRepository) FindPending(ctx context.Context, limit int) ([]entity.OutboxMessage, error) {
+	now := time.Now()
+	var msgs []entity.OutboxMessage
+	err := conn(ctx, r.db).
+		Where("published_at IS NULL AND dead_lettered_at IS NULL").
+		Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_messages earlier
+			WHERE earlier.aggregate_id = outbox_messages.aggregate_id
+				AND earlier.sequence < outbox_messages.sequence
+				AND earlier.published_at IS NULL
+				AND earlier.dead_lettered_at IS NULL
+				AND earlier.next_attempt_at > ?
+		)`, now).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&msgs).Error
+	return msgs, err
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	return conn(ctx, r.db).Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      reason,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// MarkDeadLettered records the last failed attempt of an event the relay
+// gives up on
+func (r *outboxRepository) MarkDeadLettered(ctx context.Context, id uuid.UUID, reason string) error {
+	return conn(ctx, r.db).Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_error":       reason,
+			"dead_lettered_at": time.Now(),
+		}).Error
+}
+
+// Notify sends a NOTIFY on channel. Inside a transaction the notification is
+// only delivered to listeners once the transaction commits.
+func (r *outboxRepository) Notify(ctx context.Context, channel, payload string) error {
+	return conn(ctx, r.db).Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// OldestPendingAt returns when the oldest unpublished event was recorded, or
+// nil when the outbox is drained. Dead-lettered events are not pending.
+func (r *outboxRepository) OldestPendingAt(ctx context.Context) (*time.Time, error) {
+	var oldest *time.Time
+	err := conn(ctx, r.db).Model(&entity.OutboxMessage{}).
+		Where("published_at IS NULL AND dead_lettered_at IS NULL").
+		Select("MIN(occurred_at)").
+		Scan(&oldest).Error
+	return oldest, err
+}
+
+// CountDeadLettered returns how many events the relay gave up on
+func (r *outboxRepository) CountDeadLettered(ctx context.Context) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&entity.OutboxMessage{}).
+		Where("dead_lettered_at IS NOT NULL").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB returns a DB that builds statements without a server and
+// reports the SQL of each query
+func newDryRunDB(t *testing.T, sql *string) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		*sql = strings.Join(strings.Fields(tx.Statement.SQL.String()), " ")
+	}))
+	return db
+}
+
+func TestOutboxRepository_FindPending(t *testing.T) {
+	t.Run("Should not let an aggregate waiting for a retry fill the batch", func(t *testing.T) {
+		var sql string
+		repo := NewOutboxRepository(newDryRunDB(t, &sql))
+
+		_, err := repo.FindPending(context.Background(), 100)
+
+		require.NoError(t, err)
+		assert.Contains(t, sql, "(next_attempt_at IS NULL OR next_attempt_at <= $1)")
+		assert.Contains(t, sql, "NOT EXISTS ( SELECT 1 FROM outbox_messages earlier WHERE earlier.aggregate_id = outbox_messages.aggregate_id AND earlier.sequence < outbox_messages.sequence")
+		assert.Contains(t, sql, "AND earlier.next_attempt_at > $2 )")
+		assert.True(t, strings.HasSuffix(sql, "ORDER BY sequence ASC LIMIT $3"), sql)
+	})
+}
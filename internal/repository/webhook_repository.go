@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error
+	FindSubscriptionByID(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error)
+	FindAllSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	FindSubscriptionsForEvent(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	EnqueueDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]entity.WebhookDelivery, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error
+	FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, filter *dto.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error)
+	FindDeliveryByID(ctx context.Context, subscriptionID, id uuid.UUID) (*entity.WebhookDelivery, error)
+	FindDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]entity.WebhookDeliveryAttempt, error)
+	RequeueDelivery(ctx context.Context, id uuid.UUID) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	return conn(ctx, r.db).Create(sub).Error
+}
+
+func (r *webhookRepository) FindSubscriptionByID(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error) {
+	var sub entity.WebhookSubscription
+	err := conn(ctx, r.db).Where("id = ?", id).First(&sub).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *webhookRepository) FindAllSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	var subs []entity.WebhookSubscription
+	err := conn(ctx, r.db).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) FindSubscriptionsForEvent(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	match, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []entity.WebhookSubscription
+	err = conn(ctx, r.db).
+		Where("active = ?", true).
+		Where("event_types @> ?::jsonb OR event_types @> '[\"*\"]'::jsonb", string(match)).
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	result := conn(ctx, r.db).Model(&entity.WebhookSubscription{}).
+		Where("id = ?", sub.ID).
+		Updates(map[string]interface{}{
+			"url":         sub.URL,
+			"event_types": sub.EventTypes,
+			"active":      sub.Active,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&entity.WebhookSubscription{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDelivery ignores duplicates of the same event for the same
+// subscription, which the at-least-once outbox relay may produce
+func (r *webhookRepository) EnqueueDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(delivery).Error
+}
+
+// ClaimDueDeliveries leases pending deliveries whose next attempt is due by
+// moving that attempt to leaseUntil, so other workers skip them until the
+// lease runs out. Deliveries of paused subscriptions wait until they are
+// resumed. Call it in a short transaction of its own: rows locked by another
+// worker are skipped, and a delivery whose worker dies before saving an
+// attempt becomes due again when its lease expires.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]entity.WebhookDelivery, error) {
+	db := conn(ctx, r.db)
+
+	var deliveries []entity.WebhookDelivery
+	err := db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", entity.WebhookDeliveryPending, time.Now()).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM webhook_subscriptions paused
+			WHERE paused.id = webhook_deliveries.subscription_id
+				AND paused.active = false
+				AND paused.deleted_at IS NULL
+		)`).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	ids := make([]uuid.UUID, len(deliveries))
+	for i := range deliveries {
+		ids[i] = deliveries[i].ID
+		deliveries[i].NextAttemptAt = leaseUntil
+	}
+
+	err = db.Model(&entity.WebhookDelivery{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", leaseUntil).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) SaveDeliveryAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+	db := conn(ctx, r.db)
+
+	if err := db.Create(attempt).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&entity.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+		}).Error
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, filter *dto.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	var deliveries []entity.WebhookDelivery
+	var total int64
+
+	query := conn(ctx, r.db).Model(&entity.WebhookDelivery{}).
+		Where("subscription_id = ?", subscriptionID)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Limit(filter.PageSize).
+		Offset(filter.GetOffset()).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *webhookRepository) FindDeliveryByID(ctx context.Context, subscriptionID, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := conn(ctx, r.db).
+		Where("id = ? AND subscription_id = ?", id, subscriptionID).
+		First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]entity.WebhookDeliveryAttempt, error) {
+	var attempts []entity.WebhookDeliveryAttempt
+	err := conn(ctx, r.db).
+		Where("delivery_id = ?", deliveryID).
+		Order("attempt ASC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+// RequeueDelivery makes a delivery due immediately. Attempts keep counting,
+// so a requeued delivery that has used its budget gets exactly one more try.
+func (r *webhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	result := conn(ctx, r.db).Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          entity.WebhookDeliveryPending,
+			"next_attempt_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
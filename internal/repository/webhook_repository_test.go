@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository_ClaimDueDeliveries(t *testing.T) {
+	t.Run("Should leave deliveries of paused subscriptions alone", func(t *testing.T) {
+		var sql string
+		repo := NewWebhookRepository(newDryRunDB(t, &sql))
+
+		_, err := repo.ClaimDueDeliveries(context.Background(), 10, time.Now().Add(time.Minute))
+
+		require.NoError(t, err)
+		assert.Contains(t, sql, "NOT EXISTS ( SELECT 1 FROM webhook_subscriptions paused WHERE paused.id = webhook_deliveries.subscription_id AND paused.active = false AND paused.deleted_at IS NULL )")
+		assert.Contains(t, sql, "FOR UPDATE SKIP LOCKED")
+	})
+}
@@ -0,0 +1,77 @@
+package requestctx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxRequestIDLength and MaxActorLength are the widths of the audit log
+	// columns the request ID and actor are written to
+	MaxRequestIDLength = 64
+	MaxActorLength     = 255
+)
+
+// Metadata carries request-scoped information that must reach the service
+// layer, such as who performed an action and from where
+type Metadata struct {
+	RequestID string
+	Actor     string
+	ClientIP  string
+	// Authenticated is set once the caller has presented valid credentials.
+	// Actor is only trusted to tell callers apart, as rate limits do, when
+	// it is; otherwise it is whatever the client claims.
+	Authenticated bool
+}
+
+// ValidRequestID reports whether a request ID supplied by the client can be
+// kept: 1 to MaxRequestIDLength printable ASCII characters, without spaces.
+// Other IDs are replaced, as they would not fit the audit log.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Actor makes an actor supplied by the client fit the audit log: invalid
+// UTF-8 and non-printable characters are dropped, and the rest is cut to
+// MaxActorLength characters
+func Actor(actor string) string {
+	actor = strings.Map(func(r rune) rune {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, actor)
+	if runes := []rune(actor); len(runes) > MaxActorLength {
+		actor = string(runes[:MaxActorLength])
+	}
+	return actor
+}
+
+type metadataKey struct{}
+
+// WithMetadata returns a copy of ctx carrying the given metadata
+func WithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// FromContext returns the metadata stored in ctx, or an empty value if none
+func FromContext(ctx context.Context) Metadata {
+	if md, ok := ctx.Value(metadataKey{}).(Metadata); ok {
+		return md
+	}
+	return Metadata{}
+}
+
+// ErrServerShutdown is the cancellation cause of requests still running when
+// the server's graceful shutdown period runs out
+var ErrServerShutdown = errors.New("server is shutting down")
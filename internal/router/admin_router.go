@@ -0,0 +1,38 @@
+package router
+
+import (
+	"project-simple/internal/config"
+	"project-simple/internal/handler"
+	"project-simple/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAdminRouter serves profiling, runtime and configuration endpoints. It
+// listens on its own address, which must never be exposed with the public API,
+// and requires the admin token when one is configured.
+func SetupAdminRouter(cfg *config.Config, adminHandler *handler.AdminHandler) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.Recovery())
+	router.Use(middleware.AdminAuth(cfg.Admin.Token))
+
+	admin := router.Group("/admin")
+	{
+		admin.GET("/queries", adminHandler.GetQueryStats)
+		admin.DELETE("/queries", adminHandler.ResetQueryStats)
+	}
+
+	debug := router.Group("/debug")
+	{
+		debug.GET("/pprof/*profile", adminHandler.Pprof)
+		debug.POST("/pprof/symbol", adminHandler.Pprof)
+		debug.GET("/goroutines", adminHandler.Goroutines)
+		debug.GET("/runtime", adminHandler.GetRuntimeStats)
+		debug.GET("/config", adminHandler.GetConfig)
+		debug.GET("/build", adminHandler.GetBuildInfo)
+		debug.GET("/log-level", adminHandler.GetLogLevel)
+		debug.PUT("/log-level", adminHandler.SetLogLevel)
+	}
+
+	return router
+}
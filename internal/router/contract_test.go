@@ -0,0 +1,469 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"project-simple/internal/config"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/graphql"
+	"project-simple/internal/handler"
+	"project-simple/internal/health"
+	"project-simple/internal/middleware"
+	"project-simple/internal/openapi"
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/service"
+	"project-simple/internal/stream"
+	"project-simple/internal/validation"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// missing is the ID the contract services know nothing about
+var missing = uuid.MustParse("00000000-0000-0000-0000-00000000dead")
+
+const contractTime = "2024-01-01T10:00:00Z"
+
+type contractCars struct {
+	service.CarService
+}
+
+func (contractCars) car(id uuid.UUID) (*dto.CarResponse, error) {
+	if id == missing {
+		return nil, service.ErrCarNotFound
+	}
+	return &dto.CarResponse{ID: id, Name: "Honda Civic", EngineVersion: "2.0", CreatedAt: contractTime, UpdatedAt: contractTime}, nil
+}
+
+func (s contractCars) version(id uuid.UUID, version int) (*dto.CarVersionResponse, error) {
+	if _, err := s.car(id); err != nil {
+		return nil, err
+	}
+	return &dto.CarVersionResponse{CarID: id, Version: version, Operation: "update", Name: "Honda Civic", EngineVersion: "2.0", RecordedAt: contractTime}, nil
+}
+
+func (s contractCars) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	return s.car(uuid.New())
+}
+
+func (s contractCars) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	return s.car(id)
+}
+
+func (s contractCars) GetCarAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*dto.CarResponse, error) {
+	return s.car(id)
+}
+
+func (s contractCars) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	pagination.SetDefaults()
+	car, _ := s.car(uuid.New())
+	return &dto.PaginatedResponse{
+		Data:       []dto.CarResponse{*car},
+		Pagination: dto.PaginationMeta{CurrentPage: pagination.Page, PageSize: pagination.PageSize, TotalPages: 1, TotalRecords: 1},
+	}, nil
+}
+
+func (s contractCars) ExportCars(ctx context.Context, filter *dto.CarExportRequest, fn func(*dto.CarResponse) error) error {
+	car, _ := s.car(uuid.New())
+	return fn(car)
+}
+
+func (s contractCars) ImportCars(ctx context.Context, req *dto.ImportCarsRequest) (*dto.ImportCarsResponse, error) {
+	report := &dto.ImportCarsResponse{DryRun: req.DryRun, OnConflict: req.OnConflict, Total: len(req.Rows), Created: len(req.Rows)}
+	for i := range req.Rows {
+		id := uuid.New()
+		report.Rows = append(report.Rows, dto.ImportRowResult{Row: i + 2, Status: dto.ImportStatusCreated, ID: &id})
+	}
+	return report, nil
+}
+
+func (s contractCars) UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
+	return s.car(id)
+}
+
+func (s contractCars) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	_, err := s.car(id)
+	return err
+}
+
+func (s contractCars) RestoreCar(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	return s.car(id)
+}
+
+func (s contractCars) ListCarVersions(ctx context.Context, id uuid.UUID) ([]dto.CarVersionResponse, error) {
+	version, err := s.version(id, 1)
+	if err != nil {
+		return nil, err
+	}
+	return []dto.CarVersionResponse{*version}, nil
+}
+
+func (s contractCars) GetCarVersion(ctx context.Context, id uuid.UUID, version int) (*dto.CarVersionResponse, error) {
+	return s.version(id, version)
+}
+
+func (s contractCars) RevertCar(ctx context.Context, id uuid.UUID, version int) (*dto.CarResponse, error) {
+	return s.car(id)
+}
+
+type contractAudit struct{}
+
+func (contractAudit) ListAuditLogs(ctx context.Context, filter *dto.AuditLogFilter) (*dto.AuditLogListResponse, error) {
+	filter.SetDefaults()
+	return &dto.AuditLogListResponse{
+		Data: []dto.AuditLogResponse{{
+			ID: uuid.New(), Actor: "jane.doe", Action: "update", EntityType: "car", EntityID: uuid.New(),
+			Changes: json.RawMessage(`{"name":{"old":"Civic","new":"Honda Civic"}}`), CreatedAt: contractTime,
+		}},
+		Pagination: dto.PaginationMeta{CurrentPage: filter.Page, PageSize: filter.PageSize, TotalPages: 1, TotalRecords: 1},
+	}, nil
+}
+
+type contractWebhooks struct{}
+
+func (contractWebhooks) webhook(id uuid.UUID) (*dto.WebhookResponse, error) {
+	if id == missing {
+		return nil, service.ErrWebhookNotFound
+	}
+	return &dto.WebhookResponse{ID: id, URL: "https://partner.example.com/hooks/cars", EventTypes: []string{"car.created"}, Active: true, CreatedAt: contractTime, UpdatedAt: contractTime}, nil
+}
+
+func (s contractWebhooks) delivery(id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	if _, err := s.webhook(id); err != nil {
+		return nil, err
+	}
+	if deliveryID == missing {
+		return nil, service.ErrWebhookDeliveryNotFound
+	}
+	return &dto.WebhookDeliveryResponse{
+		ID: deliveryID, SubscriptionID: id, EventID: uuid.New(), EventType: "car.created", Status: "dead", Attempts: 1,
+		NextAttemptAt: contractTime, LastStatusCode: 503, CreatedAt: contractTime,
+		AttemptLog: []dto.WebhookDeliveryAttemptResponse{{Attempt: 1, StatusCode: 503, DurationMs: 120, CreatedAt: contractTime}},
+	}, nil
+}
+
+func (s contractWebhooks) CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	webhook, err := s.webhook(uuid.New())
+	webhook.Secret = "whsec_2f7c1b9e4d3a8f6c5b2e1d0a9f8e7d6c"
+	return webhook, err
+}
+
+func (s contractWebhooks) GetWebhook(ctx context.Context, id uuid.UUID) (*dto.WebhookResponse, error) {
+	return s.webhook(id)
+}
+
+func (s contractWebhooks) ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error) {
+	// No subscriptions at all, which encodes as null
+	return nil, nil
+}
+
+func (s contractWebhooks) UpdateWebhook(ctx context.Context, id uuid.UUID, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	return s.webhook(id)
+}
+
+func (s contractWebhooks) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	_, err := s.webhook(id)
+	return err
+}
+
+func (s contractWebhooks) ListDeliveries(ctx context.Context, id uuid.UUID, filter *dto.WebhookDeliveryFilter) (*dto.WebhookDeliveryListResponse, error) {
+	delivery, err := s.delivery(id, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WebhookDeliveryListResponse{
+		Data:       []dto.WebhookDeliveryResponse{*delivery},
+		Pagination: dto.PaginationMeta{CurrentPage: 1, PageSize: 10, TotalPages: 1, TotalRecords: 1},
+	}, nil
+}
+
+func (s contractWebhooks) GetDelivery(ctx context.Context, id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	return s.delivery(id, deliveryID)
+}
+
+func (s contractWebhooks) ReplayDelivery(ctx context.Context, id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	return s.delivery(id, deliveryID)
+}
+
+// contractJobs knows a running job by any ID but missing, and queues imports
+type contractJobs struct{}
+
+func (contractJobs) job(id uuid.UUID) (*dto.JobResponse, error) {
+	if id == missing {
+		return nil, service.ErrJobNotFound
+	}
+	return &dto.JobResponse{
+		ID: id, Kind: dto.JobKindCarImport, Status: "running", Progress: dto.JobProgress{Done: 1, Total: 4, Percent: 25},
+		Attempts: 1, MaxAttempts: 3, RunAt: contractTime, StartedAt: contractTime, CreatedAt: contractTime,
+	}, nil
+}
+
+func (s contractJobs) EnqueueJob(ctx context.Context, kind string, payload interface{}) (*dto.JobResponse, error) {
+	job, err := s.job(uuid.New())
+	job.Status, job.Attempts, job.StartedAt = "queued", 0, ""
+	return job, err
+}
+
+func (s contractJobs) GetJob(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	return s.job(id)
+}
+
+func (s contractJobs) GetJobResult(ctx context.Context, id uuid.UUID) (json.RawMessage, error) {
+	if _, err := s.job(id); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(`{"total":1,"created":1}`), nil
+}
+
+func (s contractJobs) CancelJob(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	job, err := s.job(id)
+	if err != nil {
+		return nil, err
+	}
+	job.CancelRequested = true
+	return job, nil
+}
+
+// newContractAPI builds the real router over the contract services, with a
+// database that cannot be reached
+func newContractAPI(t *testing.T, writeLimit int, trustedProxies ...string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	cfg := &config.Config{}
+	cfg.Server.Env = "test"
+	cfg.Server.TrustedProxies = trustedProxies
+	cfg.RateLimit = config.RateLimitConfig{Requests: 1000, Period: time.Minute, WriteRequests: writeLimit, WritePeriod: time.Hour}
+	cfg.Import.MaxBytes = 1 << 10
+
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 connect_timeout=1 sslmode=disable"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	cars := contractCars{}
+	graphqlServer, err := graphql.NewServer(cars, graphql.Config{MaxDepth: 10, MaxComplexity: 1000})
+	require.NoError(t, err)
+
+	return SetupRouter(cfg, ratelimit.NewMemoryStore(),
+		handler.NewCarHandler(cars, contractJobs{}, time.Minute),
+		handler.NewAuditHandler(contractAudit{}),
+		handler.NewWebhookHandler(contractWebhooks{}),
+		handler.NewJobHandler(contractJobs{}),
+		handler.NewGraphQLHandler(graphqlServer, true),
+		handler.NewEventStreamHandler(stream.NewBroker(1), time.Minute),
+		handler.NewHealthHandler(db, health.NewRegistry(time.Second, time.Second)),
+	)
+}
+
+// newContractServer serves api behind a router built from the document,
+// whose only job is to check every response, including those written by
+// middleware, against the operation it matched
+func newContractServer(t *testing.T, spec *openapi3.T, api http.Handler) *httptest.Server {
+	t.Helper()
+
+	contract := gin.New()
+	contract.Use(middleware.OpenAPIValidation(spec, middleware.OpenAPIValidationConfig{
+		Responses: true,
+		OnResponseMismatch: func(c *gin.Context, err error) {
+			t.Errorf("Response does not match the OpenAPI document: %v", err)
+		},
+	}))
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			contract.Handle(method, ginPattern(path), gin.WrapH(api))
+		}
+	}
+
+	server := httptest.NewServer(contract)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func ginPattern(path string) string {
+	return strings.NewReplacer("{", ":", "}", "").Replace(path)
+}
+
+func TestContract_Routes(t *testing.T) {
+	spec := openapi.Spec()
+
+	var routed, documented []string
+	for _, route := range newContractAPI(t, 1000).Routes() {
+		if route.Path != "/swagger/*any" {
+			routed = append(routed, route.Method+" "+route.Path)
+		}
+	}
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+ginPattern(path))
+		}
+	}
+	sort.Strings(routed)
+	sort.Strings(documented)
+
+	assert.Equal(t, routed, documented, "every route must be described by the OpenAPI document, and only routes")
+}
+
+func TestContract_Responses(t *testing.T) {
+	spec := openapi.Spec()
+	server := newContractServer(t, spec, newContractAPI(t, 1000))
+
+	id := uuid.NewString()
+	car := "/api/v1/cars/" + id
+	webhook := "/api/v1/webhooks/" + id
+	delivery := webhook + "/deliveries/" + uuid.NewString()
+	job := "/api/v1/jobs/" + id
+	validCar := `{"name":"Honda Civic","engine_version":"2.0"}`
+
+	type request struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		accept      string
+		body        string
+		status      int
+	}
+	requests := []request{
+		{name: "liveness", method: http.MethodGet, target: "/livez", status: http.StatusOK},
+		{name: "readiness", method: http.MethodGet, target: "/readyz", status: http.StatusOK},
+		{name: "startup", method: http.MethodGet, target: "/startupz", status: http.StatusOK},
+		{name: "document", method: http.MethodGet, target: "/openapi.json", status: http.StatusOK},
+		{name: "health with the database down", method: http.MethodGet, target: "/api/v1/health", status: http.StatusServiceUnavailable},
+
+		{name: "GraphiQL", method: http.MethodGet, target: "/graphql", accept: "text/html", status: http.StatusOK},
+		{name: "GraphQL query", method: http.MethodGet, target: "/graphql?query=" + `{car(id:"` + id + `"){name}}`, status: http.StatusOK},
+		{name: "GraphQL mutation", method: http.MethodPost, target: "/graphql", contentType: "application/json", body: `{"query":"mutation{createCar(input:{name:\"Honda Civic\",engineVersion:\"2.0\"}){id}}"}`, status: http.StatusOK},
+		{name: "GraphQL syntax error", method: http.MethodPost, target: "/graphql", contentType: "application/json", body: `{"query":"{car("}`, status: http.StatusOK},
+		{name: "GraphQL malformed request", method: http.MethodPost, target: "/graphql", contentType: "application/json", body: `{"query":`, status: http.StatusBadRequest},
+		{name: "GraphQL too large", method: http.MethodPost, target: "/graphql", contentType: "application/json", body: `{"query":"` + strings.Repeat(" ", 1<<20) + `"}`, status: http.StatusRequestEntityTooLarge},
+
+		{name: "export as CSV", method: http.MethodGet, target: "/api/v1/cars/export", accept: "text/csv", status: http.StatusOK},
+		{name: "export as NDJSON", method: http.MethodGet, target: "/api/v1/cars/export?format=ndjson", status: http.StatusOK},
+
+		{name: "create", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", body: validCar, status: http.StatusCreated},
+		{name: "create as XML", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", accept: "application/xml", body: validCar, status: http.StatusCreated},
+		{name: "create with invalid fields", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", body: `{"name":"X"}`, status: http.StatusUnprocessableEntity},
+		{name: "create with invalid fields as problem details", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", accept: "application/json, application/problem+json", body: `{"name":"X"}`, status: http.StatusUnprocessableEntity},
+		{name: "create with invalid fields accepting only problem details", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", accept: "application/problem+json", body: `{"name":"X"}`, status: http.StatusUnprocessableEntity},
+		{name: "create with malformed JSON", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", body: `{"name":`, status: http.StatusBadRequest},
+		{name: "create with a body over the size limit", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", body: `{"name":"` + strings.Repeat("x", 1<<20) + `"}`, status: http.StatusBadRequest},
+		{name: "create in an unsupported format", method: http.MethodPost, target: "/api/v1/cars", contentType: "application/json", accept: "application/pdf", body: validCar, status: http.StatusNotAcceptable},
+		{name: "import", method: http.MethodPost, target: "/api/v1/cars/import?dry_run=true", contentType: "text/csv", body: "name,engine_version\nHonda Civic,2.0\n", status: http.StatusOK},
+		{name: "import queued", method: http.MethodPost, target: "/api/v1/cars/import?async=true", contentType: "text/csv", body: "name,engine_version\nHonda Civic,2.0\n", status: http.StatusAccepted},
+		{name: "import too large", method: http.MethodPost, target: "/api/v1/cars/import", contentType: "text/csv", body: "name,engine_version\n" + strings.Repeat("Honda Civic,2.0\n", 100), status: http.StatusRequestEntityTooLarge},
+		{name: "import in an unsupported format", method: http.MethodPost, target: "/api/v1/cars/import", contentType: "application/pdf", body: "%PDF", status: http.StatusUnsupportedMediaType},
+		{name: "list", method: http.MethodGet, target: "/api/v1/cars?page=1&page_size=10&name=civic", status: http.StatusOK},
+		{name: "list as CSV", method: http.MethodGet, target: "/api/v1/cars", accept: "text/csv", status: http.StatusOK},
+		{name: "list with invalid parameters", method: http.MethodGet, target: "/api/v1/cars?page_size=500", status: http.StatusUnprocessableEntity},
+		{name: "get", method: http.MethodGet, target: car, status: http.StatusOK},
+		{name: "get as of", method: http.MethodGet, target: car + "?as_of=" + contractTime, status: http.StatusOK},
+		{name: "get unknown", method: http.MethodGet, target: "/api/v1/cars/" + missing.String(), status: http.StatusNotFound},
+		{name: "get with an invalid ID", method: http.MethodGet, target: "/api/v1/cars/not-a-uuid", status: http.StatusBadRequest},
+		{name: "update", method: http.MethodPut, target: car, contentType: "application/json", body: `{"engine_version":"1.8"}`, status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, target: car, status: http.StatusNoContent},
+		{name: "restore", method: http.MethodPost, target: car + "/restore", status: http.StatusOK},
+		{name: "versions", method: http.MethodGet, target: car + "/versions", status: http.StatusOK},
+		{name: "version", method: http.MethodGet, target: car + "/versions/1", status: http.StatusOK},
+		{name: "revert", method: http.MethodPost, target: car + "/versions/1/revert", status: http.StatusOK},
+
+		{name: "audit log", method: http.MethodGet, target: "/api/v1/audit?actor=jane.doe", status: http.StatusOK},
+
+		{name: "create webhook", method: http.MethodPost, target: "/api/v1/webhooks", contentType: "application/json", body: `{"url":"https://partner.example.com/hooks/cars","event_types":["car.created"]}`, status: http.StatusCreated},
+		{name: "list webhooks", method: http.MethodGet, target: "/api/v1/webhooks", status: http.StatusOK},
+		{name: "get webhook", method: http.MethodGet, target: webhook, status: http.StatusOK},
+		{name: "update webhook", method: http.MethodPut, target: webhook, contentType: "application/json", body: `{"active":false}`, status: http.StatusOK},
+		{name: "delete webhook", method: http.MethodDelete, target: webhook, status: http.StatusNoContent},
+		{name: "list deliveries", method: http.MethodGet, target: webhook + "/deliveries?status=dead", status: http.StatusOK},
+		{name: "get delivery", method: http.MethodGet, target: delivery, status: http.StatusOK},
+		{name: "replay delivery", method: http.MethodPost, target: delivery + "/replay", status: http.StatusAccepted},
+
+		{name: "get job", method: http.MethodGet, target: job, status: http.StatusOK},
+		{name: "get job result", method: http.MethodGet, target: job + "/result", status: http.StatusOK},
+		{name: "cancel job", method: http.MethodPost, target: job + "/cancel", status: http.StatusAccepted},
+		{name: "cancel unknown job", method: http.MethodPost, target: "/api/v1/jobs/" + missing.String() + "/cancel", status: http.StatusNotFound},
+	}
+
+	for _, r := range requests {
+		t.Run("Should match the document - "+r.name, func(t *testing.T) {
+			req, err := http.NewRequest(r.method, server.URL+r.target, strings.NewReader(r.body))
+			require.NoError(t, err)
+			if r.contentType != "" {
+				req.Header.Set("Content-Type", r.contentType)
+			}
+			if r.accept != "" {
+				req.Header.Set("Accept", r.accept)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, r.status, res.StatusCode, string(body))
+			if strings.Contains(res.Header.Get("Content-Type"), "json") {
+				assertSingleJSON(t, body)
+			}
+		})
+	}
+
+	t.Run("Should match the document - event stream", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/cars/events", nil)
+		require.NoError(t, err)
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NotNil(t, spec.Paths.Find("/api/v1/cars/events").Get.Responses.Status(http.StatusOK).Value.Content.Get(res.Header.Get("Content-Type")))
+	})
+}
+
+func TestContract_RateLimit(t *testing.T) {
+	server := newContractServer(t, openapi.Spec(), newContractAPI(t, 1))
+
+	post := func() *http.Response {
+		res, err := http.Post(server.URL+"/api/v1/cars", "application/json", strings.NewReader(`{"name":"Honda Civic","engine_version":"2.0"}`))
+		require.NoError(t, err)
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+
+	t.Run("Should match the document when throttled", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post().StatusCode)
+
+		res := post()
+
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.NotEmpty(t, res.Header.Get("Retry-After"))
+	})
+}
+
+// assertSingleJSON fails when body is not exactly one JSON document, as when
+// a middleware answers after the handler already did
+func assertSingleJSON(t *testing.T, body []byte) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	var v interface{}
+	require.NoError(t, decoder.Decode(&v))
+	_, err := decoder.Token()
+	assert.True(t, errors.Is(err, io.EOF), "more than one JSON document: %s", body)
+}
@@ -1,17 +1,20 @@
 package router
 
 import (
+	"fmt"
+	"net/http"
 	"project-simple/internal/config"
 	"project-simple/internal/handler"
 	"project-simple/internal/middleware"
-	"time"
+	"project-simple/internal/openapi"
+	"project-simple/internal/ratelimit"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(cfg *config.Config, carHandler *handler.CarHandler, healthHandler *handler.HealthHandler) *gin.Engine {
+func SetupRouter(cfg *config.Config, rateLimitStore ratelimit.Store, carHandler *handler.CarHandler, auditHandler *handler.AuditHandler, webhookHandler *handler.WebhookHandler, jobHandler *handler.JobHandler, graphqlHandler *handler.GraphQLHandler, eventStreamHandler *handler.EventStreamHandler, healthHandler *handler.HealthHandler) *gin.Engine {
 	// Set Gin mode based on environment
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -20,20 +23,65 @@ func SetupRouter(cfg *config.Config, carHandler *handler.CarHandler, healthHandl
 	// Create router without default middleware
 	router := gin.New()
 
+	// Take the client IP from forwarding headers only when a trusted proxy
+	// set them, or anyone could pick a fresh rate limit bucket. The entries
+	// are checked by ServerConfig.Validate at startup.
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		panic(fmt.Sprintf("invalid trusted proxies: %v", err))
+	}
+
+	// OpenAPI document describing the routes below
+	spec := openapi.Spec()
+
 	// Apply core middlewares (order matters!)
-	router.Use(middleware.Recovery())           // Recover from panics
-	router.Use(middleware.RequestID())          // Add request ID for tracing
-	router.Use(middleware.Logger())             // Log requests
-	router.Use(middleware.SecurityHeaders())    // Add security headers
+	router.Use(middleware.Metrics())                       // Record request metrics, including recovered panics
+	router.Use(middleware.Recovery())                      // Recover from panics
+	router.Use(middleware.RequestID())                     // Add request ID for tracing
+	router.Use(middleware.Tracing())                       // Continue or start the distributed trace
+	router.Use(middleware.RequestContext())                // Expose request metadata to services
+	router.Use(middleware.Logger())                        // Log requests
+	router.Use(middleware.SecurityHeaders())               // Add security headers
 	router.Use(middleware.CORS(cfg.Server.AllowedOrigins)) // CORS with configured origins
-	router.Use(middleware.RequestSizeLimit(1 << 20))       // Limit request body to 1MB
-	router.Use(middleware.ErrorHandler())       // Handle errors
+	router.Use(middleware.RequestSizeLimit(1<<20,          // Limit request body to 1MB, except for imports
+		middleware.RouteSizeLimit{Method: http.MethodPost, Path: "/api/v1/cars/import", MaxBytes: int64(cfg.Import.MaxBytes)},
+	))
+	router.Use(middleware.ErrorHandler()) // Handle errors
+
+	// Orchestrator probes, registered before rate limiting so they are never throttled
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/startupz", healthHandler.Startupz)
 
-	// Apply rate limiting (100 requests per minute per IP)
-	router.Use(middleware.RateLimit(100, time.Minute))
+	// Apply rate limiting per client IP: REST requests are not authenticated,
+	// so X-Actor cannot tell principals apart
+	router.Use(middleware.RateLimit(rateLimitStore,
+		middleware.RateLimitPolicy{
+			Name:        "writes",
+			Limit:       ratelimit.Limit{Requests: cfg.RateLimit.WriteRequests, Period: cfg.RateLimit.WritePeriod},
+			Methods:     []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			RoutePrefix: "/api/",
+		},
+		middleware.RateLimitPolicy{
+			Name:  "default",
+			Limit: ratelimit.Limit{Requests: cfg.RateLimit.Requests, Period: cfg.RateLimit.Period},
+		},
+	))
 
-	// Swagger documentation
+	// Check requests, and responses in tests, against the OpenAPI document
+	if cfg.OpenAPI.ValidateRequests || cfg.OpenAPI.ValidateResponses {
+		router.Use(middleware.OpenAPIValidation(spec, middleware.OpenAPIValidationConfig{
+			Requests:  cfg.OpenAPI.ValidateRequests,
+			Responses: cfg.OpenAPI.ValidateResponses,
+		}))
+	}
+
+	// Swagger documentation and the OpenAPI 3.1 document
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/openapi.json", handler.NewOpenAPIHandler(spec).Document)
+
+	// GraphQL, which answers in its own format
+	router.GET("/graphql", graphqlHandler.Query)
+	router.POST("/graphql", graphqlHandler.Query)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -41,14 +89,50 @@ func SetupRouter(cfg *config.Config, carHandler *handler.CarHandler, healthHandl
 		// Health check (no rate limit needed)
 		v1.GET("/health", healthHandler.HealthCheck)
 
+		// Server-sent events and exports choose their own media types
+		v1.GET("/cars/events", eventStreamHandler.StreamCarEvents)
+		v1.GET("/cars/export", carHandler.ExportCars)
+
+		// Everything else answers in the format the Accept header asks for
+		api := v1.Group("", middleware.ContentNegotiation())
+
 		// Car routes
-		cars := v1.Group("/cars")
+		cars := api.Group("/cars")
 		{
 			cars.POST("", carHandler.CreateCar)
+			cars.POST("/import", carHandler.ImportCars)
 			cars.GET("", carHandler.GetAllCars)
 			cars.GET("/:id", carHandler.GetCarByID)
 			cars.PUT("/:id", carHandler.UpdateCar)
 			cars.DELETE("/:id", carHandler.DeleteCar)
+			cars.POST("/:id/restore", carHandler.RestoreCar)
+			cars.GET("/:id/versions", carHandler.ListCarVersions)
+			cars.GET("/:id/versions/:version", carHandler.GetCarVersion)
+			cars.POST("/:id/versions/:version/revert", carHandler.RevertCar)
+		}
+
+		// Audit log (read-only)
+		api.GET("/audit", auditHandler.ListAuditLogs)
+
+		// Webhook subscriptions
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.GET("/:id", webhookHandler.GetWebhook)
+			webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.GET("/:id/deliveries/:delivery_id", webhookHandler.GetDelivery)
+			webhooks.POST("/:id/deliveries/:delivery_id/replay", webhookHandler.ReplayDelivery)
+		}
+
+		// Asynchronous jobs, such as imports queued with async=true
+		jobs := api.Group("/jobs")
+		{
+			jobs.GET("/:id", jobHandler.GetJob)
+			jobs.GET("/:id/result", jobHandler.GetJobResult)
+			jobs.POST("/:id/cancel", jobHandler.CancelJob)
 		}
 	}
 
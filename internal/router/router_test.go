@@ -0,0 +1,56 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"project-simple/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupRouter_RateLimit(t *testing.T) {
+	create := func(api http.Handler, remoteAddr string, header http.Header) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/cars", strings.NewReader(`{"name":"Honda Civic","engine_version":"2.0"}`))
+		req.RemoteAddr = remoteAddr
+		req.Header = header
+		req.Header.Set("Content-Type", "application/json")
+		api.ServeHTTP(w, req)
+		return w.Code
+	}
+	as := func(actor string) http.Header {
+		return http.Header{middleware.ActorHeader: {actor}}
+	}
+
+	t.Run("Should count writes per client IP whatever their X-Actor", func(t *testing.T) {
+		api := newContractAPI(t, 1)
+
+		assert.Equal(t, http.StatusCreated, create(api, "192.0.2.1:1234", as("alice")))
+		assert.Equal(t, http.StatusTooManyRequests, create(api, "192.0.2.1:1234", as("bob")))
+		assert.Equal(t, http.StatusCreated, create(api, "192.0.2.2:1234", as("alice")))
+	})
+
+	t.Run("Should ignore X-Forwarded-For from untrusted clients", func(t *testing.T) {
+		api := newContractAPI(t, 1)
+		spoofed := func(ip string) http.Header {
+			return http.Header{"X-Forwarded-For": {ip}}
+		}
+
+		assert.Equal(t, http.StatusCreated, create(api, "192.0.2.1:1234", spoofed("198.51.100.1")))
+		assert.Equal(t, http.StatusTooManyRequests, create(api, "192.0.2.1:1234", spoofed("198.51.100.2")))
+	})
+
+	t.Run("Should take the client IP from trusted proxies", func(t *testing.T) {
+		api := newContractAPI(t, 1, "10.0.0.0/8")
+		forwarded := func(ip string) http.Header {
+			return http.Header{"X-Forwarded-For": {ip}}
+		}
+
+		assert.Equal(t, http.StatusCreated, create(api, "10.0.0.5:1234", forwarded("198.51.100.1")))
+		assert.Equal(t, http.StatusTooManyRequests, create(api, "10.0.0.5:1234", forwarded("198.51.100.1")))
+		assert.Equal(t, http.StatusCreated, create(api, "10.0.0.5:1234", forwarded("198.51.100.2")))
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"project-simple/internal/requestctx"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+const (
+	auditEntityCar = "car"
+
+	// anonymousActor is recorded when a request carries no actor
+	anonymousActor = "anonymous"
+)
+
+type AuditService interface {
+	ListAuditLogs(ctx context.Context, filter *dto.AuditLogFilter) (*dto.AuditLogListResponse, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *auditService) ListAuditLogs(ctx context.Context, filter *dto.AuditLogFilter) (*dto.AuditLogListResponse, error) {
+	filter.SetDefaults()
+
+	entries, total, err := s.auditRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dto.AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		data[i] = dto.AuditLogResponse{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Changes:    entry.Changes,
+			ClientIP:   entry.ClientIP,
+			RequestID:  entry.RequestID,
+			CreatedAt:  entry.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(filter.PageSize)))
+
+	return &dto.AuditLogListResponse{
+		Data: data,
+		Pagination: dto.PaginationMeta{
+			CurrentPage:  filter.Page,
+			PageSize:     filter.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: total,
+		},
+	}, nil
+}
+
+// fieldChange holds the value of a single field before and after a mutation
+type fieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// newAuditLog builds an audit entry for a mutation, filling in the actor,
+// client IP and request ID from the request metadata carried by ctx.
+// before is nil for creations and after is nil for deletions.
+func newAuditLog(ctx context.Context, action, entityType string, entityID uuid.UUID, before, after map[string]interface{}) (*entity.AuditLog, error) {
+	changes, err := json.Marshal(diffFields(before, after))
+	if err != nil {
+		return nil, err
+	}
+
+	md := requestctx.FromContext(ctx)
+	actor := md.Actor
+	if actor == "" {
+		actor = anonymousActor
+	}
+
+	return &entity.AuditLog{
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		ClientIP:   md.ClientIP,
+		RequestID:  md.RequestID,
+	}, nil
+}
+
+// diffFields returns the fields whose value differs between before and after
+func diffFields(before, after map[string]interface{}) map[string]fieldChange {
+	changes := make(map[string]fieldChange)
+
+	for field, oldValue := range before {
+		newValue, ok := after[field]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[field] = fieldChange{Before: oldValue, After: newValue}
+		}
+	}
+	for field, newValue := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = fieldChange{Before: nil, After: newValue}
+		}
+	}
+
+	return changes
+}
+
+// carAuditState returns the audited fields of a car
+func carAuditState(car *entity.Car) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           car.Name,
+		"engine_version": car.EngineVersion,
+	}
+}
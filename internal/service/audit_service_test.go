@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository/mocks"
+	"project-simple/internal/requestctx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewAuditLog(t *testing.T) {
+	t.Run("Success - Records request metadata and changed fields", func(t *testing.T) {
+		ctx := requestctx.WithMetadata(context.Background(), requestctx.Metadata{
+			RequestID: "req-123",
+			Actor:     "jane.doe",
+			ClientIP:  "10.0.0.1",
+		})
+		carID := uuid.New()
+
+		entry, err := newAuditLog(ctx, entity.AuditActionUpdate, auditEntityCar, carID,
+			map[string]interface{}{"name": "Civic", "engine_version": "2.0"},
+			map[string]interface{}{"name": "Civic Sport", "engine_version": "2.0"},
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jane.doe", entry.Actor)
+		assert.Equal(t, "10.0.0.1", entry.ClientIP)
+		assert.Equal(t, "req-123", entry.RequestID)
+		assert.Equal(t, carID, entry.EntityID)
+
+		var changes map[string]fieldChange
+		assert.NoError(t, json.Unmarshal(entry.Changes, &changes))
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "Civic", changes["name"].Before)
+		assert.Equal(t, "Civic Sport", changes["name"].After)
+	})
+
+	t.Run("Success - Creation diff has no before values", func(t *testing.T) {
+		entry, err := newAuditLog(context.Background(), entity.AuditActionCreate, auditEntityCar, uuid.New(),
+			nil,
+			map[string]interface{}{"name": "Civic", "engine_version": "2.0"},
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, anonymousActor, entry.Actor)
+
+		var changes map[string]fieldChange
+		assert.NoError(t, json.Unmarshal(entry.Changes, &changes))
+		assert.Len(t, changes, 2)
+		assert.Nil(t, changes["name"].Before)
+		assert.Equal(t, "2.0", changes["engine_version"].After)
+	})
+}
+
+func TestAuditService_ListAuditLogs(t *testing.T) {
+	t.Run("Success - Get paginated audit logs", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+		service := NewAuditService(mockRepo)
+
+		entries := []entity.AuditLog{
+			{
+				ID:         uuid.New(),
+				Actor:      "jane.doe",
+				Action:     entity.AuditActionCreate,
+				EntityType: auditEntityCar,
+				EntityID:   uuid.New(),
+				Changes:    json.RawMessage(`{}`),
+				CreatedAt:  time.Now(),
+			},
+		}
+
+		mockRepo.On("FindAll", mock.Anything, mock.AnythingOfType("*dto.AuditLogFilter")).Return(entries, int64(11), nil)
+
+		result, err := service.ListAuditLogs(context.Background(), &dto.AuditLogFilter{})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "jane.doe", result.Data[0].Actor)
+		assert.Equal(t, 1, result.Pagination.CurrentPage)
+		assert.Equal(t, 2, result.Pagination.TotalPages)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Repository error", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+		service := NewAuditService(mockRepo)
+
+		expectedError := errors.New("database error")
+		mockRepo.On("FindAll", mock.Anything, mock.AnythingOfType("*dto.AuditLogFilter")).Return([]entity.AuditLog{}, int64(0), expectedError)
+
+		result, err := service.ListAuditLogs(context.Background(), &dto.AuditLogFilter{})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
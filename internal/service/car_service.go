@@ -1,48 +1,70 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"math"
 	"project-simple/internal/domain/dto"
 	"project-simple/internal/domain/entity"
+	"project-simple/internal/domain/event"
 	"project-simple/internal/repository"
+	"project-simple/pkg/apperror"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CarService interface {
-	CreateCar(req *dto.CreateCarRequest) (*dto.CarResponse, error)
-	GetCarByID(id uuid.UUID) (*dto.CarResponse, error)
-	GetAllCars(pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error)
-	UpdateCar(id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error)
-	DeleteCar(id uuid.UUID) error
+	CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error)
+	GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error)
+	GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error)
+	ExportCars(ctx context.Context, filter *dto.CarExportRequest, fn func(*dto.CarResponse) error) error
+	ImportCars(ctx context.Context, req *dto.ImportCarsRequest) (*dto.ImportCarsResponse, error)
+	UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error)
+	DeleteCar(ctx context.Context, id uuid.UUID) error
+	RestoreCar(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error)
+	ListCarVersions(ctx context.Context, id uuid.UUID) ([]dto.CarVersionResponse, error)
+	GetCarVersion(ctx context.Context, id uuid.UUID, version int) (*dto.CarVersionResponse, error)
+	GetCarAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*dto.CarResponse, error)
+	RevertCar(ctx context.Context, id uuid.UUID, version int) (*dto.CarResponse, error)
 }
 
 type carService struct {
-	carRepo repository.CarRepository
+	carRepo     repository.CarRepository
+	versionRepo repository.CarVersionRepository
+	auditRepo   repository.AuditRepository
+	outboxRepo  repository.OutboxRepository
+	transactor  repository.Transactor
 }
 
-func NewCarService(carRepo repository.CarRepository) CarService {
+func NewCarService(carRepo repository.CarRepository, versionRepo repository.CarVersionRepository, auditRepo repository.AuditRepository, outboxRepo repository.OutboxRepository, transactor repository.Transactor) CarService {
 	return &carService{
-		carRepo: carRepo,
+		carRepo:     carRepo,
+		versionRepo: versionRepo,
+		auditRepo:   auditRepo,
+		outboxRepo:  outboxRepo,
+		transactor:  transactor,
 	}
 }
 
-func (s *carService) CreateCar(req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+func (s *carService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
 	car := &entity.Car{
 		Name:          req.Name,
 		EngineVersion: req.EngineVersion,
 	}
 
-	if err := s.carRepo.Create(car); err != nil {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.createCar(ctx, car)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return s.entityToResponse(car), nil
 }
 
-func (s *carService) GetCarByID(id uuid.UUID) (*dto.CarResponse, error) {
-	car, err := s.carRepo.FindByID(id)
+func (s *carService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	car, err := s.carRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrCarNotFound) {
 			return nil, ErrCarNotFound
@@ -53,10 +75,10 @@ func (s *carService) GetCarByID(id uuid.UUID) (*dto.CarResponse, error) {
 	return s.entityToResponse(car), nil
 }
 
-func (s *carService) GetAllCars(pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+func (s *carService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
 	pagination.SetDefaults()
 
-	cars, total, err := s.carRepo.FindAll(pagination)
+	cars, total, err := s.carRepo.FindAll(ctx, pagination)
 	if err != nil {
 		return nil, err
 	}
@@ -79,9 +101,27 @@ func (s *carService) GetAllCars(pagination *dto.PaginationRequest) (*dto.Paginat
 	}, nil
 }
 
-func (s *carService) UpdateCar(id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
-	// Check if car exists
-	car, err := s.carRepo.FindByID(id)
+// ExportCars streams every car matching filter to fn, oldest first. An error
+// from fn stops the export and is returned.
+func (s *carService) ExportCars(ctx context.Context, filter *dto.CarExportRequest, fn func(*dto.CarResponse) error) error {
+	return s.carRepo.Each(ctx, filter, func(car *entity.Car) error {
+		return fn(s.entityToResponse(car))
+	})
+}
+
+func (s *carService) UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
+	var updatedCar *entity.Car
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Check if car exists
+		car, err := s.carRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updatedCar, err = s.updateCar(ctx, car, req.Name, req.EngineVersion)
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrCarNotFound) {
 			return nil, ErrCarNotFound
@@ -89,39 +129,239 @@ func (s *carService) UpdateCar(id uuid.UUID, req *dto.UpdateCarRequest) (*dto.Ca
 		return nil, err
 	}
 
-	// Update only provided fields
-	if req.Name != "" {
-		car.Name = req.Name
-	}
-	if req.EngineVersion != "" {
-		car.EngineVersion = req.EngineVersion
+	return s.entityToResponse(updatedCar), nil
+}
+
+func (s *carService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		car, err := s.carRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.carRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		if err := s.audit(ctx, entity.AuditActionDelete, id, carAuditState(car), nil); err != nil {
+			return err
+		}
+
+		return s.raise(ctx, event.CarDeleted{Car: carEventState(car)})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrCarNotFound) {
+			return ErrCarNotFound
+		}
+		return err
 	}
+	return nil
+}
+
+func (s *carService) RestoreCar(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	var restoredCar *entity.Car
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.carRepo.FindDeletedByID(ctx, id); err != nil {
+			return err
+		}
 
-	if err := s.carRepo.Update(car); err != nil {
+		if err := s.carRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		restoredCar, err = s.carRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.audit(ctx, entity.AuditActionRestore, id, nil, carAuditState(restoredCar)); err != nil {
+			return err
+		}
+
+		return s.raise(ctx, event.CarRestored{Car: carEventState(restoredCar)})
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrCarNotFound) {
 			return nil, ErrCarNotFound
 		}
 		return nil, err
 	}
 
-	// Fetch updated car to get the new UpdatedAt timestamp
-	updatedCar, err := s.carRepo.FindByID(id)
+	return s.entityToResponse(restoredCar), nil
+}
+
+func (s *carService) ListCarVersions(ctx context.Context, id uuid.UUID) ([]dto.CarVersionResponse, error) {
+	versions, err := s.versionRepo.FindByCarID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.entityToResponse(updatedCar), nil
+	if len(versions) == 0 {
+		return nil, ErrCarNotFound
+	}
+
+	responses := make([]dto.CarVersionResponse, len(versions))
+	for i, v := range versions {
+		responses[i] = *s.versionToResponse(&v)
+	}
+
+	return responses, nil
+}
+
+func (s *carService) GetCarVersion(ctx context.Context, id uuid.UUID, version int) (*dto.CarVersionResponse, error) {
+	v, err := s.versionRepo.FindVersion(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrCarVersionNotFound) {
+			return nil, ErrCarVersionNotFound
+		}
+		return nil, err
+	}
+
+	return s.versionToResponse(v), nil
+}
+
+func (s *carService) GetCarAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*dto.CarResponse, error) {
+	v, err := s.versionRepo.FindAsOf(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, repository.ErrCarVersionNotFound) {
+			return nil, ErrCarNotFound
+		}
+		return nil, err
+	}
+
+	// The car did not exist at that point in time
+	if v.Deleted {
+		return nil, ErrCarNotFound
+	}
+
+	return &dto.CarResponse{
+		ID:            v.CarID,
+		Name:          v.Name,
+		EngineVersion: v.EngineVersion,
+		CreatedAt:     v.CarCreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     v.CarUpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
 }
 
-func (s *carService) DeleteCar(id uuid.UUID) error {
-	err := s.carRepo.Delete(id)
+func (s *carService) RevertCar(ctx context.Context, id uuid.UUID, version int) (*dto.CarResponse, error) {
+	var revertedCar *entity.Car
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.versionRepo.FindVersion(ctx, id, version)
+		if err != nil {
+			return err
+		}
+
+		if target.Deleted {
+			return ErrRevertToDeletedVersion
+		}
+
+		car, err := s.carRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := carAuditState(car)
+		beforeEvent := carEventState(car)
+
+		car.Name = target.Name
+		car.EngineVersion = target.EngineVersion
+
+		if err := s.carRepo.Revert(ctx, car, version); err != nil {
+			return err
+		}
+
+		revertedCar, err = s.carRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.audit(ctx, entity.AuditActionRevert, id, before, carAuditState(revertedCar)); err != nil {
+			return err
+		}
+
+		return s.raise(ctx, event.CarUpdated{Before: beforeEvent, After: carEventState(revertedCar)})
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrCarNotFound) {
-			return ErrCarNotFound
+			return nil, ErrCarNotFound
+		}
+		if errors.Is(err, repository.ErrCarVersionNotFound) {
+			return nil, ErrCarVersionNotFound
 		}
+		return nil, err
+	}
+
+	return s.entityToResponse(revertedCar), nil
+}
+
+// createCar inserts car with its audit entry and CarCreated event, within the
+// current transaction
+func (s *carService) createCar(ctx context.Context, car *entity.Car) error {
+	if err := s.carRepo.Create(ctx, car); err != nil {
 		return err
 	}
-	return nil
+
+	if err := s.audit(ctx, entity.AuditActionCreate, car.ID, nil, carAuditState(car)); err != nil {
+		return err
+	}
+
+	return s.raise(ctx, event.CarCreated{Car: carEventState(car)})
+}
+
+// updateCar applies the non-empty fields to car and saves it with its audit
+// entry and CarUpdated event, within the current transaction. It returns the
+// car as stored.
+func (s *carService) updateCar(ctx context.Context, car *entity.Car, name, engineVersion string) (*entity.Car, error) {
+	before := carAuditState(car)
+	beforeEvent := carEventState(car)
+
+	// Update only provided fields
+	if name != "" {
+		car.Name = name
+	}
+	if engineVersion != "" {
+		car.EngineVersion = engineVersion
+	}
+
+	if err := s.carRepo.Update(ctx, car); err != nil {
+		return nil, err
+	}
+
+	// Fetch updated car to get the new UpdatedAt timestamp
+	updatedCar, err := s.carRepo.FindByID(ctx, car.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.audit(ctx, entity.AuditActionUpdate, car.ID, before, carAuditState(updatedCar)); err != nil {
+		return nil, err
+	}
+
+	if err := s.raise(ctx, event.CarUpdated{Before: beforeEvent, After: carEventState(updatedCar)}); err != nil {
+		return nil, err
+	}
+	return updatedCar, nil
+}
+
+// audit appends an audit entry for a car mutation within the current transaction
+func (s *carService) audit(ctx context.Context, action string, id uuid.UUID, before, after map[string]interface{}) error {
+	entry, err := newAuditLog(ctx, action, auditEntityCar, id, before, after)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// raise stores a domain event in the outbox within the current transaction,
+// so it is published if and only if the change commits
+func (s *carService) raise(ctx context.Context, e event.Event) error {
+	msg, err := newOutboxMessage(e)
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.Create(ctx, msg)
 }
 
 func (s *carService) entityToResponse(car *entity.Car) *dto.CarResponse {
@@ -134,6 +374,21 @@ func (s *carService) entityToResponse(car *entity.Car) *dto.CarResponse {
 	}
 }
 
+func (s *carService) versionToResponse(v *entity.CarVersion) *dto.CarVersionResponse {
+	return &dto.CarVersionResponse{
+		CarID:         v.CarID,
+		Version:       v.Version,
+		Operation:     v.Operation,
+		RevertedFrom:  v.RevertedFrom,
+		Name:          v.Name,
+		EngineVersion: v.EngineVersion,
+		Deleted:       v.Deleted,
+		RecordedAt:    v.RecordedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
 var (
-	ErrCarNotFound = errors.New("car not found")
+	ErrCarNotFound            = apperror.New(apperror.CarNotFound, "car not found")
+	ErrCarVersionNotFound     = apperror.New(apperror.CarVersionNotFound, "car version not found")
+	ErrRevertToDeletedVersion = apperror.New(apperror.RevertToDeletedVersion, "cannot revert to a deleted version")
 )
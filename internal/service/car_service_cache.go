@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project-simple/internal/cache"
+	"project-simple/internal/domain/dto"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// carsTag is carried by every cached page; any write invalidates it, since
+// any write can change the content or order of any page
+const carsTag = "cars"
+
+// cachedCarService serves GetCarByID and GetAllCars from an in-process cache,
+// backed by an optional store shared with the other instances. Writes evict
+// the car they touched and all pages, here and, through the store, on every
+// instance.
+type cachedCarService struct {
+	CarService
+	cars   *cache.Cache[dto.CarResponse]
+	pages  *cache.Cache[dto.PaginatedResponse]
+	shared cache.Store
+}
+
+// NewCachedCarService wraps next in a cache of size entries per kind, kept for
+// ttl. shared may be nil for a cache local to this instance.
+func NewCachedCarService(next CarService, size int, ttl time.Duration, shared cache.Store) CarService {
+	s := &cachedCarService{
+		CarService: next,
+		cars:       cache.New[dto.CarResponse]("cars", size, ttl),
+		pages:      cache.New[dto.PaginatedResponse]("car_pages", size, ttl),
+		shared:     shared,
+	}
+	if shared != nil {
+		shared.OnInvalidate(s.invalidateLocal)
+	}
+	return s
+}
+
+func (s *cachedCarService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	car, err := s.cars.Get(ctx, id.String(), func(ctx context.Context) (dto.CarResponse, error) {
+		return cache.ReadThrough(ctx, s.shared, "cars_shared", carTag(id), []string{carTag(id)}, func(ctx context.Context) (dto.CarResponse, error) {
+			res, err := s.CarService.GetCarByID(ctx, id)
+			if err != nil {
+				return dto.CarResponse{}, err
+			}
+			return *res, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &car, nil
+}
+
+func (s *cachedCarService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	pagination.SetDefaults()
+	key := fmt.Sprintf("%s:page:%d:%d:%s:%s:%d:%q:%q", carsTag, pagination.Page, pagination.PageSize, pagination.SortBy, pagination.SortDir,
+		pagination.Offset, pagination.Name, pagination.EngineVersion)
+
+	page, err := s.pages.Get(ctx, key, func(ctx context.Context) (dto.PaginatedResponse, error) {
+		return cache.ReadThrough(ctx, s.shared, "car_pages_shared", key, []string{carsTag}, func(ctx context.Context) (dto.PaginatedResponse, error) {
+			res, err := s.CarService.GetAllCars(ctx, pagination)
+			if err != nil {
+				return dto.PaginatedResponse{}, err
+			}
+			return *res, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers must not be able to modify the cached page
+	page.Data = slices.Clone(page.Data)
+	return &page, nil
+}
+
+func (s *cachedCarService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	defer s.invalidate(ctx, carsTag)
+	return s.CarService.CreateCar(ctx, req)
+}
+
+// ImportCars drops the listings and every car the import updated. A dry run
+// changes nothing, so it leaves the cache alone.
+func (s *cachedCarService) ImportCars(ctx context.Context, req *dto.ImportCarsRequest) (*dto.ImportCarsResponse, error) {
+	res, err := s.CarService.ImportCars(ctx, req)
+	if req.DryRun {
+		return res, err
+	}
+
+	tags := []string{carsTag}
+	if res != nil {
+		for _, row := range res.Rows {
+			if row.Status == dto.ImportStatusUpdated && row.ID != nil {
+				tags = append(tags, carTag(*row.ID))
+			}
+		}
+	}
+	s.invalidate(ctx, tags...)
+	return res, err
+}
+
+func (s *cachedCarService) UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
+	defer s.invalidate(ctx, carTag(id), carsTag)
+	return s.CarService.UpdateCar(ctx, id, req)
+}
+
+func (s *cachedCarService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	defer s.invalidate(ctx, carTag(id), carsTag)
+	return s.CarService.DeleteCar(ctx, id)
+}
+
+func (s *cachedCarService) RestoreCar(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	defer s.invalidate(ctx, carTag(id), carsTag)
+	return s.CarService.RestoreCar(ctx, id)
+}
+
+func (s *cachedCarService) RevertCar(ctx context.Context, id uuid.UUID, version int) (*dto.CarResponse, error) {
+	defer s.invalidate(ctx, carTag(id), carsTag)
+	return s.CarService.RevertCar(ctx, id, version)
+}
+
+// invalidate runs after the write returns, whether it failed or not: a
+// failure reported after commit (e.g. a timeout) may still have changed the
+// row. The shared store goes first, so a local load started in between cannot
+// refill this instance from the entries being invalidated.
+func (s *cachedCarService) invalidate(ctx context.Context, tags ...string) {
+	if s.shared != nil {
+		// Other instances are left to the TTL if this fails
+		_ = s.shared.Invalidate(context.WithoutCancel(ctx), tags...)
+	}
+	s.invalidateLocal(tags)
+}
+
+// invalidateLocal drops the local entries under tags; nil drops everything
+func (s *cachedCarService) invalidateLocal(tags []string) {
+	if tags == nil {
+		s.cars.InvalidateAll()
+		s.pages.InvalidateAll()
+		return
+	}
+
+	for _, tag := range tags {
+		if tag == carsTag {
+			s.pages.InvalidateAll()
+		} else if id, ok := strings.CutPrefix(tag, "car:"); ok {
+			s.cars.Invalidate(id)
+		}
+	}
+}
+
+func carTag(id uuid.UUID) string {
+	return "car:" + id.String()
+}
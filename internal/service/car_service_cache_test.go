@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"project-simple/internal/cache"
+	"project-simple/internal/domain/dto"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCarService keeps one car per id in memory and counts reads
+type fakeCarService struct {
+	CarService
+	mu    sync.Mutex
+	cars  map[uuid.UUID]dto.CarResponse
+	reads atomic.Int32
+	// readDelay widens the window between reading and returning a car
+	readDelay time.Duration
+}
+
+func newFakeCarService() *fakeCarService {
+	return &fakeCarService{cars: make(map[uuid.UUID]dto.CarResponse)}
+}
+
+func (f *fakeCarService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	f.reads.Add(1)
+	f.mu.Lock()
+	car, ok := f.cars[id]
+	f.mu.Unlock()
+	time.Sleep(f.readDelay)
+	if !ok {
+		return nil, ErrCarNotFound
+	}
+	return &car, nil
+}
+
+func (f *fakeCarService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	f.reads.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	page := &dto.PaginatedResponse{Pagination: dto.PaginationMeta{CurrentPage: pagination.Page, PageSize: pagination.PageSize}}
+	for _, car := range f.cars {
+		page.Data = append(page.Data, car)
+	}
+	return page, nil
+}
+
+func (f *fakeCarService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	car := dto.CarResponse{ID: uuid.New(), Name: req.Name, EngineVersion: req.EngineVersion}
+	f.cars[car.ID] = car
+	return &car, nil
+}
+
+func (f *fakeCarService) UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	car, ok := f.cars[id]
+	if !ok {
+		return nil, ErrCarNotFound
+	}
+	car.Name = req.Name
+	f.cars[id] = car
+	return &car, nil
+}
+
+// ImportCars renames the cars whose IDs are given, unless it is a dry run
+func (f *fakeCarService) ImportCars(ctx context.Context, req *dto.ImportCarsRequest) (*dto.ImportCarsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	report := &dto.ImportCarsResponse{DryRun: req.DryRun}
+	for _, row := range req.Rows {
+		id := uuid.MustParse(row.ID)
+		if car, ok := f.cars[id]; ok && !req.DryRun {
+			car.Name = row.Name
+			f.cars[id] = car
+		}
+		report.Add(dto.ImportRowResult{Row: row.Row, Status: dto.ImportStatusUpdated, ID: &id})
+	}
+	return report, nil
+}
+
+func (f *fakeCarService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.cars, id)
+	return nil
+}
+
+func TestCachedCarService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Serves repeated reads from the cache", func(t *testing.T) {
+		fake := newFakeCarService()
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		car, _ := fake.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		for range 3 {
+			res, err := service.GetCarByID(ctx, car.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Honda Civic", res.Name)
+		}
+		for range 3 {
+			_, err := service.GetAllCars(ctx, &dto.PaginationRequest{})
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), fake.reads.Load())
+	})
+
+	t.Run("Should not return stale data after an update", func(t *testing.T) {
+		fake := newFakeCarService()
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		car, _ := service.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		_, err := service.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		page, err := service.GetAllCars(ctx, &dto.PaginationRequest{})
+		require.NoError(t, err)
+		require.Len(t, page.Data, 1)
+
+		_, err = service.UpdateCar(ctx, car.ID, &dto.UpdateCarRequest{Name: "Honda Civic Sport"})
+		require.NoError(t, err)
+
+		res, err := service.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic Sport", res.Name)
+
+		page, err = service.GetAllCars(ctx, &dto.PaginationRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic Sport", page.Data[0].Name)
+	})
+
+	t.Run("Should not return a deleted car or list it", func(t *testing.T) {
+		fake := newFakeCarService()
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		car, _ := service.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		_, _ = service.GetCarByID(ctx, car.ID)
+		_, _ = service.GetAllCars(ctx, &dto.PaginationRequest{})
+
+		require.NoError(t, service.DeleteCar(ctx, car.ID))
+
+		_, err := service.GetCarByID(ctx, car.ID)
+		assert.ErrorIs(t, err, ErrCarNotFound)
+		page, err := service.GetAllCars(ctx, &dto.PaginationRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Data)
+	})
+
+	t.Run("Should list a created car", func(t *testing.T) {
+		fake := newFakeCarService()
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		_, _ = service.GetAllCars(ctx, &dto.PaginationRequest{})
+
+		_, err := service.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		require.NoError(t, err)
+
+		page, err := service.GetAllCars(ctx, &dto.PaginationRequest{})
+		require.NoError(t, err)
+		assert.Len(t, page.Data, 1)
+	})
+
+	t.Run("Should not return stale data after an import", func(t *testing.T) {
+		fake := newFakeCarService()
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		car, _ := service.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		_, _ = service.GetCarByID(ctx, car.ID)
+		_, _ = service.GetAllCars(ctx, &dto.PaginationRequest{})
+		row := dto.ImportCarRow{ID: car.ID.String(), CreateCarRequest: dto.CreateCarRequest{Name: "Honda Civic Sport", EngineVersion: "2.0"}}
+
+		_, err := service.ImportCars(ctx, &dto.ImportCarsRequest{Rows: []dto.ImportCarRow{row}, DryRun: true})
+		require.NoError(t, err)
+		reads := fake.reads.Load()
+		_, _ = service.GetCarByID(ctx, car.ID)
+		assert.Equal(t, reads, fake.reads.Load(), "a dry run should keep the cache")
+
+		_, err = service.ImportCars(ctx, &dto.ImportCarsRequest{Rows: []dto.ImportCarRow{row}, OnConflict: dto.ImportOnConflictUpdate})
+		require.NoError(t, err)
+
+		res, err := service.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic Sport", res.Name)
+		page, err := service.GetAllCars(ctx, &dto.PaginationRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic Sport", page.Data[0].Name)
+	})
+
+	t.Run("Should not let callers modify cached values", func(t *testing.T) {
+		fake := newFakeCarService()
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		car, _ := service.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		res, _ := service.GetCarByID(ctx, car.ID)
+		res.Name = "changed"
+		page, _ := service.GetAllCars(ctx, &dto.PaginationRequest{})
+		page.Data[0].Name = "changed"
+
+		res, _ = service.GetCarByID(ctx, car.ID)
+		assert.Equal(t, "Honda Civic", res.Name)
+		page, _ = service.GetAllCars(ctx, &dto.PaginationRequest{})
+		assert.Equal(t, "Honda Civic", page.Data[0].Name)
+	})
+
+	t.Run("Should never read an older value after an update returns under concurrency", func(t *testing.T) {
+		fake := newFakeCarService()
+		fake.readDelay = time.Millisecond
+		service := NewCachedCarService(fake, 100, time.Minute, nil)
+		car, _ := service.CreateCar(ctx, &dto.CreateCarRequest{Name: "0", EngineVersion: "2.0"})
+
+		var committed atomic.Int64
+		stop := make(chan struct{})
+		var readers sync.WaitGroup
+		for range 8 {
+			readers.Go(func() {
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					// Anything committed before the read starts must be visible
+					floor := committed.Load()
+					res, err := service.GetCarByID(ctx, car.ID)
+					if !assert.NoError(t, err) {
+						return
+					}
+					seen, _ := strconv.ParseInt(res.Name, 10, 64)
+					if !assert.GreaterOrEqual(t, seen, floor, "stale read") {
+						return
+					}
+				}
+			})
+		}
+
+		for i := int64(1); i <= 200; i++ {
+			_, err := service.UpdateCar(ctx, car.ID, &dto.UpdateCarRequest{Name: strconv.FormatInt(i, 10)})
+			require.NoError(t, err)
+			committed.Store(i)
+		}
+		close(stop)
+		readers.Wait()
+
+		res, err := service.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "200", res.Name)
+	})
+}
+
+func TestCachedCarServiceShared(t *testing.T) {
+	ctx := context.Background()
+
+	newStore := func(t *testing.T, server *miniredis.Miniredis) *cache.RedisStore {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+		t.Cleanup(func() { client.Close() })
+		return cache.NewRedisStore(client, time.Minute, time.Minute)
+	}
+
+	// runStore delivers broadcasts to the instance until the test ends
+	runStore := func(t *testing.T, store *cache.RedisStore) {
+		runCtx, stop := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			store.Run(runCtx)
+			close(done)
+		}()
+		t.Cleanup(func() {
+			stop()
+			<-done
+		})
+	}
+
+	t.Run("Success - Instances share loaded values", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		fake := newFakeCarService()
+		car, _ := fake.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		first := NewCachedCarService(fake, 100, time.Minute, newStore(t, server))
+		second := NewCachedCarService(fake, 100, time.Minute, newStore(t, server))
+
+		_, err := first.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		res, err := second.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Honda Civic", res.Name)
+		assert.Equal(t, int32(1), fake.reads.Load())
+	})
+
+	t.Run("Should not return stale data on another instance after an update", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		fake := newFakeCarService()
+		car, _ := fake.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		writerStore, readerStore := newStore(t, server), newStore(t, server)
+		writer := NewCachedCarService(fake, 100, time.Minute, writerStore)
+		reader := NewCachedCarService(fake, 100, time.Minute, readerStore)
+		runStore(t, readerStore)
+
+		// Fill both levels of the reader
+		_, err := reader.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		_, err = reader.GetAllCars(ctx, &dto.PaginationRequest{})
+		require.NoError(t, err)
+
+		_, err = writer.UpdateCar(ctx, car.ID, &dto.UpdateCarRequest{Name: "Honda Civic Sport"})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			res, err := reader.GetCarByID(ctx, car.ID)
+			return err == nil && res.Name == "Honda Civic Sport"
+		}, time.Second, 10*time.Millisecond)
+		require.Eventually(t, func() bool {
+			page, err := reader.GetAllCars(ctx, &dto.PaginationRequest{})
+			return err == nil && page.Data[0].Name == "Honda Civic Sport"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Should read from the database while Redis is down", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		fake := newFakeCarService()
+		car, _ := fake.CreateCar(ctx, &dto.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		service := NewCachedCarService(fake, 100, time.Minute, newStore(t, server))
+		server.Close()
+
+		res, err := service.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic", res.Name)
+
+		_, err = service.UpdateCar(ctx, car.ID, &dto.UpdateCarRequest{Name: "Honda Civic Sport"})
+		require.NoError(t, err)
+
+		res, err = service.GetCarByID(ctx, car.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic Sport", res.Name)
+	})
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/jobs"
+	"project-simple/internal/logging"
+	"project-simple/internal/repository"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+// importBatchSize is the number of rows committed per transaction on import
+const importBatchSize = 500
+
+// errDryRun rolls back a dry-run batch once its rows have been applied
+var errDryRun = errors.New("dry run")
+
+// ImportCars applies rows in batches of importBatchSize, each in its own
+// transaction, through the same audit and outbox path as CreateCar and
+// UpdateCar. Rows are matched to existing cars by ID; a match is failed,
+// skipped or updated according to req.OnConflict. A row that cannot be
+// applied is reported rather than aborting the import, and a batch that
+// fails in the database is rolled back and its rows reported as failed.
+// A dry run applies each batch and rolls it back, so the report shows what
+// a real run would do. The report covers the rows processed so far even when
+// an error is returned.
+func (s *carService) ImportCars(ctx context.Context, req *dto.ImportCarsRequest) (*dto.ImportCarsResponse, error) {
+	report := &dto.ImportCarsResponse{
+		DryRun:     req.DryRun,
+		OnConflict: req.OnConflict,
+		Rows:       make([]dto.ImportRowResult, 0, len(req.Rows)),
+	}
+	if report.OnConflict == "" {
+		report.OnConflict = dto.ImportOnConflictFail
+	}
+	if req.Progress != nil {
+		req.Progress(0, len(req.Rows))
+	}
+
+	for start := 0; start < len(req.Rows); start += importBatchSize {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		batch := req.Rows[start:min(start+importBatchSize, len(req.Rows))]
+
+		var results []dto.ImportRowResult
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			results = make([]dto.ImportRowResult, 0, len(batch))
+			for i := range batch {
+				result, err := s.importRow(ctx, &batch[i], report.OnConflict)
+				if err != nil {
+					return fmt.Errorf("row %d: %w", batch[i].Row, err)
+				}
+				results = append(results, result)
+			}
+			if req.DryRun {
+				return errDryRun
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errDryRun) {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return report, ctxErr
+			}
+			logging.FromContext(ctx).Error("Car import batch rolled back",
+				"error", err, "first_row", batch[0].Row, "rows", len(batch))
+			for _, row := range batch {
+				report.Add(importFailed(dto.ImportRowResult{Row: row.Row}, "",
+					"not imported: the batch containing this row could not be saved"))
+			}
+			continue
+		}
+
+		for i, result := range results {
+			// Cars created in a dry run were rolled back, so their generated IDs never existed
+			if req.DryRun && result.Status == dto.ImportStatusCreated && (batch[i].ID == "" || batch[i].AssignedID) {
+				result.ID = nil
+			}
+			report.Add(result)
+		}
+		if req.Progress != nil {
+			req.Progress(report.Total, len(req.Rows))
+		}
+	}
+
+	return report, nil
+}
+
+// importRow applies one row within the batch transaction. Problems with the
+// row itself are reported in the result; an error means the batch must be
+// rolled back.
+func (s *carService) importRow(ctx context.Context, row *dto.ImportCarRow, onConflict string) (dto.ImportRowResult, error) {
+	result := dto.ImportRowResult{Row: row.Row}
+	car := &entity.Car{
+		Name:          row.Name,
+		EngineVersion: row.EngineVersion,
+	}
+
+	if row.ID != "" {
+		id, err := uuid.Parse(row.ID)
+		if err != nil {
+			return importFailed(result, "id", "id must be a valid UUID"), nil
+		}
+		result.ID = &id
+
+		existing, err := s.carRepo.FindByID(ctx, id)
+		if err == nil && row.AssignedID {
+			result.Status = dto.ImportStatusCreated
+			return result, nil
+		}
+		if err == nil {
+			return s.importConflict(ctx, result, existing, row, onConflict)
+		}
+		if !errors.Is(err, repository.ErrCarNotFound) {
+			return result, err
+		}
+
+		_, err = s.carRepo.FindDeletedByID(ctx, id)
+		if err == nil {
+			return importFailed(result, "id", "a deleted car has this id; restore it before importing"), nil
+		}
+		if !errors.Is(err, repository.ErrCarNotFound) {
+			return result, err
+		}
+		car.ID = id
+	}
+
+	if err := s.createCar(ctx, car); err != nil {
+		return result, err
+	}
+	result.ID = &car.ID
+	result.Status = dto.ImportStatusCreated
+	return result, nil
+}
+
+// importConflict resolves a row whose ID matches an existing car
+func (s *carService) importConflict(ctx context.Context, result dto.ImportRowResult, existing *entity.Car, row *dto.ImportCarRow, onConflict string) (dto.ImportRowResult, error) {
+	switch onConflict {
+	case dto.ImportOnConflictSkip:
+		result.Status = dto.ImportStatusSkipped
+		return result, nil
+	case dto.ImportOnConflictUpdate:
+		if existing.Name == row.Name && existing.EngineVersion == row.EngineVersion {
+			result.Status = dto.ImportStatusSkipped
+			return result, nil
+		}
+		if _, err := s.updateCar(ctx, existing, row.Name, row.EngineVersion); err != nil {
+			return result, err
+		}
+		result.Status = dto.ImportStatusUpdated
+		return result, nil
+	default:
+		return importFailed(result, "id", "a car with this id already exists"), nil
+	}
+}
+
+// CarImportJob returns the runner of dto.JobKindCarImport jobs. It applies an
+// import queued by the request through cars, so the cache is kept coherent,
+// and returns the report. Rows whose IDs were assigned when the job was
+// queued are not created twice when the job is retried.
+func CarImportJob(cars CarService) jobs.Runner {
+	return func(ctx context.Context, payload json.RawMessage, progress jobs.Progress) (interface{}, error) {
+		var job dto.CarImportJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return nil, apperror.New(apperror.InvalidRequest, fmt.Sprintf("invalid import payload: %v", err))
+		}
+
+		report, err := cars.ImportCars(ctx, &dto.ImportCarsRequest{
+			Rows:       job.Rows,
+			OnConflict: job.OnConflict,
+			DryRun:     job.DryRun,
+			Progress:   progress,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		report.Merge(job.Invalid)
+		report.IgnoredColumns = job.IgnoredColumns
+		return report, nil
+	}
+}
+
+func importFailed(result dto.ImportRowResult, field, message string) dto.ImportRowResult {
+	result.Status = dto.ImportStatusFailed
+	result.Errors = []response.ValidationError{{Field: field, Message: message}}
+	return result
+}
@@ -1,38 +1,47 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"project-simple/internal/domain/dto"
 	"project-simple/internal/domain/entity"
+	"project-simple/internal/domain/event"
 	"project-simple/internal/repository"
 	"project-simple/internal/repository/mocks"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCarService_CreateCar(t *testing.T) {
 	t.Run("Success - Create car with valid data", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		req := &dto.CreateCarRequest{
 			Name:          "Honda Civic",
 			EngineVersion: "2.0",
 		}
 
-		mockRepo.On("Create", mock.AnythingOfType("*entity.Car")).Return(nil).Run(func(args mock.Arguments) {
-			car := args.Get(0).(*entity.Car)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(func(args mock.Arguments) {
+			car := args.Get(1).(*entity.Car)
 			car.ID = uuid.New()
 			car.CreatedAt = time.Now()
 			car.UpdatedAt = time.Now()
 		})
+		mockAudit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.MatchedBy(func(msg *entity.OutboxMessage) bool {
+			return msg.EventType == event.TypeCarCreated && msg.AggregateType == event.AggregateCar
+		})).Return(nil)
 
-		result, err := service.CreateCar(req)
+		result, err := service.CreateCar(context.Background(), req)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -40,11 +49,15 @@ func TestCarService_CreateCar(t *testing.T) {
 		assert.Equal(t, req.EngineVersion, result.EngineVersion)
 		assert.NotEqual(t, uuid.Nil, result.ID)
 		mockRepo.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
 	t.Run("Error - Repository create fails", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		req := &dto.CreateCarRequest{
 			Name:          "Honda Civic",
@@ -52,9 +65,9 @@ func TestCarService_CreateCar(t *testing.T) {
 		}
 
 		expectedError := errors.New("database error")
-		mockRepo.On("Create", mock.AnythingOfType("*entity.Car")).Return(expectedError)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(expectedError)
 
-		result, err := service.CreateCar(req)
+		result, err := service.CreateCar(context.Background(), req)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -66,7 +79,9 @@ func TestCarService_CreateCar(t *testing.T) {
 func TestCarService_GetCarByID(t *testing.T) {
 	t.Run("Success - Get existing car", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
 		expectedCar := &entity.Car{
@@ -77,9 +92,9 @@ func TestCarService_GetCarByID(t *testing.T) {
 			UpdatedAt:     time.Now(),
 		}
 
-		mockRepo.On("FindByID", carID).Return(expectedCar, nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(expectedCar, nil)
 
-		result, err := service.GetCarByID(carID)
+		result, err := service.GetCarByID(context.Background(), carID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -91,12 +106,14 @@ func TestCarService_GetCarByID(t *testing.T) {
 
 	t.Run("Error - Car not found", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
-		mockRepo.On("FindByID", carID).Return(nil, repository.ErrCarNotFound)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(nil, repository.ErrCarNotFound)
 
-		result, err := service.GetCarByID(carID)
+		result, err := service.GetCarByID(context.Background(), carID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -106,13 +123,15 @@ func TestCarService_GetCarByID(t *testing.T) {
 
 	t.Run("Error - Repository error", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
 		expectedError := errors.New("database connection error")
-		mockRepo.On("FindByID", carID).Return(nil, expectedError)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(nil, expectedError)
 
-		result, err := service.GetCarByID(carID)
+		result, err := service.GetCarByID(context.Background(), carID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -124,7 +143,9 @@ func TestCarService_GetCarByID(t *testing.T) {
 func TestCarService_GetAllCars(t *testing.T) {
 	t.Run("Success - Get paginated cars", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		pagination := &dto.PaginationRequest{
 			Page:     1,
@@ -148,9 +169,9 @@ func TestCarService_GetAllCars(t *testing.T) {
 			},
 		}
 
-		mockRepo.On("FindAll", mock.AnythingOfType("*dto.PaginationRequest")).Return(expectedCars, int64(25), nil)
+		mockRepo.On("FindAll", mock.Anything, mock.AnythingOfType("*dto.PaginationRequest")).Return(expectedCars, int64(25), nil)
 
-		result, err := service.GetAllCars(pagination)
+		result, err := service.GetAllCars(context.Background(), pagination)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -164,16 +185,18 @@ func TestCarService_GetAllCars(t *testing.T) {
 
 	t.Run("Success - Empty result", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		pagination := &dto.PaginationRequest{
 			Page:     1,
 			PageSize: 10,
 		}
 
-		mockRepo.On("FindAll", mock.AnythingOfType("*dto.PaginationRequest")).Return([]entity.Car{}, int64(0), nil)
+		mockRepo.On("FindAll", mock.Anything, mock.AnythingOfType("*dto.PaginationRequest")).Return([]entity.Car{}, int64(0), nil)
 
-		result, err := service.GetAllCars(pagination)
+		result, err := service.GetAllCars(context.Background(), pagination)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -185,7 +208,9 @@ func TestCarService_GetAllCars(t *testing.T) {
 
 	t.Run("Error - Repository error", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		pagination := &dto.PaginationRequest{
 			Page:     1,
@@ -193,9 +218,9 @@ func TestCarService_GetAllCars(t *testing.T) {
 		}
 
 		expectedError := errors.New("database error")
-		mockRepo.On("FindAll", mock.AnythingOfType("*dto.PaginationRequest")).Return([]entity.Car{}, int64(0), expectedError)
+		mockRepo.On("FindAll", mock.Anything, mock.AnythingOfType("*dto.PaginationRequest")).Return([]entity.Car{}, int64(0), expectedError)
 
-		result, err := service.GetAllCars(pagination)
+		result, err := service.GetAllCars(context.Background(), pagination)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -204,10 +229,179 @@ func TestCarService_GetAllCars(t *testing.T) {
 	})
 }
 
+func TestCarService_ExportCars(t *testing.T) {
+	cars := []entity.Car{
+		{ID: uuid.New(), Name: "Honda Civic", EngineVersion: "2.0", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: uuid.New(), Name: "Toyota Corolla", EngineVersion: "1.8", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	t.Run("Success - Stream every matching car", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+		filter := &dto.CarExportRequest{CarFilter: dto.CarFilter{EngineVersion: "2.0"}}
+		mockRepo.On("Each", mock.Anything, filter).Return(cars, nil)
+
+		var exported []string
+		err := service.ExportCars(context.Background(), filter, func(car *dto.CarResponse) error {
+			exported = append(exported, car.Name)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Honda Civic", "Toyota Corolla"}, exported)
+	})
+
+	t.Run("Should stop when the consumer fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+		mockRepo.On("Each", mock.Anything, mock.Anything).Return(cars, nil)
+		writeErr := errors.New("broken pipe")
+
+		calls := 0
+		err := service.ExportCars(context.Background(), &dto.CarExportRequest{}, func(car *dto.CarResponse) error {
+			calls++
+			return writeErr
+		})
+
+		assert.Equal(t, writeErr, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestCarService_ImportCars(t *testing.T) {
+	newService := func() (CarService, *mocks.MockCarRepository) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		mockAudit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).Return(nil)
+		return NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{}), mockRepo
+	}
+	row := func(line int, id uuid.UUID, name, engineVersion string) dto.ImportCarRow {
+		r := dto.ImportCarRow{CreateCarRequest: dto.CreateCarRequest{Name: name, EngineVersion: engineVersion}, Row: line}
+		if id != uuid.Nil {
+			r.ID = id.String()
+		}
+		return r
+	}
+	assignID := func(args mock.Arguments) {
+		if car := args.Get(1).(*entity.Car); car.ID == uuid.Nil {
+			car.ID = uuid.New()
+		}
+	}
+
+	existingID, unchangedID, deletedID, newID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	expectLookups := func(mockRepo *mocks.MockCarRepository) {
+		mockRepo.On("FindByID", mock.Anything, existingID).Return(&entity.Car{ID: existingID, Name: "Honda Civic", EngineVersion: "2.0"}, nil)
+		mockRepo.On("FindByID", mock.Anything, unchangedID).Return(&entity.Car{ID: unchangedID, Name: "Golf", EngineVersion: "1.4"}, nil)
+		mockRepo.On("FindByID", mock.Anything, deletedID).Return(nil, repository.ErrCarNotFound)
+		mockRepo.On("FindByID", mock.Anything, newID).Return(nil, repository.ErrCarNotFound)
+		mockRepo.On("FindDeletedByID", mock.Anything, deletedID).Return(&entity.Car{ID: deletedID}, nil)
+		mockRepo.On("FindDeletedByID", mock.Anything, newID).Return(nil, repository.ErrCarNotFound)
+	}
+	rows := []dto.ImportCarRow{
+		row(2, uuid.Nil, "Toyota Corolla", "1.8"),
+		row(3, existingID, "Honda Civic Sport", "2.0"),
+		row(4, unchangedID, "Golf", "1.4"),
+		row(5, deletedID, "Fiat Uno", "1.0"),
+		row(6, newID, "Mazda 3", "2.5"),
+	}
+
+	t.Run("Success - Create, update and skip by ID", func(t *testing.T) {
+		service, mockRepo := newService()
+		expectLookups(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(assignID)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.ID == existingID && car.Name == "Honda Civic Sport"
+		})).Return(nil)
+
+		report, err := service.ImportCars(context.Background(), &dto.ImportCarsRequest{Rows: rows, OnConflict: dto.ImportOnConflictUpdate})
+
+		require.NoError(t, err)
+		assert.Equal(t, dto.ImportOnConflictUpdate, report.OnConflict)
+		assert.Equal(t, 5, report.Total)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 1, report.Failed)
+		statuses := make([]string, len(report.Rows))
+		for i, result := range report.Rows {
+			statuses[i] = result.Status
+		}
+		assert.Equal(t, []string{"created", "updated", "skipped", "failed", "created"}, statuses)
+		assert.NotNil(t, report.Rows[0].ID)
+		assert.Equal(t, newID, *report.Rows[4].ID)
+		assert.Contains(t, report.Rows[3].Errors[0].Message, "restore it")
+		mockRepo.AssertNumberOfCalls(t, "Create", 2)
+		mockRepo.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("Should fail rows matching an existing car by default", func(t *testing.T) {
+		service, mockRepo := newService()
+		expectLookups(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(assignID)
+
+		report, err := service.ImportCars(context.Background(), &dto.ImportCarsRequest{Rows: rows[:3]})
+
+		require.NoError(t, err)
+		assert.Equal(t, dto.ImportOnConflictFail, report.OnConflict)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, "id", report.Rows[1].Errors[0].Field)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Skip existing cars", func(t *testing.T) {
+		service, mockRepo := newService()
+		expectLookups(mockRepo)
+
+		report, err := service.ImportCars(context.Background(), &dto.ImportCarsRequest{Rows: rows[1:2], OnConflict: dto.ImportOnConflictSkip})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Skipped)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Dry run hides generated IDs", func(t *testing.T) {
+		service, mockRepo := newService()
+		expectLookups(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(assignID)
+
+		report, err := service.ImportCars(context.Background(), &dto.ImportCarsRequest{Rows: []dto.ImportCarRow{rows[0], rows[4]}, DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Created)
+		assert.Nil(t, report.Rows[0].ID)
+		assert.Equal(t, newID, *report.Rows[1].ID)
+	})
+
+	t.Run("Should fail the whole batch when a row cannot be saved", func(t *testing.T) {
+		service, mockRepo := newService()
+		batch := make([]dto.ImportCarRow, importBatchSize+1)
+		for i := range batch {
+			batch[i] = row(i+2, uuid.Nil, "Toyota Corolla", "1.8")
+		}
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(assignID).Times(10)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(errors.New("database error")).Once()
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil).Run(assignID)
+
+		report, err := service.ImportCars(context.Background(), &dto.ImportCarsRequest{Rows: batch})
+
+		require.NoError(t, err)
+		assert.Equal(t, importBatchSize+1, report.Total)
+		assert.Equal(t, importBatchSize, report.Failed)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, dto.ImportStatusCreated, report.Rows[importBatchSize].Status)
+	})
+}
+
 func TestCarService_UpdateCar(t *testing.T) {
 	t.Run("Success - Update existing car", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
 		existingCar := &entity.Car{
@@ -231,11 +425,13 @@ func TestCarService_UpdateCar(t *testing.T) {
 			UpdatedAt:     time.Now(),
 		}
 
-		mockRepo.On("FindByID", carID).Return(existingCar, nil).Once()
-		mockRepo.On("Update", mock.AnythingOfType("*entity.Car")).Return(nil)
-		mockRepo.On("FindByID", carID).Return(updatedCar, nil).Once()
+		mockRepo.On("FindByID", mock.Anything, carID).Return(existingCar, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(updatedCar, nil).Once()
+		mockAudit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).Return(nil)
 
-		result, err := service.UpdateCar(carID, req)
+		result, err := service.UpdateCar(context.Background(), carID, req)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -247,7 +443,9 @@ func TestCarService_UpdateCar(t *testing.T) {
 
 	t.Run("Success - Partial update (name only)", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
 		existingCar := &entity.Car{
@@ -270,11 +468,13 @@ func TestCarService_UpdateCar(t *testing.T) {
 			UpdatedAt:     time.Now(),
 		}
 
-		mockRepo.On("FindByID", carID).Return(existingCar, nil).Once()
-		mockRepo.On("Update", mock.AnythingOfType("*entity.Car")).Return(nil)
-		mockRepo.On("FindByID", carID).Return(updatedCar, nil).Once()
+		mockRepo.On("FindByID", mock.Anything, carID).Return(existingCar, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(updatedCar, nil).Once()
+		mockAudit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).Return(nil)
 
-		result, err := service.UpdateCar(carID, req)
+		result, err := service.UpdateCar(context.Background(), carID, req)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -285,16 +485,18 @@ func TestCarService_UpdateCar(t *testing.T) {
 
 	t.Run("Error - Car not found", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
 		req := &dto.UpdateCarRequest{
 			Name: "Updated Name",
 		}
 
-		mockRepo.On("FindByID", carID).Return(nil, repository.ErrCarNotFound)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(nil, repository.ErrCarNotFound)
 
-		result, err := service.UpdateCar(carID, req)
+		result, err := service.UpdateCar(context.Background(), carID, req)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -304,7 +506,9 @@ func TestCarService_UpdateCar(t *testing.T) {
 
 	t.Run("Error - Update fails", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
 		existingCar := &entity.Car{
@@ -318,10 +522,10 @@ func TestCarService_UpdateCar(t *testing.T) {
 		}
 
 		expectedError := errors.New("database error")
-		mockRepo.On("FindByID", carID).Return(existingCar, nil)
-		mockRepo.On("Update", mock.AnythingOfType("*entity.Car")).Return(expectedError)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(existingCar, nil)
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Car")).Return(expectedError)
 
-		result, err := service.UpdateCar(carID, req)
+		result, err := service.UpdateCar(context.Background(), carID, req)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -333,25 +537,40 @@ func TestCarService_UpdateCar(t *testing.T) {
 func TestCarService_DeleteCar(t *testing.T) {
 	t.Run("Success - Delete existing car", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
-		mockRepo.On("Delete", carID).Return(nil)
+		existingCar := &entity.Car{
+			ID:            carID,
+			Name:          "Honda Civic",
+			EngineVersion: "2.0",
+		}
 
-		err := service.DeleteCar(carID)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(existingCar, nil)
+		mockRepo.On("Delete", mock.Anything, carID).Return(nil)
+		mockAudit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).Return(nil)
+
+		err := service.DeleteCar(context.Background(), carID)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
 	t.Run("Error - Car not found", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
-		mockRepo.On("Delete", carID).Return(repository.ErrCarNotFound)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(nil, repository.ErrCarNotFound)
 
-		err := service.DeleteCar(carID)
+		err := service.DeleteCar(context.Background(), carID)
 
 		assert.Error(t, err)
 		assert.Equal(t, ErrCarNotFound, err)
@@ -360,16 +579,226 @@ func TestCarService_DeleteCar(t *testing.T) {
 
 	t.Run("Error - Repository error", func(t *testing.T) {
 		mockRepo := new(mocks.MockCarRepository)
-		service := NewCarService(mockRepo)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
 
 		carID := uuid.New()
+		existingCar := &entity.Car{
+			ID:            carID,
+			Name:          "Honda Civic",
+			EngineVersion: "2.0",
+		}
+
 		expectedError := errors.New("database error")
-		mockRepo.On("Delete", carID).Return(expectedError)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(existingCar, nil)
+		mockRepo.On("Delete", mock.Anything, carID).Return(expectedError)
 
-		err := service.DeleteCar(carID)
+		err := service.DeleteCar(context.Background(), carID)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestCarService_RestoreCar(t *testing.T) {
+	t.Run("Success - Restore deleted car", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		restoredCar := &entity.Car{
+			ID:            carID,
+			Name:          "Honda Civic",
+			EngineVersion: "2.0",
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+
+		mockRepo.On("FindDeletedByID", mock.Anything, carID).Return(restoredCar, nil)
+		mockRepo.On("Restore", mock.Anything, carID).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(restoredCar, nil)
+		mockAudit.On("Create", mock.Anything, mock.MatchedBy(func(entry *entity.AuditLog) bool {
+			return entry.Action == entity.AuditActionRestore && entry.EntityID == carID
+		})).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).Return(nil)
+
+		result, err := service.RestoreCar(context.Background(), carID)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, carID, result.ID)
+		mockRepo.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Error - Car not deleted", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		mockRepo.On("FindDeletedByID", mock.Anything, carID).Return(nil, repository.ErrCarNotFound)
+
+		result, err := service.RestoreCar(context.Background(), carID)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, ErrCarNotFound, err)
+		mockRepo.AssertExpectations(t)
+		mockAudit.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Audit write fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, new(mocks.MockCarVersionRepository), mockAudit, mockOutbox, &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		restoredCar := &entity.Car{
+			ID:            carID,
+			Name:          "Honda Civic",
+			EngineVersion: "2.0",
+		}
+
+		expectedError := errors.New("database error")
+		mockRepo.On("FindDeletedByID", mock.Anything, carID).Return(restoredCar, nil)
+		mockRepo.On("Restore", mock.Anything, carID).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(restoredCar, nil)
+		mockAudit.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).Return(expectedError)
+
+		result, err := service.RestoreCar(context.Background(), carID)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, expectedError, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCarService_GetCarAsOf(t *testing.T) {
+	t.Run("Success - Returns snapshot in effect at the given time", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockVersions := new(mocks.MockCarVersionRepository)
+		service := NewCarService(mockRepo, mockVersions, new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		asOf := time.Now().Add(-24 * time.Hour)
+		snapshot := &entity.CarVersion{
+			CarID:         carID,
+			Version:       2,
+			Name:          "Honda Civic",
+			EngineVersion: "1.8",
+			CarCreatedAt:  asOf.Add(-time.Hour),
+			CarUpdatedAt:  asOf.Add(-time.Minute),
+			RecordedAt:    asOf.Add(-time.Minute),
+		}
+
+		mockVersions.On("FindAsOf", mock.Anything, carID, asOf).Return(snapshot, nil)
+
+		result, err := service.GetCarAsOf(context.Background(), carID, asOf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, carID, result.ID)
+		assert.Equal(t, "1.8", result.EngineVersion)
+		mockVersions.AssertExpectations(t)
+	})
+
+	t.Run("Error - Car was deleted at the given time", func(t *testing.T) {
+		mockVersions := new(mocks.MockCarVersionRepository)
+		service := NewCarService(new(mocks.MockCarRepository), mockVersions, new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		asOf := time.Now()
+		mockVersions.On("FindAsOf", mock.Anything, carID, asOf).Return(&entity.CarVersion{CarID: carID, Deleted: true}, nil)
+
+		result, err := service.GetCarAsOf(context.Background(), carID, asOf)
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrCarNotFound, err)
+	})
+
+	t.Run("Error - Car did not exist yet", func(t *testing.T) {
+		mockVersions := new(mocks.MockCarVersionRepository)
+		service := NewCarService(new(mocks.MockCarRepository), mockVersions, new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		asOf := time.Now()
+		mockVersions.On("FindAsOf", mock.Anything, carID, asOf).Return(nil, repository.ErrCarVersionNotFound)
+
+		result, err := service.GetCarAsOf(context.Background(), carID, asOf)
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrCarNotFound, err)
+	})
+}
+
+func TestCarService_RevertCar(t *testing.T) {
+	t.Run("Success - Revert to earlier version", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockVersions := new(mocks.MockCarVersionRepository)
+		mockAudit := new(mocks.MockAuditRepository)
+		mockOutbox := new(mocks.MockOutboxRepository)
+		service := NewCarService(mockRepo, mockVersions, mockAudit, mockOutbox, &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		target := &entity.CarVersion{CarID: carID, Version: 1, Name: "Honda Civic", EngineVersion: "1.8"}
+		current := &entity.Car{ID: carID, Name: "Honda Civic Sport", EngineVersion: "2.0"}
+		reverted := &entity.Car{ID: carID, Name: "Honda Civic", EngineVersion: "1.8"}
+
+		mockVersions.On("FindVersion", mock.Anything, carID, 1).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(current, nil).Once()
+		mockRepo.On("Revert", mock.Anything, mock.MatchedBy(func(car *entity.Car) bool {
+			return car.Name == "Honda Civic" && car.EngineVersion == "1.8"
+		}), 1).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, carID).Return(reverted, nil).Once()
+		mockAudit.On("Create", mock.Anything, mock.MatchedBy(func(entry *entity.AuditLog) bool {
+			return entry.Action == entity.AuditActionRevert
+		})).Return(nil)
+		mockOutbox.On("Create", mock.Anything, mock.AnythingOfType("*entity.OutboxMessage")).Return(nil)
+
+		result, err := service.RevertCar(context.Background(), carID, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Honda Civic", result.Name)
+		assert.Equal(t, "1.8", result.EngineVersion)
+		mockRepo.AssertExpectations(t)
+		mockVersions.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Error - Version not found", func(t *testing.T) {
+		mockVersions := new(mocks.MockCarVersionRepository)
+		service := NewCarService(new(mocks.MockCarRepository), mockVersions, new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		mockVersions.On("FindVersion", mock.Anything, carID, 7).Return(nil, repository.ErrCarVersionNotFound)
+
+		result, err := service.RevertCar(context.Background(), carID, 7)
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrCarVersionNotFound, err)
+	})
+
+	t.Run("Error - Target version is a deletion", func(t *testing.T) {
+		mockRepo := new(mocks.MockCarRepository)
+		mockVersions := new(mocks.MockCarVersionRepository)
+		service := NewCarService(mockRepo, mockVersions, new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{})
+
+		carID := uuid.New()
+		mockVersions.On("FindVersion", mock.Anything, carID, 3).Return(&entity.CarVersion{CarID: carID, Version: 3, Deleted: true}, nil)
+
+		result, err := service.RevertCar(context.Background(), carID, 3)
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrRevertToDeletedVersion, err)
+		mockRepo.AssertNotCalled(t, "Revert", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/tracing"
+	"project-simple/pkg/apperror"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const carIDAttribute = attribute.Key("car.id")
+
+// tracedCarService wraps a CarService in one span per method call
+type tracedCarService struct {
+	next CarService
+}
+
+func NewTracedCarService(next CarService) CarService {
+	return &tracedCarService{next: next}
+}
+
+func (s *tracedCarService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.CreateCar")
+	res, err := s.next.CreateCar(ctx, req)
+	if res != nil {
+		span.SetAttributes(carIDAttribute.String(res.ID.String()))
+	}
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.GetCarByID", carIDAttribute.String(id.String()))
+	res, err := s.next.GetCarByID(ctx, id)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.GetAllCars")
+	res, err := s.next.GetAllCars(ctx, pagination)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) ExportCars(ctx context.Context, filter *dto.CarExportRequest, fn func(*dto.CarResponse) error) error {
+	ctx, span := startSpan(ctx, "CarService.ExportCars")
+	err := s.next.ExportCars(ctx, filter, fn)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCarService) ImportCars(ctx context.Context, req *dto.ImportCarsRequest) (*dto.ImportCarsResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.ImportCars",
+		attribute.Int("import.rows", len(req.Rows)), attribute.Bool("import.dry_run", req.DryRun))
+	res, err := s.next.ImportCars(ctx, req)
+	if res != nil {
+		span.SetAttributes(attribute.Int("import.created", res.Created), attribute.Int("import.updated", res.Updated),
+			attribute.Int("import.failed", res.Failed))
+	}
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.UpdateCar", carIDAttribute.String(id.String()))
+	res, err := s.next.UpdateCar(ctx, id, req)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "CarService.DeleteCar", carIDAttribute.String(id.String()))
+	err := s.next.DeleteCar(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCarService) RestoreCar(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.RestoreCar", carIDAttribute.String(id.String()))
+	res, err := s.next.RestoreCar(ctx, id)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) ListCarVersions(ctx context.Context, id uuid.UUID) ([]dto.CarVersionResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.ListCarVersions", carIDAttribute.String(id.String()))
+	res, err := s.next.ListCarVersions(ctx, id)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) GetCarVersion(ctx context.Context, id uuid.UUID, version int) (*dto.CarVersionResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.GetCarVersion", carIDAttribute.String(id.String()), attribute.Int("car.version", version))
+	res, err := s.next.GetCarVersion(ctx, id, version)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) GetCarAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*dto.CarResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.GetCarAsOf", carIDAttribute.String(id.String()), attribute.String("car.as_of", asOf.Format(time.RFC3339)))
+	res, err := s.next.GetCarAsOf(ctx, id, asOf)
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *tracedCarService) RevertCar(ctx context.Context, id uuid.UUID, version int) (*dto.CarResponse, error) {
+	ctx, span := startSpan(ctx, "CarService.RevertCar", carIDAttribute.String(id.String()), attribute.Int("car.version", version))
+	res, err := s.next.RevertCar(ctx, id, version)
+	endSpan(span, err)
+	return res, err
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span. Errors the API reports as client errors
+// are recorded without marking the span as failed.
+func endSpan(span trace.Span, err error) {
+	defer span.End()
+
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	if !isClientError(err) {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+func isClientError(err error) bool {
+	code, ok := apperror.CodeOf(err)
+	return ok && apperror.Lookup(code).Status < http.StatusInternalServerError
+}
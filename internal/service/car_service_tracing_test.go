@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"project-simple/internal/repository/mocks"
+	"project-simple/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedCarService(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := tracing.NewProvider(exporter, "car-api-test")
+	require.NoError(t, err)
+	otel.SetTracerProvider(provider)
+
+	t.Run("Success - Records a span per call", func(t *testing.T) {
+		exporter.Reset()
+		mockRepo := new(mocks.MockCarRepository)
+		service := NewTracedCarService(NewCarService(mockRepo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{}))
+
+		carID := uuid.New()
+		mockRepo.On("FindByID", mock.Anything, carID).Return(&entity.Car{ID: carID, Name: "Honda Civic", EngineVersion: "2.0"}, nil)
+
+		_, err := service.GetCarByID(context.Background(), carID)
+		require.NoError(t, err)
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "CarService.GetCarByID", spans[0].Name)
+		assert.Contains(t, spans[0].Attributes, carIDAttribute.String(carID.String()))
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	})
+
+	t.Run("Error - Not found is not a span failure", func(t *testing.T) {
+		exporter.Reset()
+		mockRepo := new(mocks.MockCarRepository)
+		service := NewTracedCarService(NewCarService(mockRepo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{}))
+
+		carID := uuid.New()
+		mockRepo.On("FindByID", mock.Anything, carID).Return(nil, repository.ErrCarNotFound)
+
+		_, err := service.GetCarByID(context.Background(), carID)
+		require.ErrorIs(t, err, ErrCarNotFound)
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+		assert.Len(t, spans[0].Events, 1)
+	})
+
+	t.Run("Error - Unexpected errors fail the span", func(t *testing.T) {
+		exporter.Reset()
+		mockRepo := new(mocks.MockCarRepository)
+		service := NewTracedCarService(NewCarService(mockRepo, new(mocks.MockCarVersionRepository), new(mocks.MockAuditRepository), new(mocks.MockOutboxRepository), &mocks.MockTransactor{}))
+
+		carID := uuid.New()
+		mockRepo.On("FindByID", mock.Anything, carID).Return(nil, errors.New("connection reset"))
+
+		_, err := service.GetCarByID(context.Background(), carID)
+		require.Error(t, err)
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+	})
+}
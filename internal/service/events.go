@@ -0,0 +1,33 @@
+package service
+
+import (
+	"encoding/json"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/domain/event"
+	"time"
+)
+
+// newOutboxMessage serialises a domain event for the outbox
+func newOutboxMessage(e event.Event) (*entity.OutboxMessage, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.OutboxMessage{
+		EventType:     e.EventType(),
+		AggregateType: e.AggregateType(),
+		AggregateID:   e.AggregateID(),
+		Payload:       payload,
+		OccurredAt:    time.Now(),
+	}, nil
+}
+
+// carEventState returns the state of a car as carried by car events
+func carEventState(car *entity.Car) event.CarState {
+	return event.CarState{
+		ID:            car.ID,
+		Name:          car.Name,
+		EngineVersion: car.EngineVersion,
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"project-simple/internal/requestctx"
+	"project-simple/pkg/apperror"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobService interface {
+	EnqueueJob(ctx context.Context, kind string, payload interface{}) (*dto.JobResponse, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error)
+	GetJobResult(ctx context.Context, id uuid.UUID) (json.RawMessage, error)
+	CancelJob(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error)
+}
+
+type jobService struct {
+	jobRepo     repository.JobRepository
+	maxAttempts int
+}
+
+func NewJobService(jobRepo repository.JobRepository, maxAttempts int) JobService {
+	return &jobService{
+		jobRepo:     jobRepo,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// EnqueueJob queues a job for the worker pool. The job remembers the request
+// that queued it, so its audit entries name the same actor.
+func (s *jobService) EnqueueJob(ctx context.Context, kind string, payload interface{}) (*dto.JobResponse, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	md := requestctx.FromContext(ctx)
+	job := &entity.Job{
+		Kind:        kind,
+		Status:      entity.JobQueued,
+		Payload:     data,
+		MaxAttempts: s.maxAttempts,
+		RunAt:       time.Now(),
+		RequestID:   md.RequestID,
+		Actor:       md.Actor,
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return s.entityToResponse(job), nil
+}
+
+func (s *jobService) GetJob(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	job, err := s.findJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.entityToResponse(job), nil
+}
+
+// GetJobResult returns the stored result of a succeeded job
+func (s *jobService) GetJobResult(ctx context.Context, id uuid.UUID) (json.RawMessage, error) {
+	job, err := s.findJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != entity.JobSucceeded {
+		return nil, ErrJobResultUnavailable
+	}
+	return job.Result, nil
+}
+
+// CancelJob cancels a queued job at once. A running job is flagged, and its
+// worker stops it at the next heartbeat.
+func (s *jobService) CancelJob(ctx context.Context, id uuid.UUID) (*dto.JobResponse, error) {
+	job, err := s.jobRepo.RequestCancel(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	if job.Finished() && !job.CancelRequested {
+		return nil, ErrJobFinished
+	}
+	return s.entityToResponse(job), nil
+}
+
+func (s *jobService) findJob(ctx context.Context, id uuid.UUID) (*entity.Job, error) {
+	job, err := s.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *jobService) entityToResponse(job *entity.Job) *dto.JobResponse {
+	res := &dto.JobResponse{
+		ID:              job.ID,
+		Kind:            job.Kind,
+		Status:          job.Status,
+		Attempts:        job.Attempts,
+		MaxAttempts:     job.MaxAttempts,
+		CancelRequested: job.CancelRequested,
+		LastError:       job.LastError,
+		RunAt:           job.RunAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:       job.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Progress: dto.JobProgress{
+			Done:  job.ProgressDone,
+			Total: job.ProgressTotal,
+		},
+	}
+	if job.ProgressTotal > 0 {
+		res.Progress.Percent = min(100, job.ProgressDone*100/job.ProgressTotal)
+	}
+	if job.Status == entity.JobSucceeded {
+		res.Progress.Percent = 100
+	}
+	if job.StartedAt != nil {
+		res.StartedAt = job.StartedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if job.FinishedAt != nil {
+		res.FinishedAt = job.FinishedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return res
+}
+
+var (
+	ErrJobNotFound          = apperror.New(apperror.JobNotFound, "job not found")
+	ErrJobFinished          = apperror.New(apperror.JobFinished, "job has already finished")
+	ErrJobResultUnavailable = apperror.New(apperror.JobResultUnavailable, "job has not succeeded")
+)
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"project-simple/internal/repository/mocks"
+	"project-simple/internal/requestctx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJobService_EnqueueJob(t *testing.T) {
+	t.Run("Success - Queues the job for the requesting actor", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		ctx := requestctx.WithMetadata(context.Background(), requestctx.Metadata{RequestID: "req-1", Actor: "alice"})
+
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Kind == "car_import" && job.Status == entity.JobQueued && job.MaxAttempts == 3 &&
+				job.RequestID == "req-1" && job.Actor == "alice" && string(job.Payload) == `{"rows":2}`
+		})).Return(nil)
+
+		result, err := service.EnqueueJob(ctx, "car_import", map[string]int{"rows": 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.JobQueued, result.Status)
+		assert.Equal(t, 3, result.MaxAttempts)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestJobService_GetJob(t *testing.T) {
+	t.Run("Success - Reports progress", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		id := uuid.New()
+		started := time.Now()
+
+		mockRepo.On("FindByID", mock.Anything, id).Return(&entity.Job{
+			ID: id, Status: entity.JobRunning, ProgressDone: 250, ProgressTotal: 1000, StartedAt: &started,
+		}, nil)
+
+		result, err := service.GetJob(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 25, result.Progress.Percent)
+		assert.NotEmpty(t, result.StartedAt)
+		assert.Empty(t, result.FinishedAt)
+	})
+
+	t.Run("Should return not found", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		id := uuid.New()
+
+		mockRepo.On("FindByID", mock.Anything, id).Return(nil, repository.ErrJobNotFound)
+
+		_, err := service.GetJob(context.Background(), id)
+
+		assert.ErrorIs(t, err, ErrJobNotFound)
+	})
+}
+
+func TestJobService_GetJobResult(t *testing.T) {
+	t.Run("Success - Returns the stored result", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		id := uuid.New()
+
+		mockRepo.On("FindByID", mock.Anything, id).Return(&entity.Job{
+			ID: id, Status: entity.JobSucceeded, Result: json.RawMessage(`{"created":2}`),
+		}, nil)
+
+		result, err := service.GetJobResult(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"created":2}`, string(result))
+	})
+
+	t.Run("Should refuse until the job succeeds", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		id := uuid.New()
+
+		mockRepo.On("FindByID", mock.Anything, id).Return(&entity.Job{ID: id, Status: entity.JobRunning}, nil)
+
+		_, err := service.GetJobResult(context.Background(), id)
+
+		assert.ErrorIs(t, err, ErrJobResultUnavailable)
+	})
+}
+
+func TestJobService_CancelJob(t *testing.T) {
+	t.Run("Success - Flags a running job", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		id := uuid.New()
+
+		mockRepo.On("RequestCancel", mock.Anything, id).Return(&entity.Job{
+			ID: id, Status: entity.JobRunning, CancelRequested: true,
+		}, nil)
+
+		result, err := service.CancelJob(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.True(t, result.CancelRequested)
+	})
+
+	t.Run("Should refuse a job that already finished", func(t *testing.T) {
+		mockRepo := new(mocks.MockJobRepository)
+		service := NewJobService(mockRepo, 3)
+		id := uuid.New()
+		finished := time.Now()
+
+		mockRepo.On("RequestCancel", mock.Anything, id).Return(&entity.Job{
+			ID: id, Status: entity.JobSucceeded, FinishedAt: &finished,
+		}, nil)
+
+		_, err := service.CancelJob(context.Background(), id)
+
+		assert.ErrorIs(t, err, ErrJobFinished)
+	})
+}
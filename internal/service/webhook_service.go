@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"project-simple/internal/webhook"
+	"project-simple/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+// webhookSecretPrefix marks generated secrets so they are easy to recognise
+const webhookSecretPrefix = "whsec_"
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*dto.WebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, id uuid.UUID, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, id uuid.UUID, filter *dto.WebhookDeliveryFilter) (*dto.WebhookDeliveryListResponse, error)
+	GetDelivery(ctx context.Context, id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error)
+	ReplayDelivery(ctx context.Context, id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := webhook.CheckURL(req.URL); err != nil {
+		return nil, ErrWebhookURLNotAllowed
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	sub := &entity.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: entity.StringList(req.EventTypes),
+		Secret:     secret,
+		Active:     true,
+	}
+
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	resp := s.subscriptionToResponse(sub)
+	// The secret is only disclosed once, at creation
+	resp.Secret = sub.Secret
+	return resp, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, id uuid.UUID) (*dto.WebhookResponse, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.subscriptionToResponse(sub), nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error) {
+	subs, err := s.webhookRepo.FindAllSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookResponse, len(subs))
+	for i, sub := range subs {
+		responses[i] = *s.subscriptionToResponse(&sub)
+	}
+
+	return responses, nil
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, id uuid.UUID, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update only provided fields
+	if req.URL != "" {
+		if err := webhook.CheckURL(req.URL); err != nil {
+			return nil, ErrWebhookURLNotAllowed
+		}
+		sub.URL = req.URL
+	}
+	if len(req.EventTypes) > 0 {
+		sub.EventTypes = entity.StringList(req.EventTypes)
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, sub); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetWebhook(ctx, id)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	err := s.webhookRepo.DeleteSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, id uuid.UUID, filter *dto.WebhookDeliveryFilter) (*dto.WebhookDeliveryListResponse, error) {
+	if _, err := s.findSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	filter.SetDefaults()
+
+	deliveries, total, err := s.webhookRepo.FindDeliveries(ctx, id, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		data[i] = *s.deliveryToResponse(&delivery)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(filter.PageSize)))
+
+	return &dto.WebhookDeliveryListResponse{
+		Data: data,
+		Pagination: dto.PaginationMeta{
+			CurrentPage:  filter.Page,
+			PageSize:     filter.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: total,
+		},
+	}, nil
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	delivery, err := s.webhookRepo.FindDeliveryByID(ctx, id, deliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	attempts, err := s.webhookRepo.FindDeliveryAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.deliveryToResponse(delivery)
+	resp.AttemptLog = make([]dto.WebhookDeliveryAttemptResponse, len(attempts))
+	for i, a := range attempts {
+		resp.AttemptLog[i] = dto.WebhookDeliveryAttemptResponse{
+			Attempt:    a.Attempt,
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMs: a.DurationMs,
+			CreatedAt:  a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *webhookService) ReplayDelivery(ctx context.Context, id, deliveryID uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	if _, err := s.webhookRepo.FindDeliveryByID(ctx, id, deliveryID); err != nil {
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.webhookRepo.RequeueDelivery(ctx, deliveryID); err != nil {
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetDelivery(ctx, id, deliveryID)
+}
+
+func (s *webhookService) findSubscription(ctx context.Context, id uuid.UUID) (*entity.WebhookSubscription, error) {
+	sub, err := s.webhookRepo.FindSubscriptionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *webhookService) subscriptionToResponse(sub *entity.WebhookSubscription) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Active:     sub.Active,
+		CreatedAt:  sub.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:  sub.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func (s *webhookService) deliveryToResponse(delivery *entity.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	return &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt.Format("2006-01-02T15:04:05Z07:00"),
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+var (
+	ErrWebhookNotFound         = apperror.New(apperror.WebhookNotFound, "webhook subscription not found")
+	ErrWebhookDeliveryNotFound = apperror.New(apperror.WebhookDeliveryNotFound, "webhook delivery not found")
+	ErrWebhookURLNotAllowed    = apperror.New(apperror.WebhookURLNotAllowed, "webhook URL must be a public HTTPS address")
+)
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"project-simple/internal/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	t.Run("Success - Generates a secret when none is given", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		req := &dto.CreateWebhookRequest{
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: []string{"car.created"},
+		}
+
+		mockRepo.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*entity.WebhookSubscription")).Return(nil)
+
+		result, err := service.CreateWebhook(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.Secret, webhookSecretPrefix))
+		assert.True(t, result.Active)
+		assert.Equal(t, []string{"car.created"}, result.EventTypes)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Keeps the provided secret", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		req := &dto.CreateWebhookRequest{
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: []string{"*"},
+			Secret:     "my-very-own-secret-value",
+		}
+
+		mockRepo.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*entity.WebhookSubscription")).Return(nil)
+
+		result, err := service.CreateWebhook(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "my-very-own-secret-value", result.Secret)
+	})
+
+	t.Run("Error - Internal URL", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		for _, url := range []string{"http://partner.example.com/hooks", "https://127.0.0.1:6060/debug/pprof", "https://169.254.169.254/latest/meta-data", "https://10.0.0.5/hooks", "https://localhost/hooks"} {
+			_, err := service.CreateWebhook(context.Background(), &dto.CreateWebhookRequest{URL: url, EventTypes: []string{"*"}})
+
+			assert.ErrorIs(t, err, ErrWebhookURLNotAllowed, url)
+		}
+		mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookService_GetWebhook(t *testing.T) {
+	t.Run("Success - Secret is not disclosed", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		id := uuid.New()
+		mockRepo.On("FindSubscriptionByID", mock.Anything, id).Return(&entity.WebhookSubscription{ID: id, Secret: "hidden"}, nil)
+
+		result, err := service.GetWebhook(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Secret)
+	})
+
+	t.Run("Error - Subscription not found", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		id := uuid.New()
+		mockRepo.On("FindSubscriptionByID", mock.Anything, id).Return(nil, repository.ErrWebhookNotFound)
+
+		result, err := service.GetWebhook(context.Background(), id)
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrWebhookNotFound, err)
+	})
+}
+
+func TestWebhookService_ReplayDelivery(t *testing.T) {
+	t.Run("Success - Requeues the delivery", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		subID := uuid.New()
+		delivery := &entity.WebhookDelivery{ID: uuid.New(), SubscriptionID: subID, Status: entity.WebhookDeliveryDead}
+
+		mockRepo.On("FindDeliveryByID", mock.Anything, subID, delivery.ID).Return(delivery, nil)
+		mockRepo.On("RequeueDelivery", mock.Anything, delivery.ID).Return(nil)
+		mockRepo.On("FindDeliveryAttempts", mock.Anything, delivery.ID).Return([]entity.WebhookDeliveryAttempt{}, nil)
+
+		_, err := service.ReplayDelivery(context.Background(), subID, delivery.ID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Delivery belongs to another subscription", func(t *testing.T) {
+		mockRepo := new(mocks.MockWebhookRepository)
+		service := NewWebhookService(mockRepo)
+
+		subID := uuid.New()
+		deliveryID := uuid.New()
+		mockRepo.On("FindDeliveryByID", mock.Anything, subID, deliveryID).Return(nil, repository.ErrWebhookDeliveryNotFound)
+
+		result, err := service.ReplayDelivery(context.Background(), subID, deliveryID)
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrWebhookDeliveryNotFound, err)
+		mockRepo.AssertNotCalled(t, "RequeueDelivery", mock.Anything, mock.Anything)
+	})
+}
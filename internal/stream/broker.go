@@ -0,0 +1,123 @@
+package stream
+
+import (
+	"project-simple/internal/domain/event"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is disconnected; it then reconnects and resumes from the replay buffer
+const subscriberBuffer = 64
+
+// Broker fans events out to live subscribers and keeps the most recent ones
+// in a bounded buffer so reconnecting clients can resume where they left off
+type Broker struct {
+	mu          sync.Mutex
+	buffer      []event.Envelope
+	capacity    int
+	subscribers map[chan event.Envelope]struct{}
+	closed      bool
+}
+
+func NewBroker(capacity int) *Broker {
+	return &Broker{
+		buffer:      make([]event.Envelope, 0, capacity),
+		capacity:    capacity,
+		subscribers: make(map[chan event.Envelope]struct{}),
+	}
+}
+
+// Publish records an event and forwards it to every subscriber. Events the
+// relay delivers again after a partial failure are ignored.
+func (b *Broker) Publish(env event.Envelope) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, buffered := range b.buffer {
+		if buffered.ID == env.ID {
+			return
+		}
+	}
+
+	if len(b.buffer) == b.capacity {
+		copy(b.buffer, b.buffer[1:])
+		b.buffer = b.buffer[:len(b.buffer)-1]
+	}
+	b.buffer = append(b.buffer, env)
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- env:
+		default:
+			// Too slow: disconnect rather than block every other subscriber
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscription is a live feed of events
+type Subscription struct {
+	// Replay holds the buffered events the client missed
+	Replay []event.Envelope
+	// Resumed is false when the requested event is no longer buffered, in
+	// which case the client may have missed events and should refetch state
+	Resumed bool
+	// Events delivers new events; it is closed if the subscriber falls behind
+	Events <-chan event.Envelope
+
+	broker *Broker
+	ch     chan event.Envelope
+}
+
+// Subscribe registers a subscriber. When lastEventID is not empty the events
+// published after it are returned for replay.
+func (b *Broker) Subscribe(lastEventID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan event.Envelope, subscriberBuffer)
+	sub := &Subscription{Resumed: true, Events: ch, broker: b, ch: ch}
+	if b.closed {
+		close(ch)
+		return sub
+	}
+	b.subscribers[ch] = struct{}{}
+	if lastEventID == "" {
+		return sub
+	}
+
+	for i := len(b.buffer) - 1; i >= 0; i-- {
+		if EventID(b.buffer[i]) == lastEventID {
+			sub.Replay = append([]event.Envelope(nil), b.buffer[i+1:]...)
+			return sub
+		}
+	}
+
+	sub.Resumed = false
+	return sub
+}
+
+// Close ends every subscription and rejects new ones. It is called on
+// shutdown so open streams do not hold the server up.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Close unregisters the subscription
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if _, ok := s.broker.subscribers[s.ch]; ok {
+		delete(s.broker.subscribers, s.ch)
+		close(s.ch)
+	}
+}
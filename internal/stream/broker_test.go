@@ -0,0 +1,107 @@
+package stream
+
+import (
+	"testing"
+
+	"project-simple/internal/domain/event"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func envelope(seq int64) event.Envelope {
+	return event.Envelope{ID: uuid.New(), Sequence: seq, Type: event.TypeCarUpdated}
+}
+
+func TestBroker(t *testing.T) {
+	t.Run("Delivers published events to subscribers", func(t *testing.T) {
+		broker := NewBroker(10)
+		sub := broker.Subscribe("")
+		defer sub.Close()
+
+		broker.Publish(envelope(1))
+
+		got := <-sub.Events
+		assert.Equal(t, int64(1), got.Sequence)
+		assert.Empty(t, sub.Replay)
+		assert.True(t, sub.Resumed)
+	})
+
+	t.Run("Replays events after Last-Event-ID", func(t *testing.T) {
+		broker := NewBroker(10)
+		for seq := int64(1); seq <= 5; seq++ {
+			broker.Publish(envelope(seq))
+		}
+
+		sub := broker.Subscribe("3")
+		defer sub.Close()
+
+		assert.True(t, sub.Resumed)
+		assert.Len(t, sub.Replay, 2)
+		assert.Equal(t, int64(4), sub.Replay[0].Sequence)
+		assert.Equal(t, int64(5), sub.Replay[1].Sequence)
+	})
+
+	t.Run("Ignores redelivered events", func(t *testing.T) {
+		broker := NewBroker(10)
+		env := envelope(1)
+		broker.Publish(env)
+
+		sub := broker.Subscribe("")
+		defer sub.Close()
+
+		broker.Publish(env)
+		broker.Publish(envelope(2))
+
+		got := <-sub.Events
+		assert.Equal(t, int64(2), got.Sequence)
+	})
+
+	t.Run("Buffer is bounded and evicts oldest events", func(t *testing.T) {
+		broker := NewBroker(3)
+		for seq := int64(1); seq <= 5; seq++ {
+			broker.Publish(envelope(seq))
+		}
+
+		sub := broker.Subscribe("1")
+		defer sub.Close()
+
+		assert.False(t, sub.Resumed)
+		assert.Empty(t, sub.Replay)
+		assert.Len(t, broker.buffer, 3)
+	})
+
+	t.Run("Slow subscribers are disconnected", func(t *testing.T) {
+		broker := NewBroker(10)
+		sub := broker.Subscribe("")
+
+		for seq := int64(1); seq <= subscriberBuffer+1; seq++ {
+			broker.Publish(envelope(seq))
+		}
+
+		received := 0
+		for range sub.Events {
+			received++
+		}
+		assert.Equal(t, subscriberBuffer, received)
+
+		// Closing after the broker dropped the subscriber must not panic
+		sub.Close()
+	})
+	t.Run("Close ends open and new subscriptions", func(t *testing.T) {
+		broker := NewBroker(10)
+		sub := broker.Subscribe("")
+
+		broker.Close()
+
+		_, ok := <-sub.Events
+		assert.False(t, ok)
+
+		late := broker.Subscribe("")
+		_, ok = <-late.Events
+		assert.False(t, ok)
+
+		sub.Close()
+		late.Close()
+	})
+}
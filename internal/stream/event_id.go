@@ -0,0 +1,12 @@
+package stream
+
+import (
+	"project-simple/internal/domain/event"
+	"strconv"
+)
+
+// EventID is the SSE id of an event. The outbox sequence is shared by every
+// replica, so a client can resume against any of them.
+func EventID(env event.Envelope) string {
+	return strconv.FormatInt(env.Sequence, 10)
+}
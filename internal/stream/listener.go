@@ -0,0 +1,68 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"project-simple/internal/domain/event"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// reconnectDelay is how long the listener waits before reconnecting after
+// losing its connection
+const reconnectDelay = 2 * time.Second
+
+// Listener feeds the broker from Postgres notifications on a dedicated
+// connection, so events committed by any replica reach local subscribers
+type Listener struct {
+	dsn    string
+	broker *Broker
+}
+
+func NewListener(dsn string, broker *Broker) *Listener {
+	return &Listener{dsn: dsn, broker: broker}
+}
+
+// Run listens until ctx is cancelled, reconnecting on failure
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		if err := l.listen(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Event stream listener error", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var env event.Envelope
+		if err := json.Unmarshal([]byte(n.Payload), &env); err != nil {
+			slog.Warn("Event stream listener: discarding malformed notification", slog.Any("error", err))
+			continue
+		}
+
+		l.broker.Publish(env)
+	}
+}
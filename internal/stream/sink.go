@@ -0,0 +1,46 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"project-simple/internal/domain/event"
+	"project-simple/internal/repository"
+)
+
+// Channel is the Postgres notification channel car events are broadcast on
+const Channel = "car_events"
+
+// maxNotifyPayload is Postgres' limit on the size of a NOTIFY payload
+const maxNotifyPayload = 8000
+
+// Sink is an outbox sink that broadcasts car events to every replica with
+// NOTIFY. It runs inside the relay transaction, so listeners only see events
+// whose publication was committed.
+type Sink struct {
+	outboxRepo repository.OutboxRepository
+}
+
+func NewSink(outboxRepo repository.OutboxRepository) *Sink {
+	return &Sink{outboxRepo: outboxRepo}
+}
+
+func (s *Sink) Name() string {
+	return "stream"
+}
+
+func (s *Sink) Publish(ctx context.Context, env event.Envelope) error {
+	if env.AggregateType != event.AggregateCar {
+		return nil
+	}
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	if len(payload) >= maxNotifyPayload {
+		return fmt.Errorf("event %s is too large to broadcast (%d bytes)", env.ID, len(payload))
+	}
+
+	return s.outboxRepo.Notify(ctx, Channel, string(payload))
+}
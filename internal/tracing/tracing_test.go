@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"project-simple/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewProvider(t *testing.T) {
+	t.Run("Success - spans reach the in-memory exporter", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		provider, err := NewProvider(exporter, "car-api-test")
+		require.NoError(t, err)
+
+		_, span := provider.Tracer(instrumentationName).Start(context.Background(), "work")
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "work", spans[0].Name)
+	})
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("Success - none installs no exporter", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: ExporterNone})
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Error - unknown exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), config.TracingConfig{Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,160 @@
+// Package validation configures request validation and translates its errors
+// into the client's language.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"project-simple/pkg/response"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	de_translations "github.com/go-playground/validator/v10/translations/de"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	"golang.org/x/text/language"
+)
+
+type locale struct {
+	tag        language.Tag
+	translator locales.Translator
+	register   func(*validator.Validate, ut.Translator) error
+	// overrides replace default messages that read poorly
+	overrides map[string]string
+	// invalid is the message for rules without a translation
+	invalid string
+}
+
+// supported lists the locales in preference order; the first is the default
+var supported = []locale{
+	{
+		tag:        language.English,
+		translator: en.New(),
+		register:   en_translations.RegisterDefaultTranslations,
+		overrides: map[string]string{
+			"min-string": "{0} must be at least {1}",
+			"max-string": "{0} must be at most {1}",
+		},
+		invalid: "{0} is invalid",
+	},
+	{
+		tag:        language.BrazilianPortuguese,
+		translator: pt_BR.New(),
+		register:   pt_BR_translations.RegisterDefaultTranslations,
+		invalid:    "{0} é inválido",
+	},
+	{
+		tag:        language.German,
+		translator: de.New(),
+		register:   de_translations.RegisterDefaultTranslations,
+		invalid:    "{0} ist ungültig",
+	},
+}
+
+var (
+	setupOnce   sync.Once
+	setupErr    error
+	translators []ut.Translator
+	matcher     language.Matcher
+)
+
+// Setup configures gin's validator: field names come from json tags (form
+// tags for query parameters) and messages are registered for every supported
+// locale. Call it before the first request is bound, since the validator
+// caches field names per struct. It is safe to call more than once.
+func Setup() error {
+	setupOnce.Do(func() {
+		setupErr = setup()
+	})
+	return setupErr
+}
+
+func setup() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin validator is not go-playground/validator")
+	}
+	v.RegisterTagNameFunc(fieldName)
+
+	uni := ut.New(supported[0].translator)
+	tags := make([]language.Tag, 0, len(supported))
+	for _, l := range supported {
+		if err := uni.AddTranslator(l.translator, true); err != nil {
+			return fmt.Errorf("add %s translator: %w", l.tag, err)
+		}
+		trans, _ := uni.GetTranslator(l.translator.Locale())
+		if err := l.register(v, trans); err != nil {
+			return fmt.Errorf("register %s messages: %w", l.tag, err)
+		}
+		for key, text := range l.overrides {
+			if err := trans.Add(key, text, true); err != nil {
+				return fmt.Errorf("override %s message %s: %w", l.tag, key, err)
+			}
+		}
+		if err := trans.Add("invalid", l.invalid, false); err != nil {
+			return fmt.Errorf("add %s fallback message: %w", l.tag, err)
+		}
+		translators = append(translators, trans)
+		tags = append(tags, l.tag)
+	}
+	matcher = language.NewMatcher(tags)
+	return nil
+}
+
+// fieldName names a field after its json tag, or its form tag for query
+// parameters; an empty name makes the validator fall back to the Go name
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Negotiate picks the supported locale best matching an Accept-Language
+// header, defaulting to English. It returns the translator and the BCP 47
+// tag to send as Content-Language.
+func Negotiate(acceptLanguage string) (ut.Translator, string) {
+	if err := Setup(); err != nil {
+		return nil, ""
+	}
+	desired, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, index, _ := matcher.Match(desired...)
+	return translators[index], supported[index].tag.String()
+}
+
+// Translate returns one message per failed rule in err, or nil when err is
+// not a validation error
+func Translate(err error, trans ut.Translator) []response.ValidationError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fieldErrors := make([]response.ValidationError, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		message := e.Error()
+		if trans != nil {
+			message = e.Translate(trans)
+			// Translate falls back to the raw Go error for unknown rules
+			if message == e.Error() {
+				message, _ = trans.T("invalid", e.Field())
+			}
+		}
+		fieldErrors = append(fieldErrors, response.ValidationError{Field: e.Field(), Message: message})
+	}
+	return fieldErrors
+}
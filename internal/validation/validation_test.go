@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type carRequest struct {
+	Brand string `json:"brand" binding:"required,min=2,max=50"`
+	Year  int    `json:"year" binding:"min=1900"`
+	Color string `json:"color" binding:"omitempty,oneof=red blue"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+}
+
+func translate(t *testing.T, acceptLanguage string, req carRequest) (map[string]string, string) {
+	t.Helper()
+	require.NoError(t, Setup())
+
+	err := binding.Validator.ValidateStruct(&req)
+	require.Error(t, err)
+
+	trans, lang := Negotiate(acceptLanguage)
+	messages := map[string]string{}
+	for _, e := range Translate(err, trans) {
+		messages[e.Field] = e.Message
+	}
+	return messages, lang
+}
+
+func TestTranslate(t *testing.T) {
+	invalid := carRequest{Brand: "X", Year: 1800, Color: "green", Page: -1}
+
+	t.Run("Success - English messages include limits and JSON names", func(t *testing.T) {
+		messages, lang := translate(t, "en-US", invalid)
+
+		assert.Equal(t, "en", lang)
+		assert.Equal(t, "brand must be at least 2 characters", messages["brand"])
+		assert.Equal(t, "year must be 1,900 or greater", messages["year"])
+		assert.Equal(t, "color must be one of [red blue]", messages["color"])
+		assert.Equal(t, "page must be 1 or greater", messages["page"])
+	})
+
+	t.Run("Success - Brazilian Portuguese", func(t *testing.T) {
+		messages, lang := translate(t, "pt-BR,pt;q=0.9", invalid)
+
+		assert.Equal(t, "pt-BR", lang)
+		assert.Equal(t, "brand deve ter pelo menos 2 caracteres", messages["brand"])
+	})
+
+	t.Run("Success - German for a regional variant", func(t *testing.T) {
+		messages, lang := translate(t, "de-AT", carRequest{Year: 1900})
+
+		assert.Equal(t, "de", lang)
+		assert.Equal(t, "brand ist ein Pflichtfeld", messages["brand"])
+	})
+
+	t.Run("Should honour quality values", func(t *testing.T) {
+		_, lang := translate(t, "fr;q=1, de;q=0.8, en;q=0.5", invalid)
+
+		assert.Equal(t, "de", lang)
+	})
+
+	t.Run("Should default to English", func(t *testing.T) {
+		for _, header := range []string{"", "fr-FR", "not a language;;"} {
+			_, lang := translate(t, header, invalid)
+			assert.Equal(t, "en", lang, header)
+		}
+	})
+
+	t.Run("Should use a generic message for rules without a translation", func(t *testing.T) {
+		require.NoError(t, Setup())
+		v := binding.Validator.Engine().(*validator.Validate)
+		require.NoError(t, v.RegisterValidation("even_test", func(fl validator.FieldLevel) bool {
+			return fl.Field().Int()%2 == 0
+		}))
+		req := struct {
+			Doors int `json:"doors" binding:"even_test"`
+		}{Doors: 3}
+
+		err := binding.Validator.ValidateStruct(&req)
+		require.Error(t, err)
+		trans, _ := Negotiate("pt-BR")
+
+		assert.Equal(t, "doors é inválido", Translate(err, trans)[0].Message)
+	})
+
+	t.Run("Should return nil for other errors", func(t *testing.T) {
+		trans, _ := Negotiate("en")
+
+		assert.Nil(t, Translate(assert.AnError, trans))
+	})
+}
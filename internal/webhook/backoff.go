@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay before retrying after the given number of failed
+// attempts: exponential growth from base, capped at max, with the upper half
+// randomised so that failing receivers are not hit in lockstep
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := max
+	if attempts < 1 {
+		attempts = 1
+	}
+	if shift := attempts - 1; shift < 32 {
+		if d := base << shift; d > 0 && d < max {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
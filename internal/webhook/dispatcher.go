@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository"
+	"time"
+)
+
+// DispatcherConfig controls retries and throughput of webhook delivery
+type DispatcherConfig struct {
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	BatchSize    int
+	PollInterval time.Duration
+	// Lease is how long a claimed batch is hidden from other dispatchers.
+	// It must outlast sending the whole batch, or deliveries are sent twice.
+	Lease time.Duration
+}
+
+// Dispatcher sends pending deliveries to subscribers. Several dispatchers
+// may run at once: each claims different rows.
+type Dispatcher struct {
+	webhookRepo repository.WebhookRepository
+	transactor  repository.Transactor
+	client      *http.Client
+	cfg         DispatcherConfig
+}
+
+func NewDispatcher(webhookRepo repository.WebhookRepository, transactor repository.Transactor, client *http.Client, cfg DispatcherConfig) *Dispatcher {
+	return &Dispatcher{
+		webhookRepo: webhookRepo,
+		transactor:  transactor,
+		client:      client,
+		cfg:         cfg,
+	}
+}
+
+// Run polls until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Webhook dispatcher error", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// DispatchDue attempts one batch of due deliveries and returns how many
+// succeeded. The batch is leased in a short transaction, sent outside any
+// transaction and each attempt is saved in a transaction of its own, so no
+// connection or lock is held while partners answer. If saving fails the
+// rest of the batch is left to be retried once its lease expires.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	var deliveries []entity.WebhookDelivery
+	err := d.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		deliveries, err = d.webhookRepo.ClaimDueDeliveries(ctx, d.cfg.BatchSize, time.Now().Add(d.cfg.Lease))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		sub, err := d.webhookRepo.FindSubscriptionByID(ctx, delivery.SubscriptionID)
+		if err != nil && !errors.Is(err, repository.ErrWebhookNotFound) {
+			return succeeded, err
+		}
+
+		// Paused since the batch was claimed: hand the delivery back without
+		// spending an attempt, to be sent once the subscription is resumed
+		if sub != nil && !sub.Active {
+			if err := d.webhookRepo.RequeueDelivery(ctx, delivery.ID); err != nil {
+				return succeeded, err
+			}
+			continue
+		}
+
+		attempt := d.attempt(ctx, sub, delivery)
+		err = d.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			return d.webhookRepo.SaveDeliveryAttempt(ctx, delivery, attempt)
+		})
+		if err != nil {
+			return succeeded, err
+		}
+
+		if delivery.Status == entity.WebhookDeliverySucceeded {
+			succeeded++
+		}
+	}
+
+	return succeeded, nil
+}
+
+// attempt performs one HTTP call and updates the delivery with its outcome
+func (d *Dispatcher) attempt(ctx context.Context, sub *entity.WebhookSubscription, delivery *entity.WebhookDelivery) *entity.WebhookDeliveryAttempt {
+	delivery.Attempts++
+	attempt := &entity.WebhookDeliveryAttempt{
+		DeliveryID:     delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		Attempt:        delivery.Attempts,
+	}
+
+	start := time.Now()
+	statusCode, err := d.send(ctx, sub, delivery)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	attempt.StatusCode = statusCode
+	delivery.LastStatusCode = statusCode
+
+	if err == nil {
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	delivery.LastError = err.Error()
+
+	if sub == nil || delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = entity.WebhookDeliveryDead
+		return attempt
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(Backoff(delivery.Attempts, d.cfg.BaseBackoff, d.cfg.MaxBackoff))
+	return attempt
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *entity.WebhookSubscription, delivery *entity.WebhookDelivery) (int, error) {
+	if sub == nil {
+		return 0, errors.New("subscription is deleted")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "car-api-webhooks/1.0")
+	req.Header.Set(HeaderID, delivery.ID.String())
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, fmt.Sprintf("%d", now.Unix()))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
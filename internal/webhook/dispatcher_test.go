@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestDispatcher(repo *mocks.MockWebhookRepository, maxAttempts int) *Dispatcher {
+	return NewDispatcher(repo, &mocks.MockTransactor{}, http.DefaultClient, DispatcherConfig{
+		MaxAttempts:  maxAttempts,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+		BatchSize:    10,
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+	})
+}
+
+// txRecorder counts transactions and tells whether one is open
+type txRecorder struct {
+	count int
+	open  bool
+}
+
+func (r *txRecorder) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.count++
+	r.open = true
+	defer func() { r.open = false }()
+	return fn(ctx)
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	t.Run("Success - Sends signed payload", func(t *testing.T) {
+		secret := "whsec_dispatcher_test"
+		payload := []byte(`{"type":"car.created"}`)
+
+		var received *http.Request
+		var receivedBody []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			receivedBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		mockRepo := new(mocks.MockWebhookRepository)
+		sub := &entity.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: secret, Active: true}
+		delivery := entity.WebhookDelivery{ID: uuid.New(), SubscriptionID: sub.ID, EventType: "car.created", Payload: payload, Status: entity.WebhookDeliveryPending}
+
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, 10, mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+		mockRepo.On("FindSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+		mockRepo.On("SaveDeliveryAttempt", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.Status == entity.WebhookDeliverySucceeded && d.Attempts == 1
+		}), mock.MatchedBy(func(a *entity.WebhookDeliveryAttempt) bool {
+			return a.StatusCode == http.StatusNoContent && a.Attempt == 1
+		})).Return(nil)
+
+		succeeded, err := newTestDispatcher(mockRepo, 3).DispatchDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, succeeded)
+		assert.Equal(t, payload, receivedBody)
+		assert.Equal(t, delivery.ID.String(), received.Header.Get(HeaderID))
+		assert.Equal(t, "car.created", received.Header.Get(HeaderEvent))
+		assert.NoError(t, Verify(secret, received.Header.Get(HeaderSignature), received.Header.Get(HeaderTimestamp), receivedBody, time.Minute, time.Now()))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Schedules a retry with backoff", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		mockRepo := new(mocks.MockWebhookRepository)
+		sub := &entity.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "secret", Active: true}
+		delivery := entity.WebhookDelivery{ID: uuid.New(), SubscriptionID: sub.ID, Payload: []byte(`{}`), Status: entity.WebhookDeliveryPending}
+
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, 10, mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+		mockRepo.On("FindSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+		mockRepo.On("SaveDeliveryAttempt", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.Status == entity.WebhookDeliveryPending &&
+				d.LastStatusCode == http.StatusServiceUnavailable &&
+				d.NextAttemptAt.After(time.Now())
+		}), mock.Anything).Return(nil)
+
+		succeeded, err := newTestDispatcher(mockRepo, 3).DispatchDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, succeeded)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Moves to dead letter after max attempts", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		mockRepo := new(mocks.MockWebhookRepository)
+		sub := &entity.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "secret", Active: true}
+		delivery := entity.WebhookDelivery{ID: uuid.New(), SubscriptionID: sub.ID, Payload: []byte(`{}`), Status: entity.WebhookDeliveryPending, Attempts: 2}
+
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, 10, mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+		mockRepo.On("FindSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+		mockRepo.On("SaveDeliveryAttempt", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.Status == entity.WebhookDeliveryDead && d.Attempts == 3
+		}), mock.Anything).Return(nil)
+
+		_, err := newTestDispatcher(mockRepo, 3).DispatchDue(context.Background())
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Should send outside transactions and save each attempt in its own", func(t *testing.T) {
+		tx := &txRecorder{}
+		sentInTx := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sentInTx = sentInTx || tx.open
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		mockRepo := new(mocks.MockWebhookRepository)
+		sub := &entity.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "secret", Active: true}
+		deliveries := []entity.WebhookDelivery{
+			{ID: uuid.New(), SubscriptionID: sub.ID, Payload: []byte(`{}`), Status: entity.WebhookDeliveryPending},
+			{ID: uuid.New(), SubscriptionID: sub.ID, Payload: []byte(`{}`), Status: entity.WebhookDeliveryPending},
+			{ID: uuid.New(), SubscriptionID: sub.ID, Payload: []byte(`{}`), Status: entity.WebhookDeliveryPending},
+		}
+		dispatcher := NewDispatcher(mockRepo, tx, http.DefaultClient, DispatcherConfig{MaxAttempts: 3, BatchSize: 10, Lease: time.Minute})
+
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, 10, mock.MatchedBy(func(leaseUntil time.Time) bool {
+			return leaseUntil.After(time.Now().Add(30 * time.Second))
+		})).Return(deliveries, nil)
+		mockRepo.On("FindSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+		mockRepo.On("SaveDeliveryAttempt", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.ID == deliveries[1].ID
+		}), mock.Anything).Return(errors.New("connection reset"))
+		mockRepo.On("SaveDeliveryAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		succeeded, err := dispatcher.DispatchDue(context.Background())
+
+		assert.EqualError(t, err, "connection reset")
+		assert.Equal(t, 1, succeeded, "the first attempt stays saved")
+		assert.False(t, sentInTx)
+		assert.Equal(t, 3, tx.count, "one to claim, one per saved attempt")
+		mockRepo.AssertNumberOfCalls(t, "SaveDeliveryAttempt", 2)
+	})
+
+	t.Run("Should keep deliveries of a paused subscription until it is resumed", func(t *testing.T) {
+		received := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received++
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		mockRepo := new(mocks.MockWebhookRepository)
+		sub := &entity.WebhookSubscription{ID: uuid.New(), URL: server.URL, Secret: "secret", Active: false}
+		delivery := entity.WebhookDelivery{ID: uuid.New(), SubscriptionID: sub.ID, Payload: []byte(`{}`), Status: entity.WebhookDeliveryPending}
+		dispatcher := newTestDispatcher(mockRepo, 1)
+
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, 10, mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+		mockRepo.On("FindSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+		mockRepo.On("RequeueDelivery", mock.Anything, delivery.ID).Return(nil).Once()
+
+		succeeded, err := dispatcher.DispatchDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, succeeded)
+		assert.Equal(t, 0, received)
+		mockRepo.AssertNotCalled(t, "SaveDeliveryAttempt", mock.Anything, mock.Anything, mock.Anything)
+
+		sub.Active = true
+		mockRepo.On("SaveDeliveryAttempt", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.Status == entity.WebhookDeliverySucceeded && d.Attempts == 1
+		}), mock.Anything).Return(nil)
+
+		succeeded, err = dispatcher.DispatchDue(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, succeeded, "the pause did not use up the only attempt")
+		assert.Equal(t, 1, received)
+		mockRepo.AssertExpectations(t)
+	})
+}
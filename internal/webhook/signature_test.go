@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_test_secret_value"
+	body := []byte(`{"type":"car.created"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("Valid signature is accepted", func(t *testing.T) {
+		signature := Sign(secret, now, body)
+
+		assert.NoError(t, Verify(secret, signature, timestamp, body, 5*time.Minute, now))
+	})
+
+	t.Run("Tampered body is rejected", func(t *testing.T) {
+		signature := Sign(secret, now, body)
+
+		err := Verify(secret, signature, timestamp, []byte(`{"type":"car.deleted"}`), 5*time.Minute, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Wrong secret is rejected", func(t *testing.T) {
+		signature := Sign("another_secret_value", now, body)
+
+		err := Verify(secret, signature, timestamp, body, 5*time.Minute, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Old timestamp is rejected", func(t *testing.T) {
+		sentAt := now.Add(-10 * time.Minute)
+		signature := Sign(secret, sentAt, body)
+
+		err := Verify(secret, signature, strconv.FormatInt(sentAt.Unix(), 10), body, 5*time.Minute, now)
+		assert.ErrorIs(t, err, ErrStaleTimestamp)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	base := time.Second
+	max := time.Minute
+
+	t.Run("Grows exponentially within jitter bounds", func(t *testing.T) {
+		for attempts, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second} {
+			delay := Backoff(attempts, base, max)
+			assert.GreaterOrEqual(t, delay, expected/2)
+			assert.LessOrEqual(t, delay, expected)
+		}
+	})
+
+	t.Run("Is capped at max", func(t *testing.T) {
+		delay := Backoff(40, base, max)
+		assert.GreaterOrEqual(t, delay, max/2)
+		assert.LessOrEqual(t, delay, max)
+	})
+}
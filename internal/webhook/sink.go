@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"project-simple/internal/domain/entity"
+	"project-simple/internal/domain/event"
+	"project-simple/internal/repository"
+	"time"
+)
+
+// Payload is the JSON body posted to subscribers
+type Payload struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Sink is an outbox sink that fans events out into one pending delivery
+// per matching subscription. The dispatcher performs the HTTP calls.
+type Sink struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewSink(webhookRepo repository.WebhookRepository) *Sink {
+	return &Sink{webhookRepo: webhookRepo}
+}
+
+func (s *Sink) Name() string {
+	return "webhook"
+}
+
+func (s *Sink) Publish(ctx context.Context, env event.Envelope) error {
+	subs, err := s.webhookRepo.FindSubscriptionsForEvent(ctx, env.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:            env.ID.String(),
+		Type:          env.Type,
+		AggregateType: env.AggregateType,
+		AggregateID:   env.AggregateID.String(),
+		OccurredAt:    env.OccurredAt,
+		Data:          env.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if err := s.webhookRepo.EnqueueDelivery(ctx, &entity.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        env.ID,
+			EventType:      env.Type,
+			Payload:        body,
+			Status:         entity.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
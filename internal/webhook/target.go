@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrTargetNotAllowed is returned for subscription URLs that are not public
+// HTTPS addresses, so partners cannot point deliveries at internal services
+var ErrTargetNotAllowed = errors.New("webhook target must be a public https URL")
+
+// sharedAddressSpace is the carrier-grade NAT range, private in all but name
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CheckURL rejects URLs that are not https or whose host is an internal
+// address or name. Names are not resolved here: a public name may resolve to
+// an internal address later, which the client from NewClient refuses.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrTargetNotAllowed
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrTargetNotAllowed
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !publicAddr(ip) {
+		return ErrTargetNotAllowed
+	}
+	return nil
+}
+
+// NewClient returns the HTTP client deliveries are sent with. It refuses to
+// connect to loopback, private, link-local and other non-public addresses
+// once names are resolved, ignores proxy settings, which would hide the
+// address dialled, and does not follow redirects.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s is not a public address", ErrTargetNotAllowed, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!sharedAddressSpace.Contains(ip)
+}
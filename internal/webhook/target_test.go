@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckURL(t *testing.T) {
+	t.Run("Success - Public HTTPS URLs", func(t *testing.T) {
+		for _, url := range []string{
+			"https://partner.example.com/hooks/cars",
+			"https://93.184.215.14:8443/hooks",
+			"https://[2606:2800:21f:cb07:6820:80da:af6b:8b2c]/hooks",
+		} {
+			assert.NoError(t, CheckURL(url), url)
+		}
+	})
+
+	t.Run("Error - Plain HTTP and internal targets", func(t *testing.T) {
+		for _, url := range []string{
+			"http://partner.example.com/hooks",
+			"ftp://partner.example.com/hooks",
+			"https:///hooks",
+			"https://localhost:8080/hooks",
+			"https://admin.localhost/hooks",
+			"https://127.0.0.1:6060/debug/pprof",
+			"https://[::1]/hooks",
+			"https://[::ffff:127.0.0.1]/hooks",
+			"https://0.0.0.0/hooks",
+			"https://10.1.2.3/hooks",
+			"https://172.16.0.1/hooks",
+			"https://192.168.1.1/hooks",
+			"https://100.64.0.1/hooks",
+			"https://169.254.169.254/latest/meta-data",
+			"https://[fe80::1]/hooks",
+			"https://[fd00::1]/hooks",
+		} {
+			assert.ErrorIs(t, CheckURL(url), ErrTargetNotAllowed, url)
+		}
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("Should refuse to connect to an internal address", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		_, err := NewClient(time.Second).Post(server.URL, "application/json", nil)
+
+		assert.ErrorIs(t, err, ErrTargetNotAllowed)
+		assert.False(t, called)
+	})
+
+	t.Run("Should not follow redirects", func(t *testing.T) {
+		client := NewClient(time.Second)
+
+		err := client.CheckRedirect(nil, nil)
+
+		assert.ErrorIs(t, err, http.ErrUseLastResponse)
+	})
+}
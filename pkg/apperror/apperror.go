@@ -0,0 +1,127 @@
+// Package apperror is the catalog of error codes the API returns. Codes are
+// part of the API contract: clients match on them instead of messages, so a
+// code is never renamed or reused once published.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Code identifies a kind of error, e.g. CAR_NOT_FOUND
+type Code string
+
+// Domain errors
+const (
+	CarNotFound             Code = "CAR_NOT_FOUND"
+	CarVersionNotFound      Code = "CAR_VERSION_NOT_FOUND"
+	RevertToDeletedVersion  Code = "REVERT_TO_DELETED_VERSION"
+	WebhookNotFound         Code = "WEBHOOK_NOT_FOUND"
+	WebhookDeliveryNotFound Code = "WEBHOOK_DELIVERY_NOT_FOUND"
+	WebhookURLNotAllowed    Code = "WEBHOOK_URL_NOT_ALLOWED"
+	JobNotFound             Code = "JOB_NOT_FOUND"
+	JobFinished             Code = "JOB_FINISHED"
+	JobResultUnavailable    Code = "JOB_RESULT_UNAVAILABLE"
+)
+
+// Request and server errors
+const (
+	InvalidRequest       Code = "INVALID_REQUEST"
+	InvalidID            Code = "INVALID_ID"
+	ValidationFailed     Code = "VALIDATION_FAILED"
+	NotFound             Code = "NOT_FOUND"
+	Conflict             Code = "CONFLICT"
+	Unauthorized         Code = "UNAUTHORIZED"
+	RequestTooLarge      Code = "REQUEST_TOO_LARGE"
+	NotAcceptable        Code = "NOT_ACCEPTABLE"
+	QueryTooComplex      Code = "QUERY_TOO_COMPLEX"
+	UnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	RateLimited          Code = "RATE_LIMITED"
+	InternalError        Code = "INTERNAL_ERROR"
+	ServiceUnavailable   Code = "SERVICE_UNAVAILABLE"
+	Timeout              Code = "TIMEOUT"
+)
+
+// Definition describes how a code is reported
+type Definition struct {
+	Code   Code   `json:"code" example:"CAR_NOT_FOUND"`
+	Status int    `json:"status" example:"404"`
+	Title  string `json:"title" example:"Car not found"`
+}
+
+var catalog = map[Code]Definition{
+	CarNotFound:             {CarNotFound, http.StatusNotFound, "Car not found"},
+	CarVersionNotFound:      {CarVersionNotFound, http.StatusNotFound, "Car version not found"},
+	RevertToDeletedVersion:  {RevertToDeletedVersion, http.StatusUnprocessableEntity, "Cannot revert to a version in which the car was deleted"},
+	WebhookNotFound:         {WebhookNotFound, http.StatusNotFound, "Webhook not found"},
+	WebhookDeliveryNotFound: {WebhookDeliveryNotFound, http.StatusNotFound, "Delivery not found"},
+	WebhookURLNotAllowed:    {WebhookURLNotAllowed, http.StatusUnprocessableEntity, "Webhook URL must be a public HTTPS address"},
+	JobNotFound:             {JobNotFound, http.StatusNotFound, "Job not found"},
+	JobFinished:             {JobFinished, http.StatusConflict, "Job has already finished"},
+	JobResultUnavailable:    {JobResultUnavailable, http.StatusConflict, "Job has no result until it succeeds"},
+	InvalidRequest:          {InvalidRequest, http.StatusBadRequest, "Invalid request"},
+	InvalidID:               {InvalidID, http.StatusBadRequest, "Invalid ID format"},
+	ValidationFailed:        {ValidationFailed, http.StatusUnprocessableEntity, "Validation failed"},
+	NotFound:                {NotFound, http.StatusNotFound, "Not found"},
+	Conflict:                {Conflict, http.StatusConflict, "Conflict"},
+	Unauthorized:            {Unauthorized, http.StatusUnauthorized, "Unauthorized"},
+	RequestTooLarge:         {RequestTooLarge, http.StatusRequestEntityTooLarge, "Request body too large"},
+	NotAcceptable:           {NotAcceptable, http.StatusNotAcceptable, "Not acceptable"},
+	QueryTooComplex:         {QueryTooComplex, http.StatusBadRequest, "Query too deep or complex"},
+	UnsupportedMediaType:    {UnsupportedMediaType, http.StatusUnsupportedMediaType, "Unsupported media type"},
+	RateLimited:             {RateLimited, http.StatusTooManyRequests, "Rate limit exceeded"},
+	InternalError:           {InternalError, http.StatusInternalServerError, "Internal server error"},
+	ServiceUnavailable:      {ServiceUnavailable, http.StatusServiceUnavailable, "Service unavailable"},
+	Timeout:                 {Timeout, http.StatusGatewayTimeout, "Request timed out"},
+}
+
+// Lookup returns the definition of code. Unknown codes are reported as
+// internal errors.
+func Lookup(code Code) Definition {
+	if def, ok := catalog[code]; ok {
+		return def
+	}
+	return catalog[InternalError]
+}
+
+// All returns every definition, ordered by code
+func All() []Definition {
+	defs := make([]Definition, 0, len(catalog))
+	for _, def := range catalog {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
+
+// Slug is the code in lowercase with dashes, e.g. car-not-found, as used in
+// problem type URIs
+func (c Code) Slug() string {
+	return strings.ReplaceAll(strings.ToLower(string(c)), "_", "-")
+}
+
+// Error is an error carrying a code. Declare sentinels with New so callers
+// can still match them with errors.Is.
+type Error struct {
+	Code    Code
+	Message string
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// CodeOf returns the code of the first coded error in err's chain
+func CodeOf(err error) (Code, bool) {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+	return "", false
+}
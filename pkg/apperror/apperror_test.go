@@ -0,0 +1,46 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Run("Should define every code once with a title and an error status", func(t *testing.T) {
+		for _, def := range All() {
+			assert.NotEmpty(t, def.Title, def.Code)
+			assert.GreaterOrEqual(t, def.Status, 400, def.Code)
+			assert.Equal(t, def, Lookup(def.Code))
+		}
+	})
+
+	t.Run("Should report unknown codes as internal errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusInternalServerError, Lookup("NOPE").Status)
+	})
+
+	t.Run("Should build slugs", func(t *testing.T) {
+		assert.Equal(t, "car-not-found", CarNotFound.Slug())
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	sentinel := New(CarNotFound, "car not found")
+
+	t.Run("Should find the code of a wrapped error", func(t *testing.T) {
+		err := fmt.Errorf("loading: %w", sentinel)
+
+		code, ok := CodeOf(err)
+		assert.True(t, ok)
+		assert.Equal(t, CarNotFound, code)
+		assert.ErrorIs(t, err, sentinel)
+	})
+
+	t.Run("Should report uncoded errors", func(t *testing.T) {
+		_, ok := CodeOf(errors.New("boom"))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"project-simple/internal/domain/dto"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The API's DTOs, named here so callers outside this module can use them
+type (
+	Car              = dto.CarResponse
+	CarVersion       = dto.CarVersionResponse
+	CreateCarRequest = dto.CreateCarRequest
+	UpdateCarRequest = dto.UpdateCarRequest
+	ListCarsRequest  = dto.PaginationRequest
+	CarFilter        = dto.CarFilter
+	CarPage          = dto.PaginatedResponse
+)
+
+const carsPath = "/api/v1/cars"
+
+func (c *Client) CreateCar(ctx context.Context, req *CreateCarRequest) (*Car, error) {
+	var car Car
+	if err := c.do(ctx, http.MethodPost, carsPath, nil, req, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (c *Client) GetCar(ctx context.Context, id uuid.UUID) (*Car, error) {
+	var car Car
+	if err := c.do(ctx, http.MethodGet, carPath(id), nil, nil, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+// GetCarAsOf returns the car as it was at asOf
+func (c *Client) GetCarAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*Car, error) {
+	query := url.Values{"as_of": {asOf.Format(time.RFC3339)}}
+
+	var car Car
+	if err := c.do(ctx, http.MethodGet, carPath(id), query, nil, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+// ListCars returns one page of cars. Zero fields of req take the server's
+// defaults; req may be nil.
+func (c *Client) ListCars(ctx context.Context, req *ListCarsRequest) (*CarPage, error) {
+	var page CarPage
+	if err := c.do(ctx, http.MethodGet, carsPath, listQuery(req), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UpdateCar changes the fields of req that are set and keeps the others
+func (c *Client) UpdateCar(ctx context.Context, id uuid.UUID, req *UpdateCarRequest) (*Car, error) {
+	var car Car
+	if err := c.do(ctx, http.MethodPut, carPath(id), nil, req, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (c *Client) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, carPath(id), nil, nil, nil)
+}
+
+func (c *Client) RestoreCar(ctx context.Context, id uuid.UUID) (*Car, error) {
+	var car Car
+	if err := c.do(ctx, http.MethodPost, carPath(id)+"/restore", nil, nil, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (c *Client) ListCarVersions(ctx context.Context, id uuid.UUID) ([]CarVersion, error) {
+	var versions []CarVersion
+	if err := c.do(ctx, http.MethodGet, carPath(id)+"/versions", nil, nil, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (c *Client) GetCarVersion(ctx context.Context, id uuid.UUID, version int) (*CarVersion, error) {
+	var v CarVersion
+	if err := c.do(ctx, http.MethodGet, versionPath(id, version), nil, nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// RevertCar restores the fields the car had at version, as a new version
+func (c *Client) RevertCar(ctx context.Context, id uuid.UUID, version int) (*Car, error) {
+	var car Car
+	if err := c.do(ctx, http.MethodPost, versionPath(id, version)+"/revert", nil, nil, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+// Pages iterates over the pages of cars matching req, from req.Page on, and
+// stops after the first error. Pages are fetched by number, so cars created
+// or deleted meanwhile may shift a car onto a page already seen.
+func (c *Client) Pages(ctx context.Context, req ListCarsRequest) iter.Seq2[*CarPage, error] {
+	return func(yield func(*CarPage, error) bool) {
+		if req.Page < 1 {
+			req.Page = dto.DefaultPage
+		}
+		for {
+			page, err := c.ListCars(ctx, &req)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+			if len(page.Data) == 0 || page.Pagination.CurrentPage >= page.Pagination.TotalPages {
+				return
+			}
+			req.Page = page.Pagination.CurrentPage + 1
+		}
+	}
+}
+
+// Cars iterates over every car matching req, fetching pages as it goes
+//
+//	for car, err := range cars.Cars(ctx, client.ListCarsRequest{PageSize: 100}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Cars(ctx context.Context, req ListCarsRequest) iter.Seq2[*Car, error] {
+	return func(yield func(*Car, error) bool) {
+		for page, err := range c.Pages(ctx, req) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range page.Data {
+				if !yield(&page.Data[i], nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func carPath(id uuid.UUID) string {
+	return carsPath + "/" + id.String()
+}
+
+func versionPath(id uuid.UUID, version int) string {
+	return carPath(id) + "/versions/" + strconv.Itoa(version)
+}
+
+// listQuery encodes the set fields of req as the list's query parameters
+func listQuery(req *ListCarsRequest) url.Values {
+	query := url.Values{}
+	if req == nil {
+		return query
+	}
+
+	if req.Page > 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	for key, value := range map[string]string{
+		"sort_by":        req.SortBy,
+		"sort_dir":       req.SortDir,
+		"name":           req.Name,
+		"engine_version": req.EngineVersion,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
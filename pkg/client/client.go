@@ -0,0 +1,245 @@
+// Package client is a typed Go client for the Car API's REST routes. It
+// takes and returns the API's own DTOs, retries requests the server turned
+// away with 429, and idempotent ones that got 503, and decodes error bodies
+// into *Error.
+//
+//	cars := client.New("http://localhost:8080", client.Config{Actor: "inventory-sync"})
+//	car, err := cars.CreateCar(ctx, &client.CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+//	if code, ok := apperror.CodeOf(err); ok && code == apperror.ValidationFailed { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Headers the client sends with every request
+const (
+	RequestIDHeader      = "X-Request-ID"
+	IdempotencyKeyHeader = "Idempotency-Key"
+	ActorHeader          = "X-Actor"
+)
+
+// Config sets how a client talks to the API. Zero values take the defaults.
+type Config struct {
+	// HTTPClient sends the requests; http.DefaultClient by default
+	HTTPClient *http.Client
+	// Actor is sent as X-Actor, naming the caller in the audit log
+	Actor string
+	// MaxAttempts bounds how often a request is sent, counting the first
+	// time (default 3); 1 disables retries
+	MaxAttempts int
+	// BaseBackoff and MaxBackoff bound the wait between attempts when the
+	// server does not send Retry-After (defaults 500ms and 10s)
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Client calls the Car API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	cfg     Config
+}
+
+// New returns a client for the API served at baseURL, e.g.
+// "https://cars.example.com"
+func New(baseURL string, cfg Config) *Client {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Second
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), cfg: cfg}
+}
+
+type requestIDKey struct{}
+type idempotencyKeyKey struct{}
+
+// WithRequestID makes the calls made with ctx send id as X-Request-ID, so
+// they can be traced with the caller's own request. Otherwise each call gets
+// a new ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// WithIdempotencyKey makes the POST made with ctx send key as
+// Idempotency-Key. Otherwise each call gets a new key, kept across the
+// client's own retries. The API does not deduplicate on the key yet, so it
+// does not make resending a POST safe: one answered with 503 may have run.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// envelope is the body of successful responses
+type envelope struct {
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// do sends a request and decodes the data of a successful response into
+// out, which may be nil. Responses of 429, and of 503 to idempotent methods,
+// are retried after the delay the server asked for, or with backoff; the
+// request ID and idempotency key stay the same across attempts. A DELETE
+// that got a 503 may have run, so a 404 to its retry means it succeeded.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set(RequestIDHeader, contextValue(ctx, requestIDKey{}))
+	if c.cfg.Actor != "" {
+		header.Set(ActorHeader, c.cfg.Actor)
+	}
+	if payload != nil {
+		header.Set("Content-Type", "application/json")
+	}
+	if method == http.MethodPost {
+		header.Set(IdempotencyKeyHeader, contextValue(ctx, idempotencyKeyKey{}))
+	}
+
+	mayHaveRun := false
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("build request: %w", err)
+		}
+		req.Header = header.Clone()
+
+		resp, err := c.cfg.HTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			return decodeData(resp, out)
+		}
+		if method == http.MethodDelete && mayHaveRun && resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return nil
+		}
+
+		apiErr := decodeError(resp)
+		resp.Body.Close()
+		if attempt >= c.cfg.MaxAttempts || !retryable(method, resp.StatusCode) {
+			return apiErr
+		}
+		mayHaveRun = mayHaveRun || resp.StatusCode == http.StatusServiceUnavailable
+
+		delay := apiErr.RetryAfter
+		if delay <= 0 {
+			delay = backoff(attempt, c.cfg.BaseBackoff, c.cfg.MaxBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func decodeData(resp *http.Response, out interface{}) error {
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("decode response data: %w", err)
+	}
+	return nil
+}
+
+// retryable reports whether a request can be sent again. A 429 comes from
+// the rate limiter, before the request runs. A 503 may come from an instance
+// shutting down after the request ran, so only methods that can safely run
+// twice are retried on it; do makes up for a DELETE whose retry finds the
+// resource already gone.
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return method != http.MethodPost && method != http.MethodPatch
+	}
+	return false
+}
+
+// parseRetryAfter reads Retry-After as seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// backoff returns the delay before the next attempt after the given number
+// of failed ones: exponential growth from base, capped at max, with the
+// upper half randomised. It matches the server's webhook backoff, without
+// depending on server packages.
+func backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := max
+	if shift := attempts - 1; shift < 32 {
+		if d := base << shift; d > 0 && d < max {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// contextValue returns the string stored in ctx under key, or a new UUID
+func contextValue(ctx context.Context, key interface{}) string {
+	if value, ok := ctx.Value(key).(string); ok && value != "" {
+		return value
+	}
+	return uuid.NewString()
+}
+
+// readAll reads a response body for error decoding, bounded so a broken
+// proxy cannot exhaust memory
+func readAll(r io.Reader) []byte {
+	body, _ := io.ReadAll(io.LimitReader(r, 1<<20))
+	return body
+}
@@ -0,0 +1,356 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"project-simple/internal/config"
+	"project-simple/internal/domain/dto"
+	"project-simple/internal/graphql"
+	"project-simple/internal/handler"
+	"project-simple/internal/health"
+	"project-simple/internal/ratelimit"
+	"project-simple/internal/router"
+	"project-simple/internal/service"
+	"project-simple/internal/stream"
+	"project-simple/internal/validation"
+	"project-simple/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCarService keeps cars in memory, in creation order
+type memoryCarService struct {
+	service.CarService
+	mu   sync.Mutex
+	cars []dto.CarResponse
+}
+
+func (m *memoryCarService) CreateCar(ctx context.Context, req *dto.CreateCarRequest) (*dto.CarResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC().Format(time.RFC3339)
+	car := dto.CarResponse{ID: uuid.New(), Name: req.Name, EngineVersion: req.EngineVersion, CreatedAt: now, UpdatedAt: now}
+	m.cars = append(m.cars, car)
+	return &car, nil
+}
+
+func (m *memoryCarService) GetCarByID(ctx context.Context, id uuid.UUID) (*dto.CarResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, car := range m.cars {
+		if car.ID == id {
+			return &car, nil
+		}
+	}
+	return nil, service.ErrCarNotFound
+}
+
+func (m *memoryCarService) GetAllCars(ctx context.Context, pagination *dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+	pagination.SetDefaults()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var matched []dto.CarResponse
+	for _, car := range m.cars {
+		if strings.Contains(strings.ToLower(car.Name), strings.ToLower(pagination.Name)) {
+			matched = append(matched, car)
+		}
+	}
+	start := min(pagination.GetOffset(), len(matched))
+	end := min(start+pagination.PageSize, len(matched))
+	return &dto.PaginatedResponse{
+		Data: append([]dto.CarResponse{}, matched[start:end]...),
+		Pagination: dto.PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   (len(matched) + pagination.PageSize - 1) / pagination.PageSize,
+			TotalRecords: int64(len(matched)),
+		},
+	}, nil
+}
+
+func (m *memoryCarService) UpdateCar(ctx context.Context, id uuid.UUID, req *dto.UpdateCarRequest) (*dto.CarResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.cars {
+		if m.cars[i].ID == id {
+			if req.Name != "" {
+				m.cars[i].Name = req.Name
+			}
+			if req.EngineVersion != "" {
+				m.cars[i].EngineVersion = req.EngineVersion
+			}
+			car := m.cars[i]
+			return &car, nil
+		}
+	}
+	return nil, service.ErrCarNotFound
+}
+
+func (m *memoryCarService) DeleteCar(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.cars {
+		if m.cars[i].ID == id {
+			m.cars = append(m.cars[:i], m.cars[i+1:]...)
+			return nil
+		}
+	}
+	return service.ErrCarNotFound
+}
+
+// newAPI serves the real router over cars. wrap, when set, sees every
+// request before the router does.
+func newAPI(t *testing.T, cars service.CarService, writeLimit int, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	cfg := &config.Config{}
+	cfg.Server.Env = "test"
+	cfg.RateLimit = config.RateLimitConfig{Requests: 1000, Period: time.Minute, WriteRequests: writeLimit, WritePeriod: time.Hour}
+	cfg.Import.MaxBytes = 1 << 20
+
+	graphqlServer, err := graphql.NewServer(cars, graphql.Config{MaxDepth: 10, MaxComplexity: 1000})
+	require.NoError(t, err)
+
+	var h http.Handler = router.SetupRouter(cfg, ratelimit.NewMemoryStore(),
+		handler.NewCarHandler(cars, nil, time.Minute),
+		handler.NewAuditHandler(nil),
+		handler.NewWebhookHandler(nil),
+		handler.NewJobHandler(nil),
+		handler.NewGraphQLHandler(graphqlServer, false),
+		handler.NewEventStreamHandler(stream.NewBroker(1), time.Minute),
+		handler.NewHealthHandler(nil, health.NewRegistry(time.Second, time.Second)),
+	)
+	if wrap != nil {
+		h = wrap(h)
+	}
+
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Cars(t *testing.T) {
+	api := newAPI(t, &memoryCarService{}, 1000, nil)
+	cars := New(api.URL, Config{Actor: "inventory-sync"})
+	ctx := context.Background()
+
+	t.Run("Success - Creates, reads, updates and deletes a car", func(t *testing.T) {
+		created, err := cars.CreateCar(ctx, &CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic", created.Name)
+
+		got, err := cars.GetCar(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created, got)
+
+		updated, err := cars.UpdateCar(ctx, created.ID, &UpdateCarRequest{EngineVersion: "1.8"})
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic", updated.Name)
+		assert.Equal(t, "1.8", updated.EngineVersion)
+
+		require.NoError(t, cars.DeleteCar(ctx, created.ID))
+
+		_, err = cars.GetCar(ctx, created.ID)
+		code, ok := apperror.CodeOf(err)
+		assert.True(t, ok)
+		assert.Equal(t, apperror.CarNotFound, code)
+	})
+
+	t.Run("Should decode validation errors with their fields", func(t *testing.T) {
+		_, err := cars.CreateCar(ctx, &CreateCarRequest{Name: "X", EngineVersion: "2.0"})
+
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+		assert.Equal(t, apperror.ValidationFailed, apiErr.Code)
+		require.Len(t, apiErr.Fields, 1)
+		assert.Equal(t, "name", apiErr.Fields[0].Field)
+		assert.NotEmpty(t, apiErr.RequestID)
+	})
+
+	t.Run("Success - Iterates over every page", func(t *testing.T) {
+		for i := range 5 {
+			_, err := cars.CreateCar(ctx, &CreateCarRequest{Name: "Golf " + string(rune('A'+i)), EngineVersion: "1.4"})
+			require.NoError(t, err)
+		}
+		_, err := cars.CreateCar(ctx, &CreateCarRequest{Name: "Corolla", EngineVersion: "1.8"})
+		require.NoError(t, err)
+
+		var pages int
+		for _, err := range cars.Pages(ctx, ListCarsRequest{PageSize: 2, CarFilter: CarFilter{Name: "golf"}}) {
+			require.NoError(t, err)
+			pages++
+		}
+		assert.Equal(t, 3, pages)
+
+		var names []string
+		for car, err := range cars.Cars(ctx, ListCarsRequest{PageSize: 2, CarFilter: CarFilter{Name: "golf"}}) {
+			require.NoError(t, err)
+			names = append(names, car.Name)
+		}
+		assert.Equal(t, []string{"Golf A", "Golf B", "Golf C", "Golf D", "Golf E"}, names)
+	})
+
+	t.Run("Should stop iterating at the first error", func(t *testing.T) {
+		var errs int
+		for car, err := range cars.Cars(ctx, ListCarsRequest{PageSize: 500}) {
+			assert.Nil(t, car)
+			assert.Equal(t, apperror.ValidationFailed, err.(*Error).Code)
+			errs++
+		}
+		assert.Equal(t, 1, errs)
+	})
+}
+
+func TestClient_Retries(t *testing.T) {
+	// flaky turns away the first two attempts of every request with status,
+	// as a busy or stopping instance would, and records the headers of each
+	// attempt
+	var (
+		mu       sync.Mutex
+		attempts []http.Header
+		status   int
+	)
+	flaky := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			attempts = append(attempts, r.Header.Clone())
+			n, code := len(attempts), status
+			mu.Unlock()
+			if n%3 != 0 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(code)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	api := newAPI(t, &memoryCarService{}, 1000, flaky)
+	cars := New(api.URL, Config{BaseBackoff: time.Millisecond})
+	reset := func(code int) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts, status = nil, code
+	}
+
+	t.Run("Success - Retries 503 of a GET with the same request ID", func(t *testing.T) {
+		reset(http.StatusServiceUnavailable)
+		ctx := WithRequestID(context.Background(), "req-123")
+
+		_, err := cars.ListCars(ctx, &ListCarsRequest{})
+
+		require.NoError(t, err)
+		require.Len(t, attempts, 3)
+		for _, header := range attempts {
+			assert.Equal(t, "req-123", header.Get(RequestIDHeader))
+		}
+	})
+
+	t.Run("Success - Retries 429 of a POST with the same idempotency key", func(t *testing.T) {
+		reset(http.StatusTooManyRequests)
+
+		car, err := cars.CreateCar(context.Background(), &CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Honda Civic", car.Name)
+		require.Len(t, attempts, 3)
+		key := attempts[0].Get(IdempotencyKeyHeader)
+		assert.NotEmpty(t, key)
+		for _, header := range attempts {
+			assert.Equal(t, key, header.Get(IdempotencyKeyHeader))
+		}
+	})
+
+	t.Run("Should not retry a POST answered with 503, which may have run", func(t *testing.T) {
+		reset(http.StatusServiceUnavailable)
+
+		_, err := cars.CreateCar(context.Background(), &CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, apperror.ServiceUnavailable, apiErr.Code)
+		assert.Len(t, attempts, 1)
+	})
+
+	t.Run("Success - A DELETE retried after 503 that finds the car gone succeeded", func(t *testing.T) {
+		reset(http.StatusServiceUnavailable)
+
+		err := cars.DeleteCar(context.Background(), uuid.New())
+
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 3)
+	})
+
+	t.Run("Should report a DELETE of a missing car retried after 429 as not found", func(t *testing.T) {
+		reset(http.StatusTooManyRequests)
+
+		err := cars.DeleteCar(context.Background(), uuid.New())
+
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CarNotFound, code)
+	})
+
+	t.Run("Should give up after the last attempt", func(t *testing.T) {
+		reset(http.StatusServiceUnavailable)
+		once := New(api.URL, Config{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+
+		_, err := once.ListCars(context.Background(), &ListCarsRequest{})
+
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Equal(t, apperror.ServiceUnavailable, apiErr.Code)
+		assert.Len(t, attempts, 2)
+	})
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	var requests atomic.Int32
+	counted := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	}
+	api := newAPI(t, &memoryCarService{}, 1, counted)
+
+	t.Run("Should report the delay the server asked for", func(t *testing.T) {
+		cars := New(api.URL, Config{Actor: "alice", MaxAttempts: 1})
+		_, err := cars.CreateCar(context.Background(), &CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+		require.NoError(t, err)
+
+		_, err = cars.CreateCar(context.Background(), &CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, apperror.RateLimited, apiErr.Code)
+		assert.Greater(t, apiErr.RetryAfter, time.Minute)
+	})
+
+	t.Run("Should stop waiting when the context ends", func(t *testing.T) {
+		cars := New(api.URL, Config{Actor: "alice"})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		requests.Store(0)
+
+		_, err := cars.CreateCar(ctx, &CreateCarRequest{Name: "Honda Civic", EngineVersion: "2.0"})
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"project-simple/pkg/apperror"
+	"project-simple/pkg/response"
+	"time"
+)
+
+// Error is an error response from the API, decoded from either error format
+// (response.ErrorResponse or problem details). It wraps an *apperror.Error,
+// so apperror.CodeOf finds its code.
+type Error struct {
+	StatusCode int
+	Code       apperror.Code
+	Message    string
+	// Fields lists the invalid fields when Code is VALIDATION_FAILED
+	Fields    []response.ValidationError
+	RequestID string
+	// RetryAfter is how long the server asked to wait, from Retry-After
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("car api: %s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+func (e *Error) Unwrap() error {
+	return apperror.New(e.Code, e.Message)
+}
+
+// decodeError reads an error response. Bodies in neither format, such as a
+// proxy's error page, get the code matching the status.
+func decodeError(resp *http.Response) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(RequestIDHeader),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	body := readAll(resp.Body)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	var details json.RawMessage
+	switch mediaType {
+	case response.ProblemContentType:
+		var problem struct {
+			response.Problem
+			Errors json.RawMessage `json:"errors"`
+		}
+		if json.Unmarshal(body, &problem) == nil {
+			apiErr.Code = apperror.Code(problem.Code)
+			apiErr.Message = problem.Detail
+			if apiErr.Message == "" {
+				apiErr.Message = problem.Title
+			}
+			details = problem.Errors
+		}
+	case "application/json":
+		var errResp struct {
+			response.ErrorResponse
+			Details json.RawMessage `json:"details"`
+		}
+		if json.Unmarshal(body, &errResp) == nil {
+			apiErr.Code = apperror.Code(errResp.Code)
+			apiErr.Message = errResp.Message
+			details = errResp.Details
+		}
+	}
+
+	if apiErr.Code == apperror.ValidationFailed && len(details) > 0 {
+		_ = json.Unmarshal(details, &apiErr.Fields)
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = codeForStatus(resp.StatusCode)
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// codeForStatus returns the catalog code reported with status, for
+// responses that carry none
+func codeForStatus(status int) apperror.Code {
+	for _, def := range apperror.All() {
+		if def.Status == status && isGeneric(def.Code) {
+			return def.Code
+		}
+	}
+	return apperror.InternalError
+}
+
+// isGeneric reports whether code is one of the request and server errors
+// rather than a domain error
+func isGeneric(code apperror.Code) bool {
+	switch code {
+	case apperror.InvalidRequest, apperror.Unauthorized, apperror.NotFound, apperror.Conflict,
+		apperror.RequestTooLarge, apperror.NotAcceptable, apperror.UnsupportedMediaType,
+		apperror.ValidationFailed, apperror.RateLimited, apperror.InternalError,
+		apperror.ServiceUnavailable, apperror.Timeout:
+		return true
+	}
+	return false
+}
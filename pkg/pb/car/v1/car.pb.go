@@ -0,0 +1,683 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: car/v1/car.proto
+
+package carv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Car struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	EngineVersion string                 `protobuf:"bytes,3,opt,name=engine_version,json=engineVersion,proto3" json:"engine_version,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Car) Reset() {
+	*x = Car{}
+	mi := &file_car_v1_car_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Car) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Car) ProtoMessage() {}
+
+func (x *Car) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Car.ProtoReflect.Descriptor instead.
+func (*Car) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Car) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Car) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Car) GetEngineVersion() string {
+	if x != nil {
+		return x.EngineVersion
+	}
+	return ""
+}
+
+func (x *Car) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Car) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+// CarFilter selects cars; empty fields match every car.
+type CarFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cars whose name contains this text, ignoring case.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Cars with this engine version.
+	EngineVersion string `protobuf:"bytes,2,opt,name=engine_version,json=engineVersion,proto3" json:"engine_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarFilter) Reset() {
+	*x = CarFilter{}
+	mi := &file_car_v1_car_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarFilter) ProtoMessage() {}
+
+func (x *CarFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarFilter.ProtoReflect.Descriptor instead.
+func (*CarFilter) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CarFilter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CarFilter) GetEngineVersion() string {
+	if x != nil {
+		return x.EngineVersion
+	}
+	return ""
+}
+
+type GetCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCarRequest) Reset() {
+	*x = GetCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarRequest) ProtoMessage() {}
+
+func (x *GetCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarRequest.ProtoReflect.Descriptor instead.
+func (*GetCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListCarsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Page number, from 1 (default 1).
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Cars per page (default 10, at most 100).
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// One of name, engine_version or created_at (default created_at).
+	SortBy string `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// asc or desc (default desc).
+	SortDir       string     `protobuf:"bytes,4,opt,name=sort_dir,json=sortDir,proto3" json:"sort_dir,omitempty"`
+	Filter        *CarFilter `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsRequest) Reset() {
+	*x = ListCarsRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsRequest) ProtoMessage() {}
+
+func (x *ListCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCarsRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListCarsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetSortDir() string {
+	if x != nil {
+		return x.SortDir
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetFilter() *CarFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListCarsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cars          []*Car                 `protobuf:"bytes,1,rep,name=cars,proto3" json:"cars,omitempty"`
+	CurrentPage   int32                  `protobuf:"varint,2,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,4,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	TotalRecords  int64                  `protobuf:"varint,5,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsResponse) Reset() {
+	*x = ListCarsResponse{}
+	mi := &file_car_v1_car_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsResponse) ProtoMessage() {}
+
+func (x *ListCarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsResponse.ProtoReflect.Descriptor instead.
+func (*ListCarsResponse) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCarsResponse) GetCars() []*Car {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+func (x *ListCarsResponse) GetCurrentPage() int32 {
+	if x != nil {
+		return x.CurrentPage
+	}
+	return 0
+}
+
+func (x *ListCarsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCarsResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *ListCarsResponse) GetTotalRecords() int64 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+type StreamCarsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *CarFilter             `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCarsRequest) Reset() {
+	*x = StreamCarsRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCarsRequest) ProtoMessage() {}
+
+func (x *StreamCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCarsRequest.ProtoReflect.Descriptor instead.
+func (*StreamCarsRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamCarsRequest) GetFilter() *CarFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type CreateCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	EngineVersion string                 `protobuf:"bytes,2,opt,name=engine_version,json=engineVersion,proto3" json:"engine_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCarRequest) Reset() {
+	*x = CreateCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarRequest) ProtoMessage() {}
+
+func (x *CreateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCarRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCarRequest) GetEngineVersion() string {
+	if x != nil {
+		return x.EngineVersion
+	}
+	return ""
+}
+
+type UpdateCarRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty fields are kept.
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	EngineVersion string `protobuf:"bytes,3,opt,name=engine_version,json=engineVersion,proto3" json:"engine_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCarRequest) Reset() {
+	*x = UpdateCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarRequest) ProtoMessage() {}
+
+func (x *UpdateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCarRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCarRequest) GetEngineVersion() string {
+	if x != nil {
+		return x.EngineVersion
+	}
+	return ""
+}
+
+type DeleteCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCarRequest) Reset() {
+	*x = DeleteCarRequest{}
+	mi := &file_car_v1_car_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarRequest) ProtoMessage() {}
+
+func (x *DeleteCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_car_v1_car_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCarRequest) Descriptor() ([]byte, []int) {
+	return file_car_v1_car_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_car_v1_car_proto protoreflect.FileDescriptor
+
+const file_car_v1_car_proto_rawDesc = "" +
+	"\n" +
+	"\x10car/v1/car.proto\x12\x06car.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xca\x01\n" +
+	"\x03Car\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
+	"\x0eengine_version\x18\x03 \x01(\tR\rengineVersion\x12;\n" +
+	"\vcreate_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\"F\n" +
+	"\tCarFilter\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0eengine_version\x18\x02 \x01(\tR\rengineVersion\"\x1f\n" +
+	"\rGetCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa1\x01\n" +
+	"\x0fListCarsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x19\n" +
+	"\bsort_dir\x18\x04 \x01(\tR\asortDir\x12)\n" +
+	"\x06filter\x18\x05 \x01(\v2\x11.car.v1.CarFilterR\x06filter\"\xb9\x01\n" +
+	"\x10ListCarsResponse\x12\x1f\n" +
+	"\x04cars\x18\x01 \x03(\v2\v.car.v1.CarR\x04cars\x12!\n" +
+	"\fcurrent_page\x18\x02 \x01(\x05R\vcurrentPage\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1f\n" +
+	"\vtotal_pages\x18\x04 \x01(\x05R\n" +
+	"totalPages\x12#\n" +
+	"\rtotal_records\x18\x05 \x01(\x03R\ftotalRecords\">\n" +
+	"\x11StreamCarsRequest\x12)\n" +
+	"\x06filter\x18\x01 \x01(\v2\x11.car.v1.CarFilterR\x06filter\"M\n" +
+	"\x10CreateCarRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0eengine_version\x18\x02 \x01(\tR\rengineVersion\"]\n" +
+	"\x10UpdateCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
+	"\x0eengine_version\x18\x03 \x01(\tR\rengineVersion\"\"\n" +
+	"\x10DeleteCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xd8\x02\n" +
+	"\n" +
+	"CarService\x12,\n" +
+	"\x06GetCar\x12\x15.car.v1.GetCarRequest\x1a\v.car.v1.Car\x12=\n" +
+	"\bListCars\x12\x17.car.v1.ListCarsRequest\x1a\x18.car.v1.ListCarsResponse\x126\n" +
+	"\n" +
+	"StreamCars\x12\x19.car.v1.StreamCarsRequest\x1a\v.car.v1.Car0\x01\x122\n" +
+	"\tCreateCar\x12\x18.car.v1.CreateCarRequest\x1a\v.car.v1.Car\x122\n" +
+	"\tUpdateCar\x12\x18.car.v1.UpdateCarRequest\x1a\v.car.v1.Car\x12=\n" +
+	"\tDeleteCar\x12\x18.car.v1.DeleteCarRequest\x1a\x16.google.protobuf.EmptyB$Z\"project-simple/pkg/pb/car/v1;carv1b\x06proto3"
+
+var (
+	file_car_v1_car_proto_rawDescOnce sync.Once
+	file_car_v1_car_proto_rawDescData []byte
+)
+
+func file_car_v1_car_proto_rawDescGZIP() []byte {
+	file_car_v1_car_proto_rawDescOnce.Do(func() {
+		file_car_v1_car_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_car_v1_car_proto_rawDesc), len(file_car_v1_car_proto_rawDesc)))
+	})
+	return file_car_v1_car_proto_rawDescData
+}
+
+var file_car_v1_car_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_car_v1_car_proto_goTypes = []any{
+	(*Car)(nil),                   // 0: car.v1.Car
+	(*CarFilter)(nil),             // 1: car.v1.CarFilter
+	(*GetCarRequest)(nil),         // 2: car.v1.GetCarRequest
+	(*ListCarsRequest)(nil),       // 3: car.v1.ListCarsRequest
+	(*ListCarsResponse)(nil),      // 4: car.v1.ListCarsResponse
+	(*StreamCarsRequest)(nil),     // 5: car.v1.StreamCarsRequest
+	(*CreateCarRequest)(nil),      // 6: car.v1.CreateCarRequest
+	(*UpdateCarRequest)(nil),      // 7: car.v1.UpdateCarRequest
+	(*DeleteCarRequest)(nil),      // 8: car.v1.DeleteCarRequest
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 10: google.protobuf.Empty
+}
+var file_car_v1_car_proto_depIdxs = []int32{
+	9,  // 0: car.v1.Car.create_time:type_name -> google.protobuf.Timestamp
+	9,  // 1: car.v1.Car.update_time:type_name -> google.protobuf.Timestamp
+	1,  // 2: car.v1.ListCarsRequest.filter:type_name -> car.v1.CarFilter
+	0,  // 3: car.v1.ListCarsResponse.cars:type_name -> car.v1.Car
+	1,  // 4: car.v1.StreamCarsRequest.filter:type_name -> car.v1.CarFilter
+	2,  // 5: car.v1.CarService.GetCar:input_type -> car.v1.GetCarRequest
+	3,  // 6: car.v1.CarService.ListCars:input_type -> car.v1.ListCarsRequest
+	5,  // 7: car.v1.CarService.StreamCars:input_type -> car.v1.StreamCarsRequest
+	6,  // 8: car.v1.CarService.CreateCar:input_type -> car.v1.CreateCarRequest
+	7,  // 9: car.v1.CarService.UpdateCar:input_type -> car.v1.UpdateCarRequest
+	8,  // 10: car.v1.CarService.DeleteCar:input_type -> car.v1.DeleteCarRequest
+	0,  // 11: car.v1.CarService.GetCar:output_type -> car.v1.Car
+	4,  // 12: car.v1.CarService.ListCars:output_type -> car.v1.ListCarsResponse
+	0,  // 13: car.v1.CarService.StreamCars:output_type -> car.v1.Car
+	0,  // 14: car.v1.CarService.CreateCar:output_type -> car.v1.Car
+	0,  // 15: car.v1.CarService.UpdateCar:output_type -> car.v1.Car
+	10, // 16: car.v1.CarService.DeleteCar:output_type -> google.protobuf.Empty
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_car_v1_car_proto_init() }
+func file_car_v1_car_proto_init() {
+	if File_car_v1_car_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_car_v1_car_proto_rawDesc), len(file_car_v1_car_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_car_v1_car_proto_goTypes,
+		DependencyIndexes: file_car_v1_car_proto_depIdxs,
+		MessageInfos:      file_car_v1_car_proto_msgTypes,
+	}.Build()
+	File_car_v1_car_proto = out.File
+	file_car_v1_car_proto_goTypes = nil
+	file_car_v1_car_proto_depIdxs = nil
+}
@@ -0,0 +1,267 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Bind decodes the request body into obj by its Content-Type and validates
+// it. Bodies are accepted in the response formats: XML, CSV and NDJSON are
+// converted to JSON and bound by json tags, using obj's field types to tell
+// numbers and booleans from strings. A missing or unknown type is read as
+// JSON, as before other formats were accepted.
+func Bind(c *gin.Context, obj interface{}) error {
+	mediaType := c.ContentType()
+
+	switch {
+	case FormatMsgPack.accepts(mediaType):
+		return c.ShouldBindWith(obj, binding.MsgPack)
+	case FormatXML.accepts(mediaType), FormatCSV.accepts(mediaType), FormatNDJSON.accepts(mediaType):
+	default:
+		return c.ShouldBindJSON(obj)
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	var model interface{}
+	switch {
+	case FormatXML.accepts(mediaType):
+		model, err = decodeXML(body, reflect.TypeOf(obj))
+	case FormatCSV.accepts(mediaType):
+		model, err = decodeCSV(body, reflect.TypeOf(obj))
+	default:
+		model, err = decodeNDJSON(body)
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+	return binding.JSON.BindBody(data, obj)
+}
+
+func (f Format) accepts(mediaType string) bool {
+	for _, t := range f.MediaTypes {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeNDJSON reads a body of exactly one JSON line
+func decodeNDJSON(body []byte) (interface{}, error) {
+	var lines [][]byte
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("expected exactly one NDJSON record, got %d", len(lines))
+	}
+	return json.RawMessage(lines[0]), nil
+}
+
+// decodeCSV reads a header row and exactly one record. Dotted columns become
+// nested objects and empty cells are left out.
+func decodeCSV(body []byte, t reflect.Type) (interface{}, error) {
+	rows, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != 2 {
+		return nil, fmt.Errorf("expected a header and exactly one CSV record, got %d rows", len(rows))
+	}
+
+	obj := object{}
+	for i, column := range rows[0] {
+		cell := rows[1][i]
+		if cell == "" {
+			continue
+		}
+		obj = setPath(obj, strings.Split(column, "."), cell, t)
+	}
+	return obj, nil
+}
+
+func setPath(obj object, path []string, cell string, t reflect.Type) object {
+	key := path[0]
+	fieldType := fieldTypeOf(t, key)
+
+	if len(path) == 1 {
+		return append(obj, member{key: key, value: coerce(cell, fieldType)})
+	}
+
+	for i, m := range obj {
+		if child, ok := m.value.(object); ok && m.key == key {
+			obj[i].value = setPath(child, path[1:], cell, fieldType)
+			return obj
+		}
+	}
+	return append(obj, member{key: key, value: setPath(object{}, path[1:], cell, fieldType)})
+}
+
+type xmlNode struct {
+	name     string
+	text     strings.Builder
+	children []*xmlNode
+}
+
+// decodeXML reads the element layout encodeXML writes: the root's children
+// are the fields and item elements are array entries
+func decodeXML(body []byte, t reflect.Type) (interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	var stack []*xmlNode
+	var root *xmlNode
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: tok.Name.Local}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(tok)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("empty XML document")
+	}
+	return xmlValue(root, t), nil
+}
+
+func xmlValue(node *xmlNode, t reflect.Type) interface{} {
+	t = indirect(t)
+
+	if t != nil && t.Kind() == reflect.Slice {
+		arr := []interface{}{}
+		for _, child := range node.children {
+			arr = append(arr, xmlValue(child, t.Elem()))
+		}
+		return arr
+	}
+
+	if len(node.children) == 0 {
+		if t != nil && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map) && strings.TrimSpace(node.text.String()) == "" {
+			return object{}
+		}
+		return coerce(node.text.String(), t)
+	}
+
+	if t == nil && allItems(node.children) {
+		arr := []interface{}{}
+		for _, child := range node.children {
+			arr = append(arr, xmlValue(child, nil))
+		}
+		return arr
+	}
+
+	obj := object{}
+	for _, child := range node.children {
+		obj = append(obj, member{key: child.name, value: xmlValue(child, fieldTypeOf(t, child.name))})
+	}
+	return obj
+}
+
+func allItems(nodes []*xmlNode) bool {
+	for _, n := range nodes {
+		if n.name != "item" {
+			return false
+		}
+	}
+	return true
+}
+
+// coerce turns text into the JSON value the target type expects. Text that
+// does not parse is kept as a string so binding reports the mismatch.
+func coerce(text string, t reflect.Type) interface{} {
+	t = indirect(t)
+	if t == nil {
+		return text
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return text
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+			return json.Number(strings.TrimSpace(text))
+		}
+	case reflect.Slice, reflect.Map, reflect.Struct, reflect.Interface:
+		if model, err := parseModel([]byte(text)); err == nil {
+			return model
+		}
+	}
+	return text
+}
+
+// fieldTypeOf returns the type of the field t decodes key into, or nil when
+// unknown
+func fieldTypeOf(t reflect.Type, key string) reflect.Type {
+	t = indirect(t)
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == key || name == "" && strings.EqualFold(field.Name, key) {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,102 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+type testRequest struct {
+	Name   string   `json:"name" binding:"required,min=2"`
+	Year   int      `json:"year"`
+	Active *bool    `json:"active"`
+	Tags   []string `json:"tags"`
+}
+
+func TestBind(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bind := func(contentType string, body []byte) (testRequest, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/cars", bytes.NewReader(body))
+		if contentType != "" {
+			c.Request.Header.Set("Content-Type", contentType)
+		}
+		var req testRequest
+		err := Bind(c, &req)
+		return req, err
+	}
+	active := true
+	want := testRequest{Name: "Civic", Year: 2020, Active: &active, Tags: []string{"red", "sport"}}
+
+	t.Run("Success - JSON, also without a content type", func(t *testing.T) {
+		body := []byte(`{"name":"Civic","year":2020,"active":true,"tags":["red","sport"]}`)
+		for _, contentType := range []string{"application/json", ""} {
+			req, err := bind(contentType, body)
+			require.NoError(t, err)
+			assert.Equal(t, want, req)
+		}
+	})
+
+	t.Run("Success - XML in the layout responses use", func(t *testing.T) {
+		req, err := bind("application/xml", []byte(
+			`<car><name>Civic</name><year>2020</year><active>true</active><tags><item>red</item><item>sport</item></tags></car>`))
+
+		require.NoError(t, err)
+		assert.Equal(t, want, req)
+	})
+
+	t.Run("Success - CSV with a header and one record", func(t *testing.T) {
+		req, err := bind("text/csv", []byte("name,year,active,tags\nCivic,2020,true,\"[\"\"red\"\",\"\"sport\"\"]\"\n"))
+
+		require.NoError(t, err)
+		assert.Equal(t, want, req)
+	})
+
+	t.Run("Success - NDJSON with one line", func(t *testing.T) {
+		req, err := bind("application/x-ndjson", []byte(`{"name":"Civic","year":2020,"active":true,"tags":["red","sport"]}`+"\n"))
+
+		require.NoError(t, err)
+		assert.Equal(t, want, req)
+	})
+
+	t.Run("Success - MessagePack", func(t *testing.T) {
+		var body []byte
+		require.NoError(t, codec.NewEncoderBytes(&body, &codec.MsgpackHandle{}).Encode(map[string]interface{}{
+			"name": "Civic", "year": 2020, "active": true, "tags": []string{"red", "sport"},
+		}))
+
+		req, err := bind("application/msgpack", body)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, req)
+	})
+
+	t.Run("Should validate decoded bodies", func(t *testing.T) {
+		_, err := bind("text/csv", []byte("name\nX\n"))
+
+		var validationErrs validator.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+	})
+
+	t.Run("Should reject bodies with several records", func(t *testing.T) {
+		_, err := bind("text/csv", []byte("name\nCivic\nGolf\n"))
+		assert.Error(t, err)
+
+		_, err = bind("application/x-ndjson", []byte("{\"name\":\"Civic\"}\n{\"name\":\"Golf\"}\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Should report values of the wrong type", func(t *testing.T) {
+		_, err := bind("application/xml", []byte(`<car><name>Civic</name><year>soon</year></car>`))
+
+		assert.Error(t, err)
+	})
+}
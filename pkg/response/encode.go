@@ -0,0 +1,321 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+)
+
+// Paged is implemented by list responses. CSV and NDJSON write one record per
+// item and move the page metadata to the X-Total-Count and Link headers.
+type Paged interface {
+	Items() interface{}
+	PageInfo() PageInfo
+}
+
+// PageInfo describes the page a Paged response holds
+type PageInfo struct {
+	Page         int
+	PageSize     int
+	TotalPages   int
+	TotalRecords int64
+}
+
+// render writes a successful response in the negotiated format. JSON, XML and
+// MessagePack carry the message/data envelope; CSV and NDJSON carry only the
+// records of data.
+func render(c *gin.Context, status int, message string, data interface{}) {
+	// Without the ContentNegotiation middleware nothing has answered 406
+	// yet, so fall back to JSON
+	n, ok := negotiation(c)
+	if !ok {
+		n = negotiated{FormatJSON, FormatJSON.MediaTypes[0]}
+	}
+	c.Writer.Header().Add("Vary", "Accept")
+
+	if n.format.Name == FormatJSON.Name {
+		c.JSON(status, Response{Message: message, Data: data})
+		return
+	}
+
+	var (
+		body []byte
+		err  error
+	)
+	switch n.format.Name {
+	case FormatXML.Name:
+		body, err = encodeXML(Response{Message: message, Data: data})
+	case FormatMsgPack.Name:
+		body, err = encodeMsgPack(Response{Message: message, Data: data})
+	case FormatCSV.Name:
+		setPageHeaders(c, data)
+		body, err = encodeCSV(records(data))
+	case FormatNDJSON.Name:
+		setPageHeaders(c, data)
+		body, err = encodeNDJSON(records(data))
+	}
+	if err != nil {
+		_ = c.Error(fmt.Errorf("encode %s response: %w", n.format.Name, err))
+		InternalServerError(c, "Failed to encode the response")
+		return
+	}
+
+	contentType := n.mediaType
+	if n.format.Name == FormatXML.Name || n.format.Name == FormatCSV.Name {
+		contentType += "; charset=utf-8"
+	}
+	c.Data(status, contentType, body)
+}
+
+// records returns the items of a list, or data itself as the only record
+func records(data interface{}) []interface{} {
+	if p, ok := data.(Paged); ok {
+		data = p.Items()
+	}
+
+	rv := reflect.ValueOf(data)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return items
+	default:
+		return []interface{}{data}
+	}
+}
+
+// setPageHeaders exposes the page metadata of a Paged response, which record
+// formats have nowhere else to put
+func setPageHeaders(c *gin.Context, data interface{}) {
+	p, ok := data.(Paged)
+	if !ok {
+		return
+	}
+	info := p.PageInfo()
+	c.Header("X-Total-Count", strconv.FormatInt(info.TotalRecords, 10))
+
+	link := func(page int, rel string) string {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		u.RawQuery = query.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+	}
+	links := []string{link(1, "first")}
+	if info.Page > 1 {
+		links = append(links, link(info.Page-1, "prev"))
+	}
+	if info.Page < info.TotalPages {
+		links = append(links, link(info.Page+1, "next"))
+	}
+	if info.TotalPages > 0 {
+		links = append(links, link(info.TotalPages, "last"))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+func encodeXML(v interface{}) ([]byte, error) {
+	model, err := toModel(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := writeXML(enc, "response", model); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXML writes objects as child elements named by key and arrays as
+// repeated item elements
+func writeXML(enc *xml.Encoder, name string, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case object:
+		for _, m := range v {
+			if err := writeXML(enc, m.key, m.value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := writeXML(enc, "item", item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(scalarString(v))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlName makes a JSON key a valid XML element name
+func xmlName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+		case i > 0 && (r == '-' || r == '.' || '0' <= r && r <= '9'):
+		case i == 0 && (r == '-' || r == '.' || '0' <= r && r <= '9'):
+			b.WriteByte('_')
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+func encodeMsgPack(v interface{}) ([]byte, error) {
+	model, err := toModel(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	var handle codec.MsgpackHandle
+	handle.WriteExt = true
+	if err := codec.NewEncoderBytes(&body, &handle).Encode(plain(model)); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// encodeCSV writes one row per record with nested objects flattened into
+// dotted columns and arrays as JSON. Columns are the union of all records' in
+// order of first appearance.
+func encodeCSV(records []interface{}) ([]byte, error) {
+	var columns []string
+	seen := map[string]bool{}
+	rows := make([]map[string]string, 0, len(records))
+
+	for _, record := range records {
+		model, err := toModel(record)
+		if err != nil {
+			return nil, err
+		}
+		row := map[string]string{}
+		if err := flatten("", model, row); err != nil {
+			return nil, err
+		}
+		// Map iteration is unordered, so collect columns in model order
+		for _, column := range flatColumns("", model) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if len(columns) > 0 {
+		if err := w.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = row[column]
+		}
+		if err := w.Write(cells); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func flatten(prefix string, v interface{}, row map[string]string) error {
+	switch v := v.(type) {
+	case object:
+		for _, m := range v {
+			if err := flatten(columnName(prefix, m.key), m.value, row); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		row[columnName(prefix, "")] = string(data)
+	default:
+		row[columnName(prefix, "")] = scalarString(v)
+	}
+	return nil
+}
+
+func flatColumns(prefix string, v interface{}) []string {
+	obj, ok := v.(object)
+	if !ok {
+		return []string{columnName(prefix, "")}
+	}
+	var columns []string
+	for _, m := range obj {
+		columns = append(columns, flatColumns(columnName(prefix, m.key), m.value)...)
+	}
+	return columns
+}
+
+// columnName joins nested keys with dots; a bare scalar record is "value"
+func columnName(prefix, key string) string {
+	switch {
+	case prefix == "" && key == "":
+		return "value"
+	case prefix == "":
+		return key
+	case key == "":
+		return prefix
+	default:
+		return prefix + "." + key
+	}
+}
+
+func encodeNDJSON(records []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
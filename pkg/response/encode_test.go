@@ -0,0 +1,157 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+type testCar struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Year    int       `json:"year"`
+	Owner   testOwner `json:"owner"`
+	Tags    []string  `json:"tags,omitempty"`
+	Comment *string   `json:"comment"`
+}
+
+type testOwner struct {
+	Name string `json:"name"`
+}
+
+type testPage struct {
+	Data []testCar `json:"data"`
+	Info PageInfo  `json:"-"`
+}
+
+func (p testPage) Items() interface{} { return p.Data }
+func (p testPage) PageInfo() PageInfo { return p.Info }
+
+var testCars = []testCar{
+	{ID: "1", Name: "Civic, Sport", Year: 2020, Owner: testOwner{Name: "Ana"}, Tags: []string{"red"}},
+	{ID: "2", Name: "Golf", Year: 2021, Owner: testOwner{Name: "Jörg"}},
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept    string
+		mediaType string
+		ok        bool
+	}{
+		{"", "application/json", true},
+		{"*/*", "application/json", true},
+		{"text/csv", "text/csv", true},
+		{"application/x-ndjson", "application/x-ndjson", true},
+		{"text/xml", "text/xml", true},
+		{"application/x-msgpack", "application/x-msgpack", true},
+		{"text/html, text/csv;q=0.5, application/json;q=0.9", "application/json", true},
+		{"text/*", "text/xml", true},
+		{"*/*, application/json;q=0", "application/xml", true},
+		{"application/problem+json", "application/json", true},
+		{"application/problem+json;q=0.5, text/csv", "text/csv", true},
+		{"application/pdf", "", false},
+		{"text/csv;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		n, ok := negotiate(tt.accept)
+		assert.Equal(t, tt.ok, ok, tt.accept)
+		assert.Equal(t, tt.mediaType, n.mediaType, tt.accept)
+	}
+}
+
+func TestRender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	respond := func(accept string, data interface{}) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/cars?page=2&page_size=2", nil)
+		c.Request.Header.Set("Accept", accept)
+		Success(c, "Cars retrieved successfully", data)
+		return w
+	}
+	page := testPage{Data: testCars, Info: PageInfo{Page: 2, PageSize: 2, TotalPages: 3, TotalRecords: 6}}
+
+	t.Run("Success - CSV flattens records and moves paging to headers", func(t *testing.T) {
+		w := respond("text/csv", page)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "6", w.Header().Get("X-Total-Count"))
+		assert.Contains(t, w.Header().Get("Link"), `</api/v1/cars?page=3&page_size=2>; rel="next"`)
+		assert.Contains(t, w.Header().Get("Link"), `</api/v1/cars?page=1&page_size=2>; rel="prev"`)
+		assert.Contains(t, w.Header().Values("Vary"), "Accept")
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "name", "year", "owner.name", "tags", "comment"},
+			{"1", "Civic, Sport", "2020", "Ana", `["red"]`, ""},
+			{"2", "Golf", "2021", "Jörg", "", ""},
+		}, rows)
+	})
+
+	t.Run("Success - NDJSON writes one object per line", func(t *testing.T) {
+		w := respond("application/x-ndjson", page)
+
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		var car testCar
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &car))
+		assert.Equal(t, testCars[1], car)
+	})
+
+	t.Run("Success - a single object is one record", func(t *testing.T) {
+		w := respond("application/x-ndjson", testCars[0])
+
+		assert.Equal(t, 1, strings.Count(w.Body.String(), "\n"))
+		assert.Empty(t, w.Header().Get("X-Total-Count"))
+	})
+
+	t.Run("Success - XML mirrors the JSON envelope", func(t *testing.T) {
+		w := respond("application/xml", []testCar{testCars[0]})
+
+		assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<response><message>Cars retrieved successfully</message><data><item>`+
+			`<id>1</id><name>Civic, Sport</name><year>2020</year><owner><name>Ana</name></owner>`+
+			`<tags><item>red</item></tags><comment></comment></item></data></response>`, w.Body.String())
+	})
+
+	t.Run("Success - MessagePack mirrors the JSON envelope", func(t *testing.T) {
+		w := respond("application/msgpack", testCars[0])
+
+		assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+		var decoded map[string]interface{}
+		handle := codec.MsgpackHandle{}
+		handle.RawToString = true
+		require.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &handle).Decode(&decoded))
+		assert.Equal(t, "Cars retrieved successfully", decoded["message"])
+		data := decoded["data"].(map[interface{}]interface{})
+		assert.Equal(t, "Civic, Sport", data["name"])
+		assert.EqualValues(t, 2020, data["year"])
+	})
+
+	t.Run("Should fall back to JSON without the middleware", func(t *testing.T) {
+		w := respond("application/pdf", testCars[0])
+
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+}
+
+func TestXMLName(t *testing.T) {
+	assert.Equal(t, "engine_version", xmlName("engine_version"))
+	assert.Equal(t, "_1.0", xmlName("1.0"))
+	assert.Equal(t, "a_b", xmlName("a b"))
+	assert.Equal(t, "_", xmlName(""))
+}
@@ -2,12 +2,16 @@ package response
 
 import (
 	"net/http"
+	"project-simple/pkg/apperror"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ErrorResponse is the default error body. Code is the catalog code clients
+// should match on; Message is for humans and may change.
 type ErrorResponse struct {
 	Error   string      `json:"error"`
+	Code    string      `json:"code,omitempty" example:"CAR_NOT_FOUND"`
 	Message string      `json:"message"`
 	Details interface{} `json:"details,omitempty"`
 }
@@ -18,47 +22,39 @@ type ValidationError struct {
 }
 
 func BadRequest(c *gin.Context, message string, details interface{}) {
-	c.JSON(http.StatusBadRequest, ErrorResponse{
-		Error:   "Bad Request",
-		Message: message,
-		Details: details,
-	})
+	Fail(c, apperror.InvalidRequest, message, details)
 }
 
 func NotFound(c *gin.Context, message string) {
-	c.JSON(http.StatusNotFound, ErrorResponse{
-		Error:   "Not Found",
-		Message: message,
-	})
+	Fail(c, apperror.NotFound, message, nil)
 }
 
 func InternalServerError(c *gin.Context, message string) {
-	c.JSON(http.StatusInternalServerError, ErrorResponse{
-		Error:   "Internal Server Error",
-		Message: message,
-	})
+	Fail(c, apperror.InternalError, message, nil)
+}
+
+func ServiceUnavailable(c *gin.Context, message string) {
+	Fail(c, apperror.ServiceUnavailable, message, nil)
+}
+
+func GatewayTimeout(c *gin.Context, message string) {
+	Fail(c, apperror.Timeout, message, nil)
 }
 
 func Conflict(c *gin.Context, message string) {
-	c.JSON(http.StatusConflict, ErrorResponse{
-		Error:   "Conflict",
-		Message: message,
-	})
+	Fail(c, apperror.Conflict, message, nil)
 }
 
 func UnprocessableEntity(c *gin.Context, message string, details interface{}) {
-	c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-		Error:   "Unprocessable Entity",
-		Message: message,
-		Details: details,
-	})
+	Fail(c, apperror.ValidationFailed, message, details)
 }
 
 func Created(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusCreated, Response{
-		Message: message,
-		Data:    data,
-	})
+	render(c, http.StatusCreated, message, data)
+}
+
+func Accepted(c *gin.Context, message string, data interface{}) {
+	render(c, http.StatusAccepted, message, data)
 }
 
 func NoContent(c *gin.Context) {
@@ -0,0 +1,155 @@
+package response
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Format is a representation responses can be written in
+type Format struct {
+	Name string
+	// MediaTypes are the types that select the format; the first is sent as
+	// Content-Type when the client accepted a wildcard
+	MediaTypes []string
+}
+
+var (
+	FormatJSON    = Format{Name: "json", MediaTypes: []string{"application/json"}}
+	FormatXML     = Format{Name: "xml", MediaTypes: []string{"application/xml", "text/xml"}}
+	FormatCSV     = Format{Name: "csv", MediaTypes: []string{"text/csv"}}
+	FormatNDJSON  = Format{Name: "ndjson", MediaTypes: []string{"application/x-ndjson", "application/jsonl"}}
+	FormatMsgPack = Format{Name: "msgpack", MediaTypes: []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}}
+)
+
+// Formats lists the supported formats in order of server preference
+var Formats = []Format{FormatJSON, FormatXML, FormatCSV, FormatNDJSON, FormatMsgPack}
+
+// negotiatedKey holds the negotiated format and media type in the gin context
+const negotiatedKey = "response_format"
+
+type negotiated struct {
+	format    Format
+	mediaType string
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// Negotiate picks the format for the request's Accept header and remembers it
+// for the response helpers. A missing header, or one accepting problem
+// details, accepts JSON. It returns false when the client accepts none of
+// Formats.
+func Negotiate(c *gin.Context) (Format, bool) {
+	n, ok := negotiation(c)
+	return n.format, ok
+}
+
+func negotiation(c *gin.Context) (negotiated, bool) {
+	if n, ok := c.Get(negotiatedKey); ok {
+		return n.(negotiated), true
+	}
+
+	n, ok := negotiate(c.GetHeader("Accept"))
+	if ok {
+		c.Set(negotiatedKey, n)
+	}
+	return n, ok
+}
+
+func negotiate(accept string) (negotiated, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return negotiated{FormatJSON, FormatJSON.MediaTypes[0]}, true
+	}
+
+	ranges := parseAccept(accept)
+	for _, r := range ranges {
+		if r.quality <= 0 {
+			continue
+		}
+		// A client accepting problem details reads JSON: errors are sent as
+		// problem details and everything else as plain JSON
+		if r.mediaType == ProblemContentType {
+			r.mediaType = FormatJSON.MediaTypes[0]
+		}
+		for _, f := range Formats {
+			if mediaType, ok := f.match(r.mediaType); ok && !refused(ranges, mediaType) {
+				return negotiated{f, mediaType}, true
+			}
+		}
+	}
+	return negotiated{}, false
+}
+
+// match reports whether a media range selects the format, and the media type
+// to answer with
+func (f Format) match(mediaRange string) (string, bool) {
+	switch {
+	case mediaRange == "*/*":
+		return f.MediaTypes[0], true
+	case strings.HasSuffix(mediaRange, "/*"):
+		for _, t := range f.MediaTypes {
+			if strings.HasPrefix(t, strings.TrimSuffix(mediaRange, "*")) {
+				return t, true
+			}
+		}
+	default:
+		for _, t := range f.MediaTypes {
+			if t == mediaRange {
+				return t, true
+			}
+		}
+	}
+	return "", false
+}
+
+// refused reports whether the client excluded mediaType with q=0
+func refused(ranges []mediaRange, mediaType string) bool {
+	for _, r := range ranges {
+		if r.mediaType == mediaType && r.quality <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccept returns the media ranges of an Accept header, most preferred
+// first: by quality, then specific types before wildcards, then header order
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].quality != ranges[j].quality {
+			return ranges[i].quality > ranges[j].quality
+		}
+		return strings.Count(ranges[i].mediaType, "*") < strings.Count(ranges[j].mediaType, "*")
+	})
+	return ranges
+}
+
+// SupportedMediaTypes lists every media type of Formats, for 406 responses
+func SupportedMediaTypes() []string {
+	var types []string
+	for _, f := range Formats {
+		types = append(types, f.MediaTypes...)
+	}
+	return types
+}
@@ -0,0 +1,77 @@
+package response
+
+import (
+	"net/http"
+	"project-simple/pkg/apperror"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix prefixes the slug of a code to form the problem type URI
+const ProblemTypePrefix = "urn:carapi:problem:"
+
+// Problem is an RFC 9457 problem details object. Code is the catalog code
+// and Errors lists field errors when validation failed.
+type Problem struct {
+	Type     string      `json:"type" example:"urn:carapi:problem:car-not-found"`
+	Title    string      `json:"title" example:"Car not found"`
+	Status   int         `json:"status" example:"404"`
+	Detail   string      `json:"detail,omitempty" example:"No car matches the given ID"`
+	Instance string      `json:"instance,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Code     string      `json:"code" example:"CAR_NOT_FOUND"`
+	Errors   interface{} `json:"errors,omitempty"`
+}
+
+var problemDetailsByDefault atomic.Bool
+
+// SetProblemDetailsDefault makes problem details the error format for clients
+// that do not ask for it. Clients that accept application/problem+json always
+// get it.
+func SetProblemDetailsDefault(enabled bool) {
+	problemDetailsByDefault.Store(enabled)
+}
+
+func wantsProblem(c *gin.Context) bool {
+	return problemDetailsByDefault.Load() || strings.Contains(c.GetHeader("Accept"), ProblemContentType)
+}
+
+// Fail responds with the error identified by code, in the format the client
+// asked for. detail describes this occurrence and defaults to the code's
+// title; details carries structured data such as field errors.
+func Fail(c *gin.Context, code apperror.Code, detail string, details interface{}) {
+	def := apperror.Lookup(code)
+
+	if !wantsProblem(c) {
+		message := detail
+		if message == "" {
+			message = def.Title
+		}
+		c.JSON(def.Status, ErrorResponse{
+			Error:   http.StatusText(def.Status),
+			Code:    string(def.Code),
+			Message: message,
+			Details: details,
+		})
+		return
+	}
+
+	if detail == def.Title {
+		detail = ""
+	}
+	// gin only sets the JSON content type when none is set yet
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(def.Status, Problem{
+		Type:     ProblemTypePrefix + def.Code.Slug(),
+		Title:    def.Title,
+		Status:   def.Status,
+		Detail:   detail,
+		Instance: c.GetString("request_id"),
+		Code:     string(def.Code),
+		Errors:   details,
+	})
+}
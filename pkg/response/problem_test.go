@@ -0,0 +1,76 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project-simple/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fail := func(accept string, code apperror.Code, detail string, details interface{}) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/cars/1", nil)
+		if accept != "" {
+			c.Request.Header.Set("Accept", accept)
+		}
+		c.Set("request_id", "req-123")
+		Fail(c, code, detail, details)
+		return w
+	}
+
+	t.Run("Should respond with the legacy body by default", func(t *testing.T) {
+		w := fail("application/json", apperror.CarNotFound, "", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		var body ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, ErrorResponse{Error: "Not Found", Code: "CAR_NOT_FOUND", Message: "Car not found"}, body)
+	})
+
+	t.Run("Should respond with problem details when asked for", func(t *testing.T) {
+		details := []ValidationError{{Field: "year", Message: "year must be at least 1900"}}
+		w := fail("application/problem+json", apperror.ValidationFailed, "The car is invalid", details)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "urn:carapi:problem:validation-failed", body["type"])
+		assert.Equal(t, "Validation failed", body["title"])
+		assert.Equal(t, float64(http.StatusUnprocessableEntity), body["status"])
+		assert.Equal(t, "The car is invalid", body["detail"])
+		assert.Equal(t, "req-123", body["instance"])
+		assert.Equal(t, "VALIDATION_FAILED", body["code"])
+		assert.Len(t, body["errors"], 1)
+	})
+
+	t.Run("Should omit a detail that repeats the title", func(t *testing.T) {
+		w := fail(ProblemContentType, apperror.CarNotFound, "Car not found", nil)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.NotContains(t, body, "detail")
+		assert.NotContains(t, body, "errors")
+	})
+
+	t.Run("Should default to problem details when configured", func(t *testing.T) {
+		SetProblemDetailsDefault(true)
+		t.Cleanup(func() { SetProblemDetailsDefault(false) })
+
+		w := fail("", apperror.RateLimited, "", nil)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	})
+}
@@ -11,11 +11,9 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Success responds 200 in the format the client negotiated
 func Success(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, Response{
-		Message: message,
-		Data:    data,
-	})
+	render(c, http.StatusOK, message, data)
 }
 
 func Error(c *gin.Context, statusCode int, message string) {
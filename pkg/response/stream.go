@@ -0,0 +1,76 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RecordWriter writes records one at a time, for bodies too large to build
+// in memory. Records are laid out as in CSV and NDJSON responses.
+type RecordWriter interface {
+	Write(record interface{}) error
+	// Flush writes buffered records to the underlying writer
+	Flush() error
+}
+
+// NewRecordWriter returns a RecordWriter for FormatCSV or FormatNDJSON
+func NewRecordWriter(w io.Writer, f Format) (RecordWriter, error) {
+	switch f.Name {
+	case FormatCSV.Name:
+		return &csvRecordWriter{w: csv.NewWriter(w)}, nil
+	case FormatNDJSON.Name:
+		return &ndjsonRecordWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("%s is not a record format", f.Name)
+	}
+}
+
+// csvRecordWriter takes its columns from the first record; later records
+// should have the same shape, as columns they add are dropped
+type csvRecordWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (rw *csvRecordWriter) Write(record interface{}) error {
+	model, err := toModel(record)
+	if err != nil {
+		return err
+	}
+	row := map[string]string{}
+	if err := flatten("", model, row); err != nil {
+		return err
+	}
+
+	if rw.columns == nil {
+		rw.columns = flatColumns("", model)
+		if err := rw.w.Write(rw.columns); err != nil {
+			return err
+		}
+	}
+
+	cells := make([]string, len(rw.columns))
+	for i, column := range rw.columns {
+		cells[i] = row[column]
+	}
+	return rw.w.Write(cells)
+}
+
+func (rw *csvRecordWriter) Flush() error {
+	rw.w.Flush()
+	return rw.w.Error()
+}
+
+type ndjsonRecordWriter struct {
+	enc *json.Encoder
+}
+
+func (rw *ndjsonRecordWriter) Write(record interface{}) error {
+	return rw.enc.Encode(record)
+}
+
+func (rw *ndjsonRecordWriter) Flush() error {
+	return nil
+}